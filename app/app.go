@@ -2,11 +2,16 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
-	"syscall"
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/yottta/go-core/logging"
 	"github.com/yottta/go-core/shutdown"
 )
 
@@ -21,102 +26,599 @@ type Component interface {
 	Stop() error
 }
 
+// StarterContext can be implemented by a [Component] when it needs the context of the app on start (ie: to start
+// background work that ends with the app). When implemented, StartContext is called instead of [Component.Start].
+// The context given is the one returned by [App.Context].
+// When a start timeout is configured, with [WithStartTimeout] or [StartTimeout], the context carries its deadline
+// instead and it is cancelled once StartContext returns, so the work outliving it should not be bound to it.
+type StarterContext interface {
+	StartContext(ctx context.Context) error
+}
+
+// StopperContext can be implemented by a [Component] when it needs to bound its cleanup. When implemented,
+// StopContext is called instead of [Component.Stop].
+// The context given is shared by the cleanup of all the components and it's done once the stop timeout configured
+// with [WithStopTimeout] elapses, the moment when [App.Stop] gives up waiting for the cleanup.
+type StopperContext interface {
+	StopContext(ctx context.Context) error
+}
+
 type App struct {
-	components []Component
+	// components holds the registered components.
+	components  []Component
+	componentsM sync.Mutex
+	// names holds the names of the registered components and of the ones being started.
+	names map[string]struct{}
+	// timings holds the durations of the start and stop of the components, as returned by [App.StartupReport].
+	timings   []ComponentTiming
+	createdAt time.Time
+	// batches holds for each of the components the registration call that added it, used by [WithParallelStop].
+	batches   []int
+	lastBatch int
+	// phases holds for each of the components the index of its group in groupNames, the first one being the
+	// default group of the components registered directly on the app.
+	phases     []int
+	groupNames []string
+	// lastPhase is the latest group that started, the earlier groups being closed to new components.
+	lastPhase int
+	readyFns  []func()
 
 	ctx       context.Context
 	cancel    context.CancelCauseFunc
 	closingCh chan struct{}
+	// stopping is set by the first call of [App.StopWithTimeout], which closes stoppedCh once it is done waiting.
+	stopping  atomic.Bool
+	stoppedCh chan struct{}
 
 	forcefullyTimeout time.Duration
+
+	// logger is used instead of [slog.Default] when configured.
+	logger *slog.Logger
+	// signalsDisabled stops [App.Start] from listening on system signals.
+	signalsDisabled bool
+	// stopSignals are the signals that stop the app, as configured by [WithSignals].
+	stopSignals []os.Signal
+	// hardDeadline is the time after which a shutdown still in progress exits the process. Disabled when 0.
+	hardDeadline time.Duration
+	// signals reports the signals received since the app was created, logged during the cleanup.
+	signals *shutdown.Observer
+	// startTimeout bounds the start of each component. Disabled when 0.
+	startTimeout time.Duration
+	// parallelStopTimeout is the timeout of stopping each component when they are stopped concurrently.
+	// Disabled when 0.
+	parallelStopTimeout time.Duration
+
+	// runners tracks the [Runner.Run] calls of the registered components.
+	runners sync.WaitGroup
+	// runErr is the error of the first [Runner] that failed, as returned by [App.Err].
+	runErr atomic.Pointer[error]
+	// cleanupErr joins the errors encountered while stopping the components, as returned by [App.Err].
+	cleanupErr atomic.Pointer[error]
+	// registrations replays the registrations of the components when the app is restarted.
+	registrations []func() error
+	// running is set while [App.Start] runs, while finished is set once a run of the app finished, until the
+	// app is restarted.
+	running  atomic.Bool
+	finished atomic.Bool
+
+	// stopProgress tracks the components being stopped during the cleanup.
+	stopProgress stopProgress
+
+	// hooks holds the callbacks registered with [App.OnStart] and [App.OnStop].
+	hooks hooks
+}
+
+// HardDeadlineExitCode is the code with which the process exits when the shutdown exceeds the deadline
+// configured by [WithHardDeadline].
+const HardDeadlineExitCode = 3
+
+// Option configures the [App] when given to [New].
+type Option func(*App)
+
+// WithHardDeadline configures a last resort for the shutdown: when the whole shutdown, including the cleanup of the
+// components, takes longer than d, the process exits with [HardDeadlineExitCode]. This guarantees that the process
+// eventually dies even if a component is wedged and never returns from its [Component.Stop].
+func WithHardDeadline(d time.Duration) Option {
+	return func(a *App) {
+		a.hardDeadline = d
+	}
+}
+
+// WithStopTimeout configures how long [App.Stop] waits for the cleanup of the components, before returning while the
+// cleanup is still in progress. A timeout of 0 means waiting forever. Default: 3s
+// This panics on a negative timeout.
+func WithStopTimeout(d time.Duration) Option {
+	if d < 0 {
+		panic(fmt.Sprintf("app: the stop timeout cannot be negative, got %s", d))
+	}
+	return func(a *App) {
+		a.forcefullyTimeout = d
+	}
+}
+
+// WithStartTimeout configures how long [App.Register] and [App.RegisterParallel] wait for each component to start.
+// A component that does not start in time is treated as failing to start, rolling back the startup.
+// Since the start of such a component cannot be interrupted, it is abandoned and it is only logged if it eventually
+// returns. The components implementing [StarterContext] get the deadline through their context.
+// A timeout of 0 means waiting forever. Default: 0
+// This panics on a negative timeout.
+func WithStartTimeout(d time.Duration) Option {
+	if d < 0 {
+		panic(fmt.Sprintf("app: the start timeout cannot be negative, got %s", d))
+	}
+	return func(a *App) {
+		a.startTimeout = d
+	}
+}
+
+// WithLogger configures the logger used by the app to log its lifecycle and the one of its components, allowing to
+// route these logs separately from the rest of the process. A nil logger is ignored. Default: [slog.Default]
+func WithLogger(l *slog.Logger) Option {
+	return func(a *App) {
+		a.logger = l
+	}
+}
+
+// RegisterOption configures the registration of a component when given to [App.Register].
+type RegisterOption func(*registration)
+
+type registration struct {
+	startTimeout time.Duration
+}
+
+// StartTimeout overrides for a single component the timeout configured with [WithStartTimeout].
+// A timeout of 0 means waiting forever.
+// This panics on a negative timeout.
+func StartTimeout(d time.Duration) RegisterOption {
+	if d < 0 {
+		panic(fmt.Sprintf("app: the start timeout cannot be negative, got %s", d))
+	}
+	return func(r *registration) {
+		r.startTimeout = d
+	}
+}
+
+// WithSignals configures the signals that stop the app. Default: [shutdown.TerminationSignals]
+// Giving no signal disables the handling of the signals altogether, including the reload on syscall.SIGHUP, which
+// is useful when the app is embedded into another lifecycle manager.
+// Leaving syscall.SIGHUP out lets it be used only for reloading, while leaving syscall.SIGQUIT out keeps its default
+// behavior of dumping the goroutines.
+func WithSignals(sigs ...os.Signal) Option {
+	return func(a *App) {
+		a.stopSignals = slices.Clone(sigs)
+		a.signalsDisabled = len(sigs) == 0
+	}
 }
 
-func New() *App {
+// New creates a new [App] configured with the given options, applied in order. The nil options are ignored.
+// All the options are applied before the app starts listening on the signals, which happens only in [App.Start].
+func New(opts ...Option) *App {
 	ctx, cancel := context.WithCancelCause(context.Background())
-	return &App{
+	a := &App{
 		ctx:               ctx,
 		cancel:            cancel,
 		closingCh:         make(chan struct{}, 1),
+		stoppedCh:         make(chan struct{}),
 		forcefullyTimeout: 3 * time.Second,
+		signals:           shutdown.Observe(),
+		stopSignals:       shutdown.TerminationSignals(),
+		createdAt:         time.Now(),
+		groupNames:        []string{defaultGroup},
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(a)
+		}
 	}
+	return a
 }
 
 // Register initialises a [Component] calling its [Component.Start].
 // If the initialisation of the [Component] returns an error, any other [Component] previously
-// registered, will be cleaned up (ie: call [Component.Stop]) and will panic to stop the startup.
-func (a *App) Register(c Component) {
+// registered, will be cleaned up (ie: call [Component.Stop]) and will panic to stop the startup. Use [App.RegisterE]
+// to get the error instead.
+//
+// When c implements [StarterContext] and/or [StopperContext], it receives a context on start and/or on stop.
+//
+// The [Component.String] of c identifies it in the app: registering a component with the name of an already
+// registered one panics the same way.
+// When c implements [Dependent], the components it depends on must be already registered.
+//
+// The start of c is bounded by the timeout configured with [WithStartTimeout], unless overridden by [StartTimeout].
+//
+// On cleanup, the components are stopped in the reverse order of their registration.
+// The components registered directly on the app belong to the default group, which is the first one to start and the
+// last one to stop. See [App.Group].
+func (a *App) Register(c Component, opts ...RegisterOption) {
+	if err := a.RegisterE(c, opts...); err != nil {
+		panic(err)
+	}
+}
+
+// RegisterE is the same as [App.Register] but returns the error instead of panicking, allowing the caller to report
+// it (ie: with a non-zero exit code) instead of crashing.
+// On failure, the previously registered components are cleaned up the same way. The error returned names c and wraps
+// the error of its start, joined with the errors encountered during the cleanup.
+func (a *App) RegisterE(c Component, opts ...RegisterOption) error {
+	return a.register(0, c, opts...)
+}
+
+func (a *App) register(group int, c Component, opts ...RegisterOption) error {
 	if c == nil {
-		a.exit(fmt.Errorf("given component is nil"))
-		return
+		return a.rollback(fmt.Errorf("given component is nil"))
 	}
-	err := c.Start()
-	if err != nil {
-		a.exit(err)
+	if err := a.checkDependencies(c, nil); err != nil {
+		return a.rollback(err)
+	}
+	if err := a.enterGroup(group); err != nil {
+		return a.rollback(err)
 	}
-	slog.
-		With("component", c.String()).
+	if err := a.reserve(c.String()); err != nil {
+		return a.rollback(err)
+	}
+	begin := time.Now()
+	r := registration{startTimeout: a.startTimeout}
+	for _, opt := range opts {
+		opt(&r)
+	}
+	if err := a.startWithTimeout(c, r.startTimeout); err != nil {
+		a.release(c.String())
+		return a.rollback(startError(c, err))
+	}
+	d := time.Since(begin)
+	a.recordStart(c.String(), d)
+	a.componentLog(c.String()).
+		With("duration", d).
 		Debug("component registered successfully")
-	a.components = append(a.components, c)
+	a.addComponents(group, c)
+	a.remember(func() error { return a.register(group, c, opts...) })
+	return nil
 }
 
 // Start is a blocking call that keeps the main goroutine from returning, allowing the other
 // previously registered components to run properly.
-// This method returns in only 2 cases: a system signal is received or the [Stop] is called specifically from another
-// goroutine.
-// The system signals that this listens for are: syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT.
+// This method returns in only 3 cases: a system signal is received, the [Stop] is called specifically from another
+// goroutine or a [Runner] component failed.
+// The system signals that this listens for are the ones configured with [WithSignals], by default the ones returned by
+// [shutdown.TerminationSignals]: syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT or, on Windows, os.Interrupt
+// and syscall.SIGTERM.
+//
+// The syscall.SIGHUP is used as the reload signal: when received, the logging is configured again by calling
+// [logging.Reload], allowing changes of LOG_LEVEL or LOG_FORMAT to take effect without a restart. The options given to
+// [logging.SetupWith] are kept, and a default logger installed with [slog.SetDefault] is left untouched.
+// The signals received are logged, as described in [shutdown.LogSignals].
+//
+// Once Start returned, calling it again restarts the app: the components registered before are started again, in
+// the order in which they were registered, and the callbacks registered with [App.OnStart] and [App.OnStop] are
+// called again. The app must not be used concurrently while it is restarted.
+// Calling Start while the app is running, including while its cleanup is still in progress, panics.
 func (a *App) Start() {
-	ctx, cancel := shutdown.Context(a.ctx, syscall.SIGHUP,
-		syscall.SIGINT,
-		syscall.SIGTERM,
-		syscall.SIGQUIT,
-	)
-	defer cancel()
+	a.restart()
+	if !a.running.CompareAndSwap(false, true) {
+		panic("app: the app is already running")
+	}
+	ctx := a.ctx
+	var reloadCh <-chan struct{}
+	if !a.signalsDisabled {
+		shutdown.LogSignals(a.logger)
+		var cancel context.CancelFunc
+		ctx, cancel = shutdown.Context(a.ctx, a.stopSignals...)
+		defer cancel()
+
+		var stopReload func()
+		reloadCh, stopReload = shutdown.Reload()
+		defer stopReload()
+	}
 
 	defer func() {
+		// propagate the cause of a signal to the components still using the app context, like the [Runner]s
+		a.cancel(context.Cause(ctx))
+		if a.hardDeadline > 0 {
+			t := time.AfterFunc(a.hardDeadline, a.exitOnHardDeadline)
+			defer t.Stop()
+		}
 		a.cleanup()
+		// the hooks registered from now on are kept for the next run
+		a.hooks.reset()
 		close(a.closingCh)
+		a.finished.Store(true)
+		a.running.Store(false)
 	}()
-	slog.Info("started...")
-	select {
-	case <-ctx.Done():
-		slog.Debug("app closing triggered")
+	a.logStartup()
+	a.log().Info("started...")
+	a.ready()
+	a.runStartHooks(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			a.log().With("cause", context.Cause(ctx)).Info("app closing triggered")
+			return
+		case <-reloadCh:
+			a.reload()
+		}
+	}
+}
+
+// WhenReady registers a callback that is called once the app is fully started, right before [App.Start]
+// starts blocking. This is informational only and cannot fail the startup, making it useful for signaling
+// readiness to a supervisor (ie: systemd's sd_notify) or emitting a metric.
+// The callbacks are called in the order in which they were registered.
+func (a *App) WhenReady(fn func()) {
+	if fn == nil {
+		return
 	}
+	a.readyFns = append(a.readyFns, fn)
 }
 
-// Stop cancels the application [context.Context] and waits for the whole application to cleanup
+// Stop cancels the application [context.Context] and waits for the whole application to cleanup.
+// This is safe to be called multiple times and concurrently, as described in [App.StopWithTimeout].
 func (a *App) Stop() {
+	a.StopWithTimeout(a.forcefullyTimeout)
+}
+
+// StopWithTimeout is the same as [App.Stop] but waits for the cleanup at most the given timeout instead
+// of the default one. The default timeout of the app is not changed. A timeout of 0 means waiting forever.
+//
+// Only the first call stops the app and waits for the cleanup. Any other call, concurrent or later, just waits
+// for the first one to return, regardless of its own timeout.
+func (a *App) StopWithTimeout(timeout time.Duration) {
+	if !a.stopping.CompareAndSwap(false, true) {
+		<-a.stoppedCh
+		return
+	}
+	defer close(a.stoppedCh)
 	a.cancel(fmt.Errorf("app stopped"))
 
+	var timeoutCh <-chan time.Time
+	if timeout > 0 {
+		timeoutCh = time.After(timeout)
+	}
+	select {
+	case <-a.closingCh:
+		a.log().Debug("app stopped successfully")
+	case <-timeoutCh:
+		a.log().
+			With("timeout", timeout).
+			With("blocking", a.stopProgress.blocking()).
+			Warn("app stopped forcefully after timeout")
+	}
+}
+
+// StopE is the same as [App.Stop] but returns the errors encountered while stopping the components, joined
+// together, or an error naming the components still stopping when the cleanup did not finish within the stop timeout.
+func (a *App) StopE() error {
+	a.Stop()
 	select {
 	case <-a.closingCh:
-		slog.Debug("app stopped successfully")
-	case <-time.After(a.forcefullyTimeout):
-		slog.With("timeout", a.forcefullyTimeout).Warn("app stopped forcefully after timeout")
+		return a.cleanupError()
+	default:
+		if blocking := a.stopProgress.blocking(); len(blocking) > 0 {
+			return fmt.Errorf("app cleanup did not finish within %s, still stopping: %v", a.forcefullyTimeout, blocking)
+		}
+		return fmt.Errorf("app cleanup did not finish within %s", a.forcefullyTimeout)
 	}
 }
 
 // Context returns the context that is used to start the app.
-// This is cancellable context whose [context.Done()] can be used
-// to listen on the shutdown signals.
+// The context is done once the app starts closing, either because of [App.Stop], a shutdown signal or a failed
+// [Runner], and its [context.Cause] tells why (ie: "app stopped").
+// The context cannot be cancelled by the callers, only by the app. A restarted app has a new context, see [App.Start].
 func (a *App) Context() context.Context {
-	return context.WithValue(a.ctx, "", "")
+	return a.ctx
+}
+
+// Done is the same as the Done of [App.Context], returning a channel that is closed once the app starts closing.
+func (a *App) Done() <-chan struct{} {
+	return a.ctx.Done()
+}
+
+// Err returns the error of the first [Runner] that failed, stopping the app, joined with the errors encountered while
+// stopping the components, once the cleanup finished (ie: after [App.Start] returned).
+// This returns nil when the app is running or it was stopped cleanly, so it can be checked to tell a failure or a
+// dirty shutdown apart from a clean one.
+func (a *App) Err() error {
+	var runErr error
+	if err := a.runErr.Load(); err != nil {
+		runErr = *err
+	}
+	return errors.Join(runErr, a.cleanupError())
+}
+
+func (a *App) cleanupError() error {
+	if err := a.cleanupErr.Load(); err != nil {
+		return *err
+	}
+	return nil
+}
+
+// ready calls all the callbacks registered with [App.WhenReady].
+func (a *App) ready() {
+	for _, fn := range a.readyFns {
+		fn()
+	}
+}
+
+// reload configures again the parts of the app that can be changed without a restart.
+func (a *App) reload() {
+	if _, err := logging.Reload(); err != nil {
+		a.log().With(logging.Err(err)).Warn("invalid logging configuration, falling back on the defaults")
+	}
+	a.log().Info("app reloaded")
 }
 
-// cleanup stops and successfully registered [Component].
-func (a *App) cleanup() {
-	for _, c := range a.components {
-		if err := c.Stop(); err != nil {
-			slog.
-				With("error", err).
-				With("component", c.String()).
-				Warn("stop error encountered during closing component")
+// cleanup stops and successfully registered [Component] and flushes the queued logs.
+// It returns the errors returned by the components while stopping, joined.
+func (a *App) cleanup() error {
+	a.runStopHooks()
+	if received := a.signals.Received(); len(received) > 0 {
+		a.log().With("signals", received).Info("signals received by the app")
+	}
+	ctx, cancel := a.stopContext()
+	defer cancel()
+	a.sortForStop()
+	stopLogging := a.logStopProgress()
+	var errs []error
+	if a.parallelStopTimeout > 0 {
+		errs = a.stopParallel(ctx)
+	} else {
+		for i, c := range a.components {
+			a.logGroupStopping(i)
+			begin := time.Now()
+			err := a.stopTracked(ctx, c)
+			d := time.Since(begin)
+			a.recordStop(c.String(), d)
+			if err != nil {
+				a.logStopError(c, err)
+				errs = append(errs, stopError(c, err))
+				continue
+			}
+			a.componentLog(c.String()).
+				With("duration", d).
+				Debug("component stopped")
 		}
 	}
+	a.waitRunners(ctx)
+	stopLogging()
+	a.componentsM.Lock()
 	a.components = nil
+	a.batches = nil
+	a.phases = nil
+	a.names = nil
+	a.componentsM.Unlock()
+	err := errors.Join(errs...)
+	if err != nil {
+		a.cleanupErr.Store(&err)
+	}
+	a.flushLogs()
+	return err
+}
+
+// flushLogs writes the records queued by the [logging.AsyncHandler], so the logs of the shutdown are not lost.
+func (a *App) flushLogs() {
+	ctx, cancel := a.stopContext()
+	defer cancel()
+	if err := logging.Flush(ctx); err != nil {
+		a.log().With("error", err).Warn("not all the queued logs could be written")
+	}
+}
+
+// exitOnHardDeadline terminates the process since the shutdown did not finish in the configured hard deadline.
+func (a *App) exitOnHardDeadline() {
+	a.log().
+		With("hard_deadline", a.hardDeadline).
+		With("exit_code", HardDeadlineExitCode).
+		Error("app shutdown exceeded the hard deadline, exiting the process")
+	os.Exit(HardDeadlineExitCode)
+}
+
+// addComponents records the successfully started components of the given group as a batch that can be stopped together.
+// The ones implementing [Runner] start running.
+func (a *App) addComponents(group int, cs ...Component) {
+	a.componentsM.Lock()
+	defer a.componentsM.Unlock()
+	a.lastBatch++
+	for _, c := range cs {
+		a.components = append(a.components, c)
+		a.batches = append(a.batches, a.lastBatch)
+		a.phases = append(a.phases, group)
+		if r, ok := c.(Runner); ok {
+			a.run(c, r)
+		}
+	}
+}
+
+// registered returns a snapshot of the registered components, safe to be used concurrently with the registration.
+func (a *App) registered() []Component {
+	a.componentsM.Lock()
+	defer a.componentsM.Unlock()
+	return slices.Clone(a.components)
+}
+
+func (a *App) logStopError(c fmt.Stringer, err error) {
+	a.componentLog(c.String()).
+		With(logging.Err(err)).
+		Warn("stop error encountered during closing component")
+}
+
+// stopContext returns a context bounded by the stop timeout of the app, which is never done when the timeout is 0.
+func (a *App) stopContext() (context.Context, context.CancelFunc) {
+	if a.forcefullyTimeout > 0 {
+		return context.WithTimeout(context.Background(), a.forcefullyTimeout)
+	}
+	return context.WithCancel(context.Background())
+}
+
+// startError wraps the error returned by the start of c, naming the component.
+func startError(c fmt.Stringer, err error) error {
+	return fmt.Errorf("component %q failed to start: %w", c.String(), err)
+}
+
+// stopError wraps the error returned by the stop of c, naming the component.
+func stopError(c fmt.Stringer, err error) error {
+	return fmt.Errorf("component %q failed to stop: %w", c.String(), err)
+}
+
+// startWithTimeout starts c, abandoning it when it does not start in the given timeout.
+func (a *App) startWithTimeout(c Component, timeout time.Duration) error {
+	if timeout <= 0 {
+		return startComponent(a.ctx, c)
+	}
+	ctx, cancel := context.WithTimeout(a.ctx, timeout)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- startComponent(ctx, c)
+	}()
+	select {
+	case err := <-errCh:
+		cancel()
+		return err
+	case <-ctx.Done():
+		go func() {
+			defer cancel()
+			err := <-errCh
+			a.componentLog(c.String()).
+				With(logging.Err(err)).
+				Warn("component abandoned after the start timeout eventually returned")
+		}()
+		return fmt.Errorf("did not start within %s: %w", timeout, ctx.Err())
+	}
+}
+
+// startComponent starts c, giving it ctx when it implements [StarterContext].
+func startComponent(ctx context.Context, c Component) error {
+	if s, ok := c.(StarterContext); ok {
+		return s.StartContext(ctx)
+	}
+	return c.Start()
+}
+
+// stopComponent stops c, giving it ctx when it implements [StopperContext].
+func stopComponent(ctx context.Context, c Component) error {
+	if s, ok := c.(StopperContext); ok {
+		return s.StopContext(ctx)
+	}
+	return c.Stop()
+}
+
+// log returns the logger of the app, falling back on [slog.Default].
+func (a *App) log() *slog.Logger {
+	if a.logger != nil {
+		return a.logger
+	}
+	return slog.Default()
 }
 
-// exit is just a utility function that combines [cleanup] with a panic.
+// exit is just a utility function that combines [App.rollback] with a panic.
 func (a *App) exit(err error) {
-	a.cleanup()
-	panic(err)
+	panic(a.rollback(err))
+}
+
+// rollback cleans up the components registered so far after a failed registration, and returns err joined with the
+// errors encountered during the cleanup.
+func (a *App) rollback(err error) error {
+	a.cancel(err)
+	if cleanupErr := a.cleanup(); cleanupErr != nil {
+		return errors.Join(err, cleanupErr)
+	}
+	return err
 }
@@ -1,12 +1,24 @@
 package app
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"sync"
 	"sync/atomic"
+	"syscall"
 	"testing"
 	"testing/synctest"
 	"time"
+
+	"github.com/yottta/go-core/logging"
+	"github.com/yottta/go-core/logging/logtest"
+	"github.com/yottta/go-core/shutdown"
 )
 
 func TestRegister(t *testing.T) {
@@ -16,16 +28,64 @@ func TestRegister(t *testing.T) {
 		a.Register(nil)
 	})
 	t.Run("component start returns error", func(t *testing.T) {
-		const want = "error from component"
-		defer expectPanic(t, want)
+		startErr := errors.New("error from component")
+		defer func() {
+			r := recover()
+			err, ok := r.(error)
+			if !ok {
+				t.Fatalf("expected to panic with an error but got: %v", r)
+			}
+			if got, want := err.Error(), `component "postgres" failed to start: error from component`; got != want {
+				t.Errorf("failed with a different error.\nexpected: \n\t%s\ngot:\n\t%s", want, got)
+			}
+			if got := errors.Unwrap(err); got != startErr {
+				t.Errorf("expected the original error to be unwrapped but got: %v", got)
+			}
+		}()
 		a := New()
 		a.Register(&mockComp{
+			name: "postgres",
 			startF: func() error {
-				return fmt.Errorf(want)
+				return startErr
 			},
 			stopF: nil,
 		})
 	})
+	t.Run("RegisterE returns the error and rolls back", func(t *testing.T) {
+		startErr := errors.New("error from component")
+		var stopped bool
+		a := New()
+		if err := a.RegisterE(&mockComp{
+			name:   "db",
+			startF: func() error { return nil },
+			stopF:  func() error { stopped = true; return nil },
+		}); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		err := a.RegisterE(&mockComp{name: "cache", startF: func() error { return startErr }})
+		if got, want := fmt.Sprint(err), `component "cache" failed to start: error from component`; got != want {
+			t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+		}
+		if !errors.Is(err, startErr) {
+			t.Errorf("expected the error of the start to be wrapped")
+		}
+		if !stopped {
+			t.Errorf("expected the previously registered component to be stopped")
+		}
+	})
+	t.Run("RegisterE joins the errors of the rollback", func(t *testing.T) {
+		stopErr := errors.New("connection reset")
+		a := New()
+		a.Register(&mockComp{name: "db", startF: func() error { return nil }, stopF: func() error { return stopErr }})
+		err := a.RegisterE(&mockComp{name: "cache", startF: func() error { return errors.New("boom") }})
+		want := "component \"cache\" failed to start: boom\ncomponent \"db\" failed to stop: connection reset"
+		if got := fmt.Sprint(err); got != want {
+			t.Errorf("got a different value than the wanted one.\nexpected:\n%s\ngot:\n%s", want, got)
+		}
+		if !errors.Is(err, stopErr) {
+			t.Errorf("expected the error of the rollback to be wrapped")
+		}
+	})
 }
 
 func TestStartStop(t *testing.T) {
@@ -73,6 +133,79 @@ func TestStartStop(t *testing.T) {
 			t.Fatalf("failed with a different context cause.\nexpected: \n\t%s\ngot:\n\t%s", want, got)
 		}
 	})
+	t.Run("done and err accessors", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			a := New()
+			a.signalsDisabled = true
+			select {
+			case <-a.Done():
+				t.Fatalf("expected the app not to be done before stopping it")
+			default:
+			}
+			go a.Stop()
+			a.Start()
+			select {
+			case <-a.Done():
+			default:
+				t.Fatalf("expected the app to be done after stopping it")
+			}
+			if err := a.Err(); err != nil {
+				t.Errorf("expected no error after a clean stop but got: %s", err)
+			}
+			if got, want := context.Cause(a.Context()).Error(), "app stopped"; got != want {
+				t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+			}
+		})
+	})
+}
+
+func TestStopCauseIsLogged(t *testing.T) {
+	var b bytes.Buffer
+	a := New()
+	a.logger = slog.New(slog.NewTextHandler(&b, nil))
+	go func() {
+		<-time.After(100 * time.Millisecond)
+		a.Stop()
+	}()
+	a.Start()
+	if want := `msg="app closing triggered" cause="app stopped"`; !strings.Contains(b.String(), want) {
+		t.Errorf("expected logs to contain %q but got:\n%s", want, b.String())
+	}
+}
+
+func TestStopOnTerminationSignal(t *testing.T) {
+	shutdown.EnableTestMode()
+	synctest.Test(t, func(t *testing.T) {
+		var b bytes.Buffer
+		var stopCalled atomic.Bool
+		a := New()
+		a.logger = slog.New(slog.NewTextHandler(&b, nil))
+		a.Register(&mockComp{
+			startF: func() error { return nil },
+			stopF:  func() error { stopCalled.Store(true); return nil },
+		})
+		doneCh := make(chan struct{})
+		go func() {
+			defer close(doneCh)
+			a.Start()
+		}()
+		synctest.Wait()
+		shutdown.Trigger(syscall.SIGTERM)
+		<-doneCh
+
+		if !stopCalled.Load() {
+			t.Errorf("expected to have the stop function called but it wasn't")
+		}
+		for _, want := range []string{`cause="shutdown: received signal SIGTERM"`, `signals="[SIGTERM at `} {
+			if !strings.Contains(b.String(), want) {
+				t.Errorf("expected logs to contain %q but got:\n%s", want, b.String())
+			}
+		}
+		received, closing := strings.Index(b.String(), `msg="received SIGTERM"`), strings.Index(b.String(), `msg="app closing triggered"`)
+		if received < 0 || received > closing {
+			t.Errorf("expected the signal to be logged before the app closing but got:\n%s", b.String())
+		}
+	})
 }
 
 func TestComponentErrors(t *testing.T) {
@@ -80,20 +213,25 @@ func TestComponentErrors(t *testing.T) {
 		var (
 			startCalled, stopCalled bool
 		)
+		stopErr := fmt.Errorf("failed to stop")
 		a := New()
 		a.Register(&mockComp{
+			name: "postgres",
 			startF: func() error {
 				startCalled = true
 				return nil
 			},
 			stopF: func() error {
 				stopCalled = true
-				return fmt.Errorf("failed to stop")
+				return stopErr
 			},
 		})
+		a.RegisterFunc("cache", nil, func() error { return errors.New("connection reset") })
+		a.RegisterFunc("queue", nil, nil)
+		stopErrCh := make(chan error, 1)
 		go func() {
 			<-time.After(time.Second)
-			a.Stop()
+			stopErrCh <- a.StopE()
 		}()
 		a.Start()
 
@@ -103,49 +241,303 @@ func TestComponentErrors(t *testing.T) {
 		if !stopCalled {
 			t.Errorf("expected to have the stop function called but it wasn't")
 		}
+		err := <-stopErrCh
+		want := "component \"cache\" failed to stop: connection reset\ncomponent \"postgres\" failed to stop: failed to stop"
+		if err == nil || err.Error() != want {
+			t.Fatalf("got a different value than the wanted one.\nexpected:\n%s\ngot:\n%v", want, err)
+		}
+		if !errors.Is(err, stopErr) {
+			t.Errorf("expected the original error to be part of the aggregated one")
+		}
+		if got := a.Err(); got == nil || got.Error() != want {
+			t.Errorf("got a different value than the wanted one.\nexpected:\n%s\ngot:\n%v", want, got)
+		}
+	})
+	t.Run("StopE reports a cleanup that did not finish", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			a := New(WithStopTimeout(time.Second))
+			a.signalsDisabled = true
+			a.RegisterFunc("slow", nil, func() error { <-time.After(time.Minute); return nil })
+			go a.Start()
+			synctest.Wait()
+			if got, want := fmt.Sprint(a.StopE()), "app cleanup did not finish within 1s, still stopping: [slow (1s)]"; got != want {
+				t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+			}
+			<-a.closingCh
+			if err := a.StopE(); err != nil {
+				t.Errorf("expected no error once the cleanup finished but got: %s", err)
+			}
+		})
 	})
 	t.Run("when component.Stop takes too much time, app.Stop returns before component.Stop", func(t *testing.T) {
+		const compStopDuration = 5 * time.Second
+		cases := []struct {
+			stopTimeout time.Duration
+			// wantForceful is true when app.Stop is expected to return before component.Stop
+			wantForceful bool
+		}{
+			{stopTimeout: time.Second, wantForceful: true},
+			{stopTimeout: 3 * time.Second, wantForceful: true},
+			{stopTimeout: 0, wantForceful: false}, // waits forever
+		}
+		for _, tt := range cases {
+			t.Run(fmt.Sprintf("stop timeout %s", tt.stopTimeout), func(t *testing.T) {
+				synctest.Test(t, func(t *testing.T) {
+					var (
+						startCalled   bool
+						compStoppedAt atomic.Pointer[time.Time]
+						appStoppedAt  atomic.Pointer[time.Time]
+					)
+					a := New(WithStopTimeout(tt.stopTimeout))
+					a.Register(&mockComp{
+						startF: func() error { startCalled = true; return nil },
+						stopF: func() error {
+							<-time.After(compStopDuration) // longer than the stop timeout
+							now := time.Now()
+							compStoppedAt.Store(&now)
+							return nil
+						},
+					})
+					stopCalledAt := time.Now().Add(time.Second)
+					go func() {
+						<-time.After(time.Second)
+						a.Stop()
+						now := time.Now()
+						appStoppedAt.Store(&now)
+					}()
+					synctest.Wait()
+					a.Start()
+					// NOTE: Do not sleep here. Start() is meant to block until everything is cleaned up
+					if !startCalled {
+						t.Errorf("expected to have the start function called but it wasn't")
+					}
+
+					select {
+					case <-a.Context().Done():
+					case <-time.After(5 * time.Second):
+						t.Fatalf("expected the app to fail and close the channel")
+					}
+					synctest.Wait()
+					compStoppedAtTime := compStoppedAt.Load()
+					appStoppedAtTime := appStoppedAt.Load()
+					if got := compStoppedAtTime.Compare(*appStoppedAtTime) > 0; got != tt.wantForceful {
+						t.Fatalf("got a different value than the wanted one for the component finishing after the app. expected: %t; got: %t", tt.wantForceful, got)
+					}
+					want := stopCalledAt.Add(tt.stopTimeout)
+					if !tt.wantForceful {
+						want = stopCalledAt.Add(compStopDuration)
+					}
+					if got := *appStoppedAtTime; !got.Equal(want) {
+						t.Errorf("got a different value than the wanted one. expected: %s; got: %s", want, got)
+					}
+				})
+			})
+		}
+	})
+}
+
+func TestStopWithTimeout(t *testing.T) {
+	run := func(t *testing.T, stop func(a *App)) (compStopped bool) {
+		var stopped atomic.Bool
+		a := New()
+		a.Register(&mockComp{
+			startF: func() error { return nil },
+			stopF: func() error {
+				<-time.After(5 * time.Second) // longer than the forcefullyTimeout
+				stopped.Store(true)
+				return nil
+			},
+		})
+		doneCh := make(chan struct{})
+		go func() {
+			defer close(doneCh)
+			a.Start()
+		}()
+		synctest.Wait()
+		stop(a)
+		compStopped = stopped.Load()
+		if got, want := a.forcefullyTimeout, 3*time.Second; got != want {
+			t.Errorf("expected the default timeout to be unchanged. expected %s; got %s", want, got)
+		}
+		<-doneCh
+		return compStopped
+	}
+	t.Run("longer one-off timeout waits for the component", func(t *testing.T) {
 		synctest.Test(t, func(t *testing.T) {
-			var (
-				startCalled   bool
-				compStoppedAt atomic.Pointer[time.Time]
-				appStoppedAt  atomic.Pointer[time.Time]
-			)
-			a := New()
-			a.Register(&mockComp{
-				startF: func() error { startCalled = true; return nil },
-				stopF: func() error {
-					<-time.After(5 * time.Second) // longer than the forcefullyTimeout
-					now := time.Now()
-					compStoppedAt.Store(&now)
-					return nil
-				},
+			if !run(t, func(a *App) { a.StopWithTimeout(10 * time.Second) }) {
+				t.Errorf("expected the stop to wait for the component")
+			}
+		})
+	})
+	t.Run("default timeout does not wait for the component", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			if run(t, func(a *App) { a.Stop() }) {
+				t.Errorf("expected the stop to return before the component stopped")
+			}
+		})
+	})
+}
+
+func TestConcurrentStop(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		stopDuration time.Duration
+		wantLog      string
+	}{
+		{name: "clean stop", stopDuration: time.Second, wantLog: "app stopped successfully"},
+		{name: "forceful stop", stopDuration: 5 * time.Second, wantLog: "app stopped forcefully after timeout"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			synctest.Test(t, func(t *testing.T) {
+				var b bytes.Buffer
+				a := New()
+				a.logger = slog.New(slog.NewTextHandler(&b, &slog.HandlerOptions{Level: slog.LevelDebug}))
+				a.signalsDisabled = true
+				a.RegisterFunc("slow", nil, func() error { <-time.After(tc.stopDuration); return nil })
+				go a.Start()
+				synctest.Wait()
+
+				begin := time.Now()
+				var wg sync.WaitGroup
+				for range 10 {
+					wg.Go(a.Stop)
+				}
+				wg.Wait()
+				if got, want := time.Since(begin), min(tc.stopDuration, a.forcefullyTimeout); got != want {
+					t.Errorf("got a different value than the wanted one. expected: %s; got: %s", want, got)
+				}
+				<-a.closingCh // let the forcefully stopped cleanup finish
+				logs := b.String()
+				if got := strings.Count(logs, `msg="app stopped`); got != 1 {
+					t.Errorf("expected a single stop outcome to be logged but got %d:\n%s", got, logs)
+				}
+				if !strings.Contains(logs, tc.wantLog) {
+					t.Errorf("expected logs to contain %q but got:\n%s", tc.wantLog, logs)
+				}
+				a.Stop() // later calls return right away
+				if got, want := time.Since(begin), tc.stopDuration; got != want {
+					t.Errorf("expected a later stop to return right away. expected: %s; got: %s", want, got)
+				}
 			})
-			go func() {
-				<-time.After(time.Second)
-				a.Stop()
-				now := time.Now()
-				appStoppedAt.Store(&now)
+		})
+	}
+}
+
+func TestStartTimeout(t *testing.T) {
+	t.Run("slow start fails the registration", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			const abandonedMsg = "component abandoned after the start timeout eventually returned"
+			h := logtest.NewHandler()
+			var stopped bool
+			a := New(WithStartTimeout(2 * time.Second))
+			a.logger = slog.New(h)
+			a.RegisterFunc("cache", nil, func() error { stopped = true; return nil })
+			func() {
+				defer expectPanic(t, `component "db" failed to start: did not start within 2s: context deadline exceeded`)
+				a.RegisterFunc("db", func() error { <-time.After(time.Minute); return nil }, nil)
 			}()
+			if !stopped {
+				t.Errorf("expected the previously started components to be rolled back")
+			}
 			synctest.Wait()
-			a.Start()
-			// NOTE: Do not sleep here. Start() is meant to block until everything is cleaned up
-			if !startCalled {
-				t.Errorf("expected to have the start function called but it wasn't")
+			if h.ContainsMessage(abandonedMsg) {
+				t.Errorf("expected the abandoned component not to be logged before its start returned")
 			}
-
-			select {
-			case <-a.Context().Done():
-			case <-time.After(5 * time.Second):
-				t.Fatalf("expected the app to fail and close the channel")
+			<-time.After(time.Minute)
+			synctest.Wait()
+			if got, want := h.AttrsFor(abandonedMsg)["component"].String(), "db"; got != want {
+				t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+			}
+		})
+	})
+	t.Run("per registration override", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			a := New(WithStartTimeout(time.Second))
+			a.RegisterFunc("db", func() error { <-time.After(5 * time.Second); return nil }, nil, StartTimeout(10*time.Second))
+			a.RegisterFunc("migrations", func() error { <-time.After(time.Minute); return nil }, nil, StartTimeout(0))
+			if got, want := a.Components(), []string{"db", "migrations"}; !slices.Equal(got, want) {
+				t.Errorf("got a different value than the wanted one. expected: %v; got: %v", want, got)
 			}
-			compStoppedAtTime := compStoppedAt.Load()
-			appStoppedAtTime := appStoppedAt.Load()
-			if compStoppedAtTime.Compare(*appStoppedAtTime) <= 0 {
-				t.Fatalf("expected the component to finish after the app because of the timeout")
+		})
+	})
+	t.Run("context aware components get the deadline", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			var startCtx context.Context
+			a := New(WithStartTimeout(3 * time.Second))
+			a.Register(&ctxComp{
+				startF: func(ctx context.Context) error {
+					startCtx = ctx
+					deadline, ok := ctx.Deadline()
+					if !ok || deadline.Sub(time.Now()) != 3*time.Second {
+						t.Errorf("expected the context to carry the deadline but got: %s (%t)", deadline, ok)
+					}
+					return nil
+				},
+				stopF: func(ctx context.Context) error { return nil },
+			})
+			if startCtx.Err() == nil {
+				t.Errorf("expected the start context to be cancelled once the start returned")
 			}
 		})
 	})
+	t.Run("negative timeout panics", func(t *testing.T) {
+		defer expectPanic(t, "app: the start timeout cannot be negative, got -1s")
+		StartTimeout(-time.Second)
+	})
+}
+
+func TestWithStopTimeoutNegative(t *testing.T) {
+	defer expectPanic(t, "app: the stop timeout cannot be negative, got -1s")
+	WithStopTimeout(-time.Second)
+}
+
+func TestNewOptions(t *testing.T) {
+	t.Run("defaults without options", func(t *testing.T) {
+		a := New()
+		if got, want := a.forcefullyTimeout, 3*time.Second; got != want {
+			t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+		}
+		if got, want := a.stopSignals, shutdown.TerminationSignals(); !slices.Equal(got, want) {
+			t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+		}
+		if a.signalsDisabled {
+			t.Errorf("expected the signals to be handled by default")
+		}
+		if a.log() != slog.Default() {
+			t.Errorf("expected the default logger to be used")
+		}
+	})
+	t.Run("options configure the app", func(t *testing.T) {
+		l := slog.New(slog.DiscardHandler)
+		a := New(WithStopTimeout(time.Second), WithSignals(syscall.SIGTERM), WithLogger(l))
+		if got, want := a.forcefullyTimeout, time.Second; got != want {
+			t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+		}
+		if got, want := a.stopSignals, []os.Signal{syscall.SIGTERM}; !slices.Equal(got, want) {
+			t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+		}
+		if a.log() != l {
+			t.Errorf("expected the configured logger to be used")
+		}
+	})
+	t.Run("no signals disable the signal handling", func(t *testing.T) {
+		a := New(WithSignals())
+		if !a.signalsDisabled {
+			t.Errorf("expected the signals to not be handled")
+		}
+	})
+	t.Run("nil options are ignored", func(t *testing.T) {
+		a := New(nil, WithStopTimeout(time.Second), nil)
+		if got, want := a.forcefullyTimeout, time.Second; got != want {
+			t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+		}
+	})
+	t.Run("options are applied in order", func(t *testing.T) {
+		a := New(WithStopTimeout(time.Second), WithStopTimeout(2*time.Second))
+		if got, want := a.forcefullyTimeout, 2*time.Second; got != want {
+			t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+		}
+	})
 }
 
 func expectPanic(t *testing.T, want string) {
@@ -160,10 +552,14 @@ func expectPanic(t *testing.T, want string) {
 }
 
 type mockComp struct {
+	name          string
 	startF, stopF func() error
 }
 
 func (m mockComp) String() string {
+	if m.name != "" {
+		return m.name
+	}
 	return "mockComp"
 }
 
@@ -174,3 +570,200 @@ func (m mockComp) Start() error {
 func (m mockComp) Stop() error {
 	return m.stopF()
 }
+
+// ctxComp is a component implementing [StarterContext] and [StopperContext].
+type ctxComp struct {
+	startF, stopF func(ctx context.Context) error
+}
+
+func (m ctxComp) String() string {
+	return "ctxComp"
+}
+
+func (m ctxComp) Start() error {
+	panic("ctxComp: Start called instead of StartContext")
+}
+
+func (m ctxComp) StartContext(ctx context.Context) error {
+	return m.startF(ctx)
+}
+
+func (m ctxComp) Stop() error {
+	panic("ctxComp: Stop called instead of StopContext")
+}
+
+func (m ctxComp) StopContext(ctx context.Context) error {
+	return m.stopF(ctx)
+}
+
+// mixedComp is a component implementing [Component.Start] and [StopperContext].
+type mixedComp struct {
+	stopF func(ctx context.Context) error
+}
+
+func (m mixedComp) String() string                        { return "mixedComp" }
+func (m mixedComp) Start() error                          { return nil }
+func (m mixedComp) Stop() error                           { return nil }
+func (m mixedComp) StopContext(ctx context.Context) error { return m.stopF(ctx) }
+
+func TestContextComponents(t *testing.T) {
+	t.Run("start receives the context of the app", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			var startCtx context.Context
+			a := New(WithStopTimeout(time.Second))
+			a.Register(&ctxComp{
+				startF: func(ctx context.Context) error { startCtx = ctx; return nil },
+				stopF:  func(ctx context.Context) error { return nil },
+			})
+			if err := startCtx.Err(); err != nil {
+				t.Fatalf("expected the start context to be alive but got: %s", err)
+			}
+			go a.Stop()
+			a.Start()
+			if err := startCtx.Err(); !errors.Is(err, context.Canceled) {
+				t.Errorf("got a different value than the wanted one. expected: %v; got: %v", context.Canceled, err)
+			}
+		})
+	})
+	t.Run("stop observes the deadline of the stop timeout", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			var (
+				stopErr     error
+				stopStarted time.Time
+				stopEnded   time.Time
+			)
+			a := New(WithStopTimeout(2 * time.Second))
+			a.Register(&ctxComp{
+				startF: func(ctx context.Context) error { return nil },
+				stopF: func(ctx context.Context) error {
+					stopStarted = time.Now()
+					select {
+					case <-time.After(time.Minute): // a wedged cleanup
+					case <-ctx.Done():
+						stopErr = ctx.Err()
+					}
+					stopEnded = time.Now()
+					return stopErr
+				},
+			})
+			go a.Stop()
+			a.Start()
+			if !errors.Is(stopErr, context.DeadlineExceeded) {
+				t.Errorf("got a different value than the wanted one. expected: %v; got: %v", context.DeadlineExceeded, stopErr)
+			}
+			if got, want := stopEnded.Sub(stopStarted), 2*time.Second; got != want {
+				t.Errorf("got a different value than the wanted one. expected: %s; got: %s", want, got)
+			}
+		})
+	})
+	t.Run("mixed variants", func(t *testing.T) {
+		var stopped bool
+		a := New()
+		a.Register(&mixedComp{stopF: func(ctx context.Context) error {
+			stopped = true
+			return nil
+		}})
+		go a.Stop()
+		a.Start()
+		if !stopped {
+			t.Errorf("expected the context aware stop to be called")
+		}
+	})
+}
+
+func TestCleanupRecoversStopPanics(t *testing.T) {
+	run := func(t *testing.T, opts ...Option) {
+		h := logtest.NewHandler()
+		a := New(append([]Option{WithLogger(slog.New(h))}, opts...)...)
+		var stopped []string
+		var m sync.Mutex
+		stop := func(name string) func() error {
+			return func() error {
+				m.Lock()
+				defer m.Unlock()
+				stopped = append(stopped, name)
+				return nil
+			}
+		}
+		a.Register(&mockComp{name: "db", startF: func() error { return nil }, stopF: stop("db")})
+		a.Register(&mockComp{name: "cache", startF: func() error { return nil }, stopF: func() error {
+			var counts map[string]int
+			counts["key"]++
+			return nil
+		}})
+		a.Register(&mockComp{name: "http", startF: func() error { return nil }, stopF: stop("http")})
+		a.cleanup()
+
+		if want := []string{"http", "db"}; !slices.Equal(stopped, want) {
+			t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, stopped)
+		}
+		want := `component "cache" failed to stop: panic: assignment to entry in nil map`
+		if got := a.cleanupError(); got == nil || got.Error() != want {
+			t.Errorf("got a different value than the wanted one. expected: %q; got: %v", want, got)
+		}
+		attrs := h.AttrsFor("component panicked while stopping")
+		if got, want := attrs["component"].String(), "cache"; got != want {
+			t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+		}
+		if !strings.Contains(attrs["stack"].String(), "TestCleanupRecoversStopPanics") {
+			t.Errorf("expected the stack of the panic to be logged but got:\n%s", attrs["stack"])
+		}
+	}
+	t.Run("sequential stop", func(t *testing.T) {
+		run(t)
+	})
+	t.Run("parallel stop", func(t *testing.T) {
+		run(t, WithParallelStop(time.Second))
+	})
+}
+
+func TestCleanupFlushesAsyncLogs(t *testing.T) {
+	var b bytes.Buffer
+	h := logging.NewAsyncHandler(slog.NewTextHandler(&b, &slog.HandlerOptions{Level: slog.LevelDebug}), 100)
+	defer func() { _ = h.Close(context.Background()) }()
+	a := New()
+	a.logger = slog.New(h)
+	a.Register(&mockComp{startF: func() error { return nil }, stopF: func() error { return nil }})
+	a.cleanup()
+	if want := "component registered successfully"; !strings.Contains(b.String(), want) {
+		t.Errorf("expected the queued logs to be written on cleanup but got:\n%s", b.String())
+	}
+}
+
+func TestWithLogger(t *testing.T) {
+	t.Run("routes the lifecycle logs", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			global := logtest.Install(t)
+			h := logtest.NewHandler()
+			a := New(WithLogger(slog.New(h)))
+			a.signalsDisabled = true
+			a.Register(&mockComp{name: "db", startF: func() error { return nil }, stopF: func() error { return nil }})
+			go func() {
+				synctest.Wait()
+				a.Stop()
+			}()
+			a.Start()
+
+			for _, msg := range []string{"component registered successfully", "app closing triggered", "component stopped"} {
+				if !h.ContainsMessage(msg) {
+					t.Errorf("expected the configured logger to receive %q", msg)
+				}
+			}
+			if got, want := h.AttrsFor("component stopped")["component"].String(), "db"; got != want {
+				t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+			}
+			if got := global.Records(); len(got) != 0 {
+				t.Errorf("expected no record to reach the default logger but got %d", len(got))
+			}
+		})
+	})
+	t.Run("nil falls back on the default logger", func(t *testing.T) {
+		global := logtest.Install(t)
+		a := New(WithLogger(nil))
+		a.Register(&mockComp{startF: func() error { return nil }, stopF: func() error { return nil }})
+		a.cleanup()
+		if !global.ContainsMessage("component registered successfully") {
+			t.Errorf("expected the default logger to be used")
+		}
+	})
+}
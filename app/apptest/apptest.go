@@ -0,0 +1,196 @@
+// Package apptest provides helpers to exercise the lifecycle of an [app.App] in tests, including the ones running
+// inside a [testing/synctest] bubble. The stubs created by a [Recorder] allow to assert on the order in which the app
+// starts and stops the components.
+package apptest
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yottta/go-core/app"
+	"github.com/yottta/go-core/app/internal/lifecycle"
+)
+
+// readyTimeout is how long [Run] waits for the app to be started.
+const readyTimeout = 5 * time.Second
+
+// New creates an [app.App] suitable for tests: it does not listen on system signals, which cannot be done inside a
+// [testing/synctest] bubble, and it logs to the output of t, at debug level. The given options are applied after
+// these defaults, allowing to override them (ie: [app.WithLogger] to assert on the logs).
+func New(t testing.TB, opts ...app.Option) *app.App {
+	t.Helper()
+	l := slog.New(slog.NewTextHandler(t.Output(), &slog.HandlerOptions{Level: slog.LevelDebug}))
+	return app.New(append([]app.Option{app.WithSignals(), app.WithLogger(l)}, opts...)...)
+}
+
+// Run starts a in a separate goroutine, calls during once the app is started, with the context given to the
+// callbacks of [app.App.OnStart], and stops the app afterward, waiting for [app.App.Start] to return.
+//
+// The test fails when the app does not start in time or stops before during is called, when a [app.Runner] failed,
+// when the components fail to stop or when the cleanup does not finish within the stop timeout of the app. The
+// errors reported name the components responsible.
+func Run(t testing.TB, a *app.App, during func(ctx context.Context)) {
+	t.Helper()
+	var once sync.Once
+	readyCh := make(chan context.Context, 1)
+	a.OnStart(func(ctx context.Context) {
+		// the callback stays registered for the next runs of the app
+		once.Do(func() { readyCh <- ctx })
+	})
+	doneCh := make(chan any, 1)
+	go func() {
+		defer func() {
+			doneCh <- recover()
+		}()
+		a.Start()
+	}()
+
+	select {
+	case ctx := <-readyCh:
+		if during != nil {
+			during(ctx)
+		}
+	case r := <-doneCh:
+		if r != nil {
+			t.Fatalf("app panicked on start: %v", r)
+		}
+		t.Fatalf("app stopped before being started: %v", a.Err())
+	case <-time.After(readyTimeout):
+		t.Fatalf("app did not start within %s", readyTimeout)
+	}
+
+	if err := a.StopE(); err != nil {
+		// the cleanup might be still in progress, so [app.App.Start] is not waited for
+		t.Fatalf("app did not stop cleanly: %s", err)
+	}
+	if r := <-doneCh; r != nil {
+		t.Fatalf("app panicked on stop: %v", r)
+	}
+	if err := a.Err(); err != nil {
+		t.Errorf("app failed: %s", err)
+	}
+}
+
+// EventKind describes what happened to a [Stub].
+type EventKind = lifecycle.EventKind
+
+const (
+	EventStart = lifecycle.EventStart
+	EventStop  = lifecycle.EventStop
+)
+
+// Event is recorded by a [Recorder] for each start and stop of its stubs.
+type Event = lifecycle.Event
+
+// Recorder records, in order, the starts and the stops of the stubs created with [Recorder.Stub], so the tests can
+// assert on the order in which the app called the components.
+type Recorder struct {
+	rec lifecycle.Recorder
+}
+
+// NewRecorder creates an empty [Recorder].
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Stub creates a new [Stub] with the given name, recording its starts and stops into r.
+func (r *Recorder) Stub(name string) *Stub {
+	return &Stub{Name: name, r: r}
+}
+
+// Events returns a copy of all the events recorded so far.
+func (r *Recorder) Events() []Event {
+	return r.rec.Events()
+}
+
+// AssertStartOrder fails the test if the stubs were not started in the given order.
+func (r *Recorder) AssertStartOrder(t testing.TB, names ...string) {
+	t.Helper()
+	r.rec.AssertOrder(t, EventStart, names)
+}
+
+// AssertStopOrder fails the test if the stubs were not stopped in the given order.
+func (r *Recorder) AssertStopOrder(t testing.TB, names ...string) {
+	t.Helper()
+	r.rec.AssertOrder(t, EventStop, names)
+}
+
+func (r *Recorder) record(name string, kind EventKind) {
+	if r == nil {
+		return
+	}
+	r.rec.Record(name, kind)
+}
+
+// Stub is a component recording its calls. Its start and stop succeed, unless configured otherwise with StartFn and
+// StopFn, which must be set before the stub is registered.
+type Stub struct {
+	Name    string
+	StartFn func(ctx context.Context) error
+	StopFn  func(ctx context.Context) error
+
+	// r is the recorder of the order of the calls, when the stub was created by a [Recorder]
+	r *Recorder
+
+	m                sync.Mutex
+	started, stopped int
+}
+
+// StubComponent creates a new [Stub] with the given name. Use [Recorder.Stub] to record also the order of the
+// calls among multiple stubs.
+func StubComponent(name string) *Stub {
+	return &Stub{Name: name}
+}
+
+func (s *Stub) String() string {
+	return s.Name
+}
+
+// Start is the same as [Stub.StartContext] with [context.Background].
+func (s *Stub) Start() error {
+	return s.StartContext(context.Background())
+}
+
+func (s *Stub) StartContext(ctx context.Context) error {
+	s.m.Lock()
+	s.started++
+	s.m.Unlock()
+	s.r.record(s.Name, EventStart)
+	if s.StartFn != nil {
+		return s.StartFn(ctx)
+	}
+	return nil
+}
+
+// Stop is the same as [Stub.StopContext] with [context.Background].
+func (s *Stub) Stop() error {
+	return s.StopContext(context.Background())
+}
+
+func (s *Stub) StopContext(ctx context.Context) error {
+	s.m.Lock()
+	s.stopped++
+	s.m.Unlock()
+	s.r.record(s.Name, EventStop)
+	if s.StopFn != nil {
+		return s.StopFn(ctx)
+	}
+	return nil
+}
+
+// Started returns how many times the stub was started.
+func (s *Stub) Started() int {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.started
+}
+
+// Stopped returns how many times the stub was stopped.
+func (s *Stub) Stopped() int {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.stopped
+}
@@ -0,0 +1,162 @@
+package apptest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/yottta/go-core/app"
+)
+
+// recordingT records the failures of the test instead of failing it.
+type recordingT struct {
+	testing.TB
+
+	m        sync.Mutex
+	failures []string
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Errorf(format string, args ...any) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingT) Fatalf(format string, args ...any) {
+	r.Errorf(format, args...)
+	runtime.Goexit()
+}
+
+// run calls [Run] with a recordingT, returning the failures reported.
+func run(t *testing.T, a *app.App, during func(ctx context.Context)) []string {
+	rt := &recordingT{TB: t}
+	doneCh := make(chan struct{})
+	go func() {
+		defer close(doneCh)
+		Run(rt, a, during)
+	}()
+	<-doneCh
+	return rt.failures
+}
+
+func TestRun(t *testing.T) {
+	t.Run("components are started and stopped", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			a := New(t)
+			r := NewRecorder()
+			db, http := r.Stub("db"), r.Stub("http")
+			a.Register(db)
+			a.Register(http)
+			var called bool
+			Run(t, a, func(ctx context.Context) {
+				called = true
+				if err := ctx.Err(); err != nil {
+					t.Errorf("expected the context to not be done while the app runs but got: %s", err)
+				}
+				if got, want := db.Stopped(), 0; got != want {
+					t.Errorf("got a different value than the wanted one. expected: %d; got: %d", want, got)
+				}
+			})
+			if !called {
+				t.Fatalf("expected the during function to be called")
+			}
+			for _, s := range []*Stub{db, http} {
+				if got, want := s.Started(), 1; got != want {
+					t.Errorf("got a different value than the wanted one. expected: %d; got: %d", want, got)
+				}
+				if got, want := s.Stopped(), 1; got != want {
+					t.Errorf("got a different value than the wanted one. expected: %d; got: %d", want, got)
+				}
+			}
+			r.AssertStartOrder(t, "db", "http")
+			r.AssertStopOrder(t, "http", "db")
+		})
+	})
+	t.Run("the app can be run again", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			a := New(t)
+			db := StubComponent("db")
+			a.Register(db)
+			Run(t, a, nil)
+			Run(t, a, nil)
+			if got, want := db.Started(), 2; got != want {
+				t.Errorf("got a different value than the wanted one. expected: %d; got: %d", want, got)
+			}
+		})
+	})
+	t.Run("failing stop is reported", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			a := New(t)
+			db := StubComponent("db")
+			db.StopFn = func(context.Context) error { return errors.New("connection reset") }
+			a.Register(db)
+			got := run(t, a, nil)
+			want := []string{`app did not stop cleanly: component "db" failed to stop: connection reset`}
+			if fmt.Sprint(got) != fmt.Sprint(want) {
+				t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+			}
+		})
+	})
+	t.Run("hung stop is reported", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			a := New(t, app.WithStopTimeout(time.Second))
+			releaseCh := make(chan struct{})
+			db := StubComponent("db")
+			db.StopFn = func(context.Context) error { <-releaseCh; return nil }
+			a.Register(db)
+			got := run(t, a, nil)
+			want := []string{"app did not stop cleanly: app cleanup did not finish within 1s, still stopping: [db (1s)]"}
+			if fmt.Sprint(got) != fmt.Sprint(want) {
+				t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+			}
+			close(releaseCh)
+			synctest.Wait()
+		})
+	})
+}
+
+func TestRecorder(t *testing.T) {
+	t.Run("records the events in chronological order", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			a := New(t)
+			r := NewRecorder()
+			http := r.Stub("http")
+			http.StopFn = func(context.Context) error { <-time.After(time.Second); return nil }
+			a.Register(r.Stub("db"))
+			a.Register(http)
+			Run(t, a, nil)
+
+			events := r.Events()
+			want := []string{"start db", "start http", "stop http", "stop db"}
+			var got []string
+			for _, e := range events {
+				got = append(got, fmt.Sprintf("%s %s", e.Kind, e.Component))
+			}
+			if fmt.Sprint(got) != fmt.Sprint(want) {
+				t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+			}
+			if got, want := events[3].At.Sub(events[2].At), time.Second; got != want {
+				t.Errorf("got a different value than the wanted one. expected: %s; got: %s", want, got)
+			}
+		})
+	})
+	t.Run("wrong order is reported", func(t *testing.T) {
+		r := NewRecorder()
+		_ = r.Stub("db").Start()
+		_ = r.Stub("http").Start()
+		rt := &recordingT{TB: t}
+		r.AssertStartOrder(rt, "http", "db")
+		r.AssertStopOrder(rt)
+		want := []string{"wrong start order of the components.\nexpected: [http db]\ngot: [db http]"}
+		if fmt.Sprint(rt.failures) != fmt.Sprint(want) {
+			t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, rt.failures)
+		}
+	})
+}
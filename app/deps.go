@@ -0,0 +1,150 @@
+package app
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Dependent is an optional interface for the components that need other components to be started before them and
+// to be stopped after them. DependsOn returns the names of these components, as returned by [Component.String].
+//
+// The dependencies of a component given to [App.Register] must be already registered, while the ones of a component
+// given to [App.RegisterParallel] can be also part of the same call, in which case the components are started in
+// the order required by their dependencies. On cleanup, a component is always stopped before its dependencies.
+type Dependent interface {
+	DependsOn() []string
+}
+
+func dependencies(c fmt.Stringer) []string {
+	if d, ok := c.(Dependent); ok {
+		return d.DependsOn()
+	}
+	return nil
+}
+
+// checkDependencies returns an error when any of the dependencies of c is neither registered nor part of batch.
+func (a *App) checkDependencies(c fmt.Stringer, batch []string) error {
+	for _, dep := range dependencies(c) {
+		if _, ok := a.Component(dep); ok || slices.Contains(batch, dep) {
+			continue
+		}
+		return fmt.Errorf("component %q depends on %q, which is not registered", c.String(), dep)
+	}
+	return nil
+}
+
+// startWaves splits the components registered together into waves of components that can be started concurrently,
+// each wave depending only on the previous ones. This returns an error describing the path of any dependency cycle.
+func startWaves[C fmt.Stringer](cs []C) ([][]int, error) {
+	index := make(map[string]int, len(cs))
+	for i, c := range cs {
+		index[c.String()] = i
+	}
+	const unvisited, visiting = -2, -1
+	levels := make([]int, len(cs))
+	for i := range levels {
+		levels[i] = unvisited
+	}
+	var (
+		path  []string
+		visit func(i int) (int, error)
+	)
+	visit = func(i int) (int, error) {
+		switch levels[i] {
+		case visiting:
+			start := slices.Index(path, cs[i].String())
+			cycle := append(slices.Clone(path[start:]), cs[i].String())
+			return 0, fmt.Errorf("dependency cycle between the components: %s", strings.Join(cycle, " -> "))
+		case unvisited:
+		default:
+			return levels[i], nil
+		}
+		levels[i] = visiting
+		path = append(path, cs[i].String())
+		level := 0
+		for _, dep := range dependencies(cs[i]) {
+			j, ok := index[dep]
+			if !ok {
+				continue // already registered
+			}
+			l, err := visit(j)
+			if err != nil {
+				return 0, err
+			}
+			level = max(level, l+1)
+		}
+		path = path[:len(path)-1]
+		levels[i] = level
+		return level, nil
+	}
+	var waves [][]int
+	for i := range cs {
+		level, err := visit(i)
+		if err != nil {
+			return nil, err
+		}
+		for len(waves) <= level {
+			waves = append(waves, nil)
+		}
+		waves[level] = append(waves[level], i)
+	}
+	for _, w := range waves {
+		slices.Sort(w)
+	}
+	return waves, nil
+}
+
+// sortForStop reorders the registered components so that the groups are stopped in the reverse order of their
+// creation and, within a group, the components are stopped in the reverse order of their registration. The components
+// registered together keep their registration order, unless a component must be stopped before its dependencies.
+// This is the order of both the sequential and the parallel stop (see [WithParallelStop]).
+func (a *App) sortForStop() {
+	a.componentsM.Lock()
+	defer a.componentsM.Unlock()
+	order := make([]int, len(a.components))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(i, j int) int {
+		return cmp.Or(cmp.Compare(a.phases[j], a.phases[i]), cmp.Compare(a.batches[j], a.batches[i]))
+	})
+	var (
+		remaining        = make([]Component, len(order))
+		remainingBatches = make([]int, len(order))
+		remainingPhases  = make([]int, len(order))
+	)
+	for k, i := range order {
+		remaining[k], remainingBatches[k], remainingPhases[k] = a.components[i], a.batches[i], a.phases[i]
+	}
+	a.components = a.components[:0]
+	a.batches = a.batches[:0]
+	a.phases = a.phases[:0]
+	// since the components depend only on the ones of their own group or of the earlier ones, the dependencies
+	// never reorder the groups
+	for len(remaining) > 0 {
+		next := 0
+		for i, c := range remaining {
+			if !hasDependents(c, remaining) {
+				next = i
+				break
+			}
+		}
+		a.components = append(a.components, remaining[next])
+		a.batches = append(a.batches, remainingBatches[next])
+		a.phases = append(a.phases, remainingPhases[next])
+		remaining = slices.Delete(remaining, next, next+1)
+		remainingBatches = slices.Delete(remainingBatches, next, next+1)
+		remainingPhases = slices.Delete(remainingPhases, next, next+1)
+	}
+}
+
+func hasDependents(c Component, cs []Component) bool {
+	for _, other := range cs {
+		if slices.Contains(dependencies(other), c.String()) {
+			return true
+		}
+	}
+	return false
+}
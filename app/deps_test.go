@@ -0,0 +1,108 @@
+package app
+
+import (
+	"slices"
+	"sync"
+	"testing"
+)
+
+// depComp is a component implementing [Dependent] that records its start and stop.
+type depComp struct {
+	name string
+	deps []string
+
+	m      *sync.Mutex
+	events *[]string
+}
+
+func (c *depComp) String() string      { return c.name }
+func (c *depComp) DependsOn() []string { return c.deps }
+func (c *depComp) Start() error        { c.record("start " + c.name); return nil }
+func (c *depComp) Stop() error         { c.record("stop " + c.name); return nil }
+
+func (c *depComp) record(e string) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	*c.events = append(*c.events, e)
+}
+
+func TestDependencies(t *testing.T) {
+	newComps := func() (func(name string, deps ...string) *depComp, *[]string) {
+		var (
+			m      sync.Mutex
+			events []string
+		)
+		return func(name string, deps ...string) *depComp {
+			return &depComp{name: name, deps: deps, m: &m, events: &events}
+		}, &events
+	}
+	t.Run("diamond registered together", func(t *testing.T) {
+		newComp, events := newComps()
+		a := New()
+		a.signalsDisabled = true
+		// db <- cache, queue <- http
+		a.RegisterParallel(
+			newComp("http", "cache", "queue"),
+			newComp("queue", "db"),
+			newComp("cache", "db"),
+			newComp("db"),
+		)
+		starts := slices.Clone(*events)
+		if got, want := starts[0], "start db"; got != want {
+			t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+		}
+		if got := starts[1:3]; !slices.Contains(got, "start cache") || !slices.Contains(got, "start queue") {
+			t.Errorf("expected cache and queue to be started after db but got: %v", starts)
+		}
+		if got, want := starts[3], "start http"; got != want {
+			t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+		}
+
+		go a.Stop()
+		a.Start()
+		// the components registered together are stopped in the registration order when not constrained
+		want := []string{"stop http", "stop queue", "stop cache", "stop db"}
+		if got := (*events)[4:]; !slices.Equal(got, want) {
+			t.Errorf("wrong stop order.\nexpected: %v\ngot: %v", want, got)
+		}
+	})
+	t.Run("diamond registered one by one", func(t *testing.T) {
+		newComp, events := newComps()
+		a := New()
+		a.signalsDisabled = true
+		a.Register(newComp("db"))
+		a.Register(newComp("cache", "db"))
+		a.RegisterFunc("metrics", nil, nil)
+		a.Register(newComp("queue", "db"))
+		a.Register(newComp("http", "cache", "queue"))
+		go a.Stop()
+		a.Start()
+		want := []string{
+			"start db", "start cache", "start queue", "start http",
+			"stop http", "stop queue", "stop cache", "stop db",
+		}
+		if !slices.Equal(*events, want) {
+			t.Errorf("wrong order of events.\nexpected: %v\ngot: %v", want, *events)
+		}
+		if got, want := a.Components(), []string(nil); !slices.Equal(got, want) {
+			t.Errorf("expected no components after the cleanup but got: %v", got)
+		}
+	})
+	t.Run("unknown dependency", func(t *testing.T) {
+		newComp, _ := newComps()
+		a := New()
+		defer expectPanic(t, `component "cache" depends on "db", which is not registered`)
+		a.Register(newComp("cache", "db"))
+	})
+	t.Run("cycle", func(t *testing.T) {
+		newComp, events := newComps()
+		a := New()
+		defer func() {
+			if len(*events) > 0 {
+				t.Errorf("expected no component to be started but got: %v", *events)
+			}
+		}()
+		defer expectPanic(t, "dependency cycle between the components: a -> b -> c -> a")
+		a.RegisterParallel(newComp("a", "b"), newComp("b", "c"), newComp("c", "a"))
+	})
+}
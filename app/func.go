@@ -0,0 +1,39 @@
+package app
+
+// ComponentFunc builds a [Component] out of a start and a stop function, whose [Component.String] returns name.
+// A nil start or stop is a no-op that never fails.
+// This panics when name is empty since it is used to identify the component in the logs.
+func ComponentFunc(name string, start, stop func() error) Component {
+	if name == "" {
+		panic("app: the name of the component cannot be empty")
+	}
+	return &funcComponent{name: name, start: start, stop: stop}
+}
+
+// RegisterFunc is the same as [App.Register] with the [Component] built by [ComponentFunc].
+func (a *App) RegisterFunc(name string, start, stop func() error, opts ...RegisterOption) {
+	a.Register(ComponentFunc(name, start, stop), opts...)
+}
+
+type funcComponent struct {
+	name        string
+	start, stop func() error
+}
+
+func (c *funcComponent) String() string {
+	return c.name
+}
+
+func (c *funcComponent) Start() error {
+	if c.start == nil {
+		return nil
+	}
+	return c.start()
+}
+
+func (c *funcComponent) Stop() error {
+	if c.stop == nil {
+		return nil
+	}
+	return c.stop()
+}
@@ -0,0 +1,45 @@
+package app
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRegisterFunc(t *testing.T) {
+	t.Run("start and stop are called", func(t *testing.T) {
+		var started, stopped bool
+		a := New()
+		a.RegisterFunc("db",
+			func() error { started = true; return nil },
+			func() error { stopped = true; return nil },
+		)
+		if !started {
+			t.Errorf("expected to have the start function called but it wasn't")
+		}
+		go a.Stop()
+		a.Start()
+		if !stopped {
+			t.Errorf("expected to have the stop function called but it wasn't")
+		}
+	})
+	t.Run("nil functions are no-ops", func(t *testing.T) {
+		a := New()
+		a.RegisterFunc("noop", nil, nil)
+		go a.Stop()
+		a.Start()
+	})
+	t.Run("the name is used by String", func(t *testing.T) {
+		if got, want := ComponentFunc("cache", nil, nil).String(), "cache"; got != want {
+			t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+		}
+	})
+	t.Run("start error", func(t *testing.T) {
+		defer expectPanic(t, `component "failing" failed to start: start failed`)
+		a := New()
+		a.RegisterFunc("failing", func() error { return errors.New("start failed") }, nil)
+	})
+	t.Run("panics on empty name", func(t *testing.T) {
+		defer expectPanic(t, "app: the name of the component cannot be empty")
+		ComponentFunc("", nil, nil)
+	})
+}
@@ -0,0 +1,86 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+)
+
+// defaultGroup is the name of the group of the components registered directly on the [App].
+const defaultGroup = "default"
+
+// Group is a phase of the lifecycle of the app (ie: infrastructure, domain services, servers), created with
+// [App.Group]. All the components of a group are started before the ones of the groups created after it and are
+// stopped after them.
+type Group struct {
+	app   *App
+	name  string
+	index int
+}
+
+// Group creates a new group of components, starting after all the groups created before it and stopping before them.
+// The components registered directly on the app belong to a default group, created together with the app, which is
+// therefore the first one to start and the last one to stop.
+//
+// Since the components are started on registration, a group is closed to new components once a component of a group
+// created after it was registered: registering a component in it afterwards panics the same way as a failing start.
+// This panics on an empty name or on the name of an existing group.
+func (a *App) Group(name string) *Group {
+	if name == "" {
+		panic("app: the group name cannot be empty")
+	}
+	a.componentsM.Lock()
+	defer a.componentsM.Unlock()
+	if slices.Contains(a.groupNames, name) {
+		panic(fmt.Sprintf("app: group %q already exists", name))
+	}
+	a.groupNames = append(a.groupNames, name)
+	return &Group{app: a, name: name, index: len(a.groupNames) - 1}
+}
+
+// Register is the same as [App.Register] but adds c to the group.
+func (g *Group) Register(c Component, opts ...RegisterOption) {
+	if err := g.RegisterE(c, opts...); err != nil {
+		panic(err)
+	}
+}
+
+// RegisterE is the same as [App.RegisterE] but adds c to the group.
+func (g *Group) RegisterE(c Component, opts ...RegisterOption) error {
+	return g.app.register(g.index, c, opts...)
+}
+
+// String returns the name of the group.
+func (g *Group) String() string {
+	return g.name
+}
+
+// enterGroup checks that no group created after the given one started already, logging the start of the group
+// when this is the first component registered in it.
+func (a *App) enterGroup(group int) error {
+	a.componentsM.Lock()
+	last := a.lastPhase
+	if group > last {
+		a.lastPhase = group
+	}
+	name, lastName := a.groupNames[group], a.groupNames[last]
+	a.componentsM.Unlock()
+	if group < last {
+		return fmt.Errorf("group %q cannot get new components after the group %q started", name, lastName)
+	}
+	if group > last {
+		a.log().With("group", name).Info("app group starting")
+	}
+	return nil
+}
+
+// logGroupStopping logs the stop of a group when the component at index i is the first one stopped from its group.
+// Expects the components sorted by [App.sortForStop].
+func (a *App) logGroupStopping(i int) {
+	if a.phases[i] == 0 || (i > 0 && a.phases[i-1] == a.phases[i]) {
+		return
+	}
+	a.componentsM.Lock()
+	name := a.groupNames[a.phases[i]]
+	a.componentsM.Unlock()
+	a.log().With("group", name).Info("app group stopping")
+}
@@ -0,0 +1,95 @@
+package app
+
+import (
+	"log/slog"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yottta/go-core/logging/logtest"
+)
+
+func TestGroups(t *testing.T) {
+	newComps := func() (func(name string) *depComp, *[]string) {
+		var (
+			m      sync.Mutex
+			events []string
+		)
+		return func(name string) *depComp {
+			return &depComp{name: name, m: &m, events: &events}
+		}, &events
+	}
+	t.Run("groups stop in the reverse order of their creation", func(t *testing.T) {
+		newComp, events := newComps()
+		h := logtest.NewHandler()
+		a := New(WithLogger(slog.New(h)))
+		infra := a.Group("infra")
+		edge := a.Group("edge")
+		a.Register(newComp("config"))
+		infra.Register(newComp("db"))
+		infra.Register(newComp("cache"))
+		edge.Register(newComp("http"))
+		a.cleanup()
+
+		want := []string{
+			"start config", "start db", "start cache", "start http",
+			"stop http", "stop cache", "stop db", "stop config",
+		}
+		if got := *events; !slices.Equal(got, want) {
+			t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+		}
+		var boundaries []string
+		for _, r := range h.Records() {
+			if r.Message == "app group starting" || r.Message == "app group stopping" {
+				boundaries = append(boundaries, r.Message+" "+r.Attrs["group"].String())
+			}
+		}
+		wantBoundaries := []string{
+			"app group starting infra", "app group starting edge",
+			"app group stopping edge", "app group stopping infra",
+		}
+		if !slices.Equal(boundaries, wantBoundaries) {
+			t.Errorf("got a different value than the wanted one. expected: %q; got: %q", wantBoundaries, boundaries)
+		}
+	})
+	t.Run("groups are kept with parallel stop", func(t *testing.T) {
+		newComp, events := newComps()
+		a := New(WithParallelStop(time.Second))
+		infra := a.Group("infra")
+		edge := a.Group("edge")
+		infra.Register(newComp("db"))
+		edge.Register(newComp("http"))
+		a.cleanup()
+
+		if got, want := (*events)[2:], []string{"stop http", "stop db"}; !slices.Equal(got, want) {
+			t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+		}
+	})
+	t.Run("a group is closed once a later one started", func(t *testing.T) {
+		newComp, _ := newComps()
+		defer expectPanic(t, `group "infra" cannot get new components after the group "edge" started`)
+		a := New()
+		infra := a.Group("infra")
+		edge := a.Group("edge")
+		edge.Register(newComp("http"))
+		infra.Register(newComp("db"))
+	})
+	t.Run("the default group is closed once a group started", func(t *testing.T) {
+		newComp, _ := newComps()
+		defer expectPanic(t, `group "default" cannot get new components after the group "infra" started`)
+		a := New()
+		a.Group("infra").Register(newComp("db"))
+		a.Register(newComp("config"))
+	})
+	t.Run("duplicated group name panics", func(t *testing.T) {
+		defer expectPanic(t, `app: group "infra" already exists`)
+		a := New()
+		a.Group("infra")
+		a.Group("infra")
+	})
+	t.Run("empty group name panics", func(t *testing.T) {
+		defer expectPanic(t, "app: the group name cannot be empty")
+		New().Group("")
+	})
+}
@@ -0,0 +1,65 @@
+package app
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+const envKeyForHardDeadline = "app_hard_deadline_subprocess"
+
+// runHardDeadlineSubprocess starts an app with a component that never stops, so only the hard deadline
+// can end the process.
+func runHardDeadlineSubprocess() int {
+	a := New(WithHardDeadline(500 * time.Millisecond))
+	a.Register(&mockComp{
+		startF: func() error { return nil },
+		stopF: func() error {
+			<-make(chan struct{}) // wedged forever
+			return nil
+		},
+	})
+	go func() {
+		<-time.After(100 * time.Millisecond)
+		a.Stop()
+	}()
+	a.Start()
+	return 0
+}
+
+func TestHardDeadline(t *testing.T) {
+	var stderr bytes.Buffer
+	cmd := exec.Command(os.Args[0])
+	cmd.Env = []string{fmt.Sprintf("%s=1", envKeyForHardDeadline)}
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("failed to start the subprocess: %s", err)
+	}
+	doneCh := make(chan error, 1)
+	go func() {
+		doneCh <- cmd.Wait()
+	}()
+
+	var err error
+	select {
+	case err = <-doneCh:
+	case <-time.After(5 * time.Second):
+		_ = cmd.Process.Kill()
+		t.Fatalf("expected the hard deadline to terminate the subprocess but it didn't")
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		t.Fatalf("expected the subprocess to exit with an error but got: %v", err)
+	}
+	if got, want := exitErr.ExitCode(), HardDeadlineExitCode; got != want {
+		t.Errorf("got a different exit code than the wanted one. expected: %d; got: %d", want, got)
+	}
+	if want := "app shutdown exceeded the hard deadline"; !strings.Contains(stderr.String(), want) {
+		t.Errorf("expected the logs to contain %q but got:\n%s", want, stderr.String())
+	}
+}
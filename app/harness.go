@@ -0,0 +1,152 @@
+package app
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/yottta/go-core/app/internal/lifecycle"
+)
+
+// Harness wraps an [App] to exercise components against a real app lifecycle in tests. The events are recorded in
+// the same way as by the stubs of the apptest package, which also supports the [testing/synctest] bubbles.
+// The app created by the harness is logging nowhere and is not listening on system signals,
+// so it can be safely used in parallel tests.
+type Harness struct {
+	*App
+
+	t testing.TB
+
+	rec lifecycle.Recorder
+
+	doneCh        chan struct{}
+	stopDuration  time.Duration
+	running, done bool
+}
+
+// EventKind describes what happened to a component tracked by the [Harness].
+type EventKind = lifecycle.EventKind
+
+const (
+	EventStart = lifecycle.EventStart
+	EventStop  = lifecycle.EventStop
+)
+
+// Event is recorded by the [Harness] for each start and stop of a [MockComponent].
+type Event = lifecycle.Event
+
+// TestHarness creates a new [Harness] whose app is stopped at the end of the test if it was started.
+func TestHarness(t testing.TB) *Harness {
+	t.Helper()
+	a := New(WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	a.signalsDisabled = true
+	h := &Harness{
+		App:    a,
+		t:      t,
+		doneCh: make(chan struct{}),
+	}
+	t.Cleanup(func() {
+		if h.running && !h.done {
+			h.Stop()
+		}
+	})
+	return h
+}
+
+// MockComponent is a [Component] that records its start and stop into the [Harness] that created it.
+// The optional StartFn and StopFn are called after the event is recorded.
+type MockComponent struct {
+	Name    string
+	StartFn func() error
+	StopFn  func() error
+
+	h *Harness
+}
+
+func (m *MockComponent) String() string {
+	return m.Name
+}
+
+func (m *MockComponent) Start() error {
+	m.h.rec.Record(m.Name, EventStart)
+	if m.StartFn != nil {
+		return m.StartFn()
+	}
+	return nil
+}
+
+func (m *MockComponent) Stop() error {
+	m.h.rec.Record(m.Name, EventStop)
+	if m.StopFn != nil {
+		return m.StopFn()
+	}
+	return nil
+}
+
+// NewMock creates a [MockComponent] tracked by the harness without registering it.
+func (h *Harness) NewMock(name string) *MockComponent {
+	return &MockComponent{Name: name, h: h}
+}
+
+// RegisterMock creates a [MockComponent] and registers it into the app.
+func (h *Harness) RegisterMock(name string) *MockComponent {
+	m := h.NewMock(name)
+	h.Register(m)
+	return m
+}
+
+// Run starts the app in a separate goroutine and returns once the app is ready.
+func (h *Harness) Run() {
+	h.t.Helper()
+	readyCh := make(chan struct{})
+	h.WhenReady(func() { close(readyCh) })
+	h.running = true
+	go func() {
+		defer close(h.doneCh)
+		h.App.Start()
+	}()
+	select {
+	case <-readyCh:
+	case <-time.After(5 * time.Second):
+		h.t.Fatalf("app did not become ready in time")
+	}
+}
+
+// Stop stops the app and waits for [App.Start] to return.
+func (h *Harness) Stop() {
+	h.t.Helper()
+	start := time.Now()
+	h.App.Stop()
+	<-h.doneCh
+	h.stopDuration = time.Since(start)
+	h.done = true
+}
+
+// Events returns a copy of all the events recorded so far.
+func (h *Harness) Events() []Event {
+	return h.rec.Events()
+}
+
+// AssertStartOrder fails the test if the components were not started in the given order.
+func (h *Harness) AssertStartOrder(names ...string) {
+	h.t.Helper()
+	h.rec.AssertOrder(h.t, EventStart, names)
+}
+
+// AssertStopOrder fails the test if the components were not stopped in the given order.
+func (h *Harness) AssertStopOrder(names ...string) {
+	h.t.Helper()
+	h.rec.AssertOrder(h.t, EventStop, names)
+}
+
+// AssertStoppedWithin fails the test if the last call of [Harness.Stop] took longer than the given duration.
+func (h *Harness) AssertStoppedWithin(d time.Duration) {
+	h.t.Helper()
+	if !h.done {
+		h.t.Fatalf("app was not stopped")
+	}
+	if h.stopDuration > d {
+		h.t.Errorf("expected the app to stop within %s but it took %s", d, h.stopDuration)
+	}
+}
@@ -0,0 +1,54 @@
+package app
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestHarnessLifecycle(t *testing.T) {
+	t.Run("records start and stop ordering", func(t *testing.T) {
+		h := TestHarness(t)
+		h.RegisterMock("db")
+		h.RegisterMock("http")
+		h.Run()
+		h.AssertStartOrder("db", "http")
+		h.Stop()
+		h.AssertStopOrder("http", "db")
+		h.AssertStoppedWithin(time.Second)
+
+		events := h.Events()
+		if got, want := len(events), 4; got != want {
+			t.Fatalf("expected %d events but got %d", want, got)
+		}
+		for i := 1; i < len(events); i++ {
+			if events[i].At.Before(events[i-1].At) {
+				t.Errorf("events are not recorded in chronological order: %v", events)
+			}
+		}
+	})
+	t.Run("slow component delays stop", func(t *testing.T) {
+		h := TestHarness(t)
+		m := h.NewMock("slow")
+		m.StopFn = func() error {
+			<-time.After(200 * time.Millisecond)
+			return fmt.Errorf("stopped with error")
+		}
+		h.Register(m)
+		h.Run()
+		h.Stop()
+		h.AssertStopOrder("slow")
+		if h.stopDuration < 200*time.Millisecond {
+			t.Errorf("expected the stop to wait for the slow component but took %s", h.stopDuration)
+		}
+	})
+	t.Run("app is stopped on cleanup", func(t *testing.T) {
+		var h *Harness
+		t.Run("inner", func(t *testing.T) {
+			h = TestHarness(t)
+			h.RegisterMock("comp")
+			h.Run()
+		})
+		h.AssertStopOrder("comp")
+	})
+}
@@ -0,0 +1,82 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// healthTimeout bounds the [HealthReporter.Healthy] call of each component.
+const healthTimeout = 2 * time.Second
+
+// HealthReporter is an optional interface for the components that can report their health (ie: a database
+// connection pool pinging the database). Use [App.Health] or [HealthHandler] to check all the components at once.
+type HealthReporter interface {
+	Healthy(ctx context.Context) error
+}
+
+// Health checks concurrently the health of the registered components that implement [HealthReporter], each check
+// being bounded by a short timeout. The result is keyed by the [Component.String] of the components, with a nil
+// error for the healthy ones. The components that do not implement [HealthReporter] are considered healthy and
+// are not part of the result.
+func (a *App) Health(ctx context.Context) map[string]error {
+	var (
+		wg  sync.WaitGroup
+		m   sync.Mutex
+		res = map[string]error{}
+	)
+	for _, c := range a.registered() {
+		h, ok := c.(HealthReporter)
+		if !ok {
+			continue
+		}
+		wg.Go(func() {
+			err := checkHealth(ctx, h)
+			m.Lock()
+			defer m.Unlock()
+			res[c.String()] = err
+		})
+	}
+	wg.Wait()
+	return res
+}
+
+// checkHealth calls [HealthReporter.Healthy], giving up when it does not return in [healthTimeout].
+func checkHealth(ctx context.Context, h HealthReporter) error {
+	ctx, cancel := context.WithTimeout(ctx, healthTimeout)
+	defer cancel()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- h.Healthy(ctx)
+	}()
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("health check did not finish: %w", ctx.Err())
+	}
+}
+
+// HealthHandler returns a handler that responds with 200 when all the components of the app are healthy, as
+// reported by [App.Health], meant to be used as a readiness probe. Otherwise, it responds with 503 and a JSON
+// object with the errors of the unhealthy components, keyed by their names.
+func HealthHandler(a *App) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		failing := map[string]string{}
+		for name, err := range a.Health(r.Context()) {
+			if err != nil {
+				failing[name] = err.Error()
+			}
+		}
+		if len(failing) == 0 {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(failing)
+	})
+}
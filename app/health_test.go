@@ -0,0 +1,99 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+// healthComp is a component implementing [HealthReporter].
+type healthComp struct {
+	mockComp
+	name     string
+	healthyF func(ctx context.Context) error
+}
+
+func (m healthComp) String() string {
+	return m.name
+}
+
+func (m healthComp) Healthy(ctx context.Context) error {
+	return m.healthyF(ctx)
+}
+
+func newHealthComp(name string, healthyF func(ctx context.Context) error) *healthComp {
+	noop := func() error { return nil }
+	return &healthComp{
+		mockComp: mockComp{startF: noop, stopF: noop},
+		name:     name,
+		healthyF: healthyF,
+	}
+}
+
+func TestHealth(t *testing.T) {
+	t.Run("reports only the components implementing HealthReporter", func(t *testing.T) {
+		a := New()
+		a.RegisterFunc("plain", nil, nil)
+		a.Register(newHealthComp("db", func(ctx context.Context) error { return nil }))
+		a.Register(newHealthComp("cache", func(ctx context.Context) error { return errors.New("connection refused") }))
+
+		res := a.Health(context.Background())
+		if got, want := len(res), 2; got != want {
+			t.Fatalf("got a different value than the wanted one. expected: %d; got: %d (%v)", want, got, res)
+		}
+		if err, ok := res["db"]; !ok || err != nil {
+			t.Errorf("expected db to be healthy but got: %v", err)
+		}
+		if err := res["cache"]; err == nil || err.Error() != "connection refused" {
+			t.Errorf("expected cache to be unhealthy but got: %v", err)
+		}
+	})
+	t.Run("hanging checks time out", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			release := make(chan struct{})
+			defer close(release)
+			a := New()
+			a.Register(newHealthComp("stuck", func(ctx context.Context) error { <-release; return nil }))
+			begin := time.Now()
+			res := a.Health(context.Background())
+			if got, want := time.Since(begin), healthTimeout; got != want {
+				t.Errorf("got a different value than the wanted one. expected: %s; got: %s", want, got)
+			}
+			if err := res["stuck"]; !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("got a different value than the wanted one. expected: %v; got: %v", context.DeadlineExceeded, err)
+			}
+		})
+	})
+}
+
+func TestHealthHandler(t *testing.T) {
+	var healthErr error
+	a := New()
+	a.RegisterFunc("plain", nil, nil)
+	a.Register(newHealthComp("db", func(ctx context.Context) error { return healthErr }))
+
+	rec := httptest.NewRecorder()
+	HealthHandler(a).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	if got, want := rec.Code, http.StatusOK; got != want {
+		t.Errorf("got a different value than the wanted one. expected: %d; got: %d", want, got)
+	}
+
+	healthErr = errors.New("db is down")
+	rec = httptest.NewRecorder()
+	HealthHandler(a).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	if got, want := rec.Code, http.StatusServiceUnavailable; got != want {
+		t.Errorf("got a different value than the wanted one. expected: %d; got: %d", want, got)
+	}
+	var body map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode the body: %s", err)
+	}
+	if got, want := body["db"], "db is down"; got != want {
+		t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+	}
+}
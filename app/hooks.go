@@ -0,0 +1,97 @@
+package app
+
+import (
+	"context"
+	"runtime/debug"
+	"slices"
+	"sync"
+)
+
+type hooks struct {
+	m sync.Mutex
+
+	onStart []func(ctx context.Context)
+	onStop  []func()
+
+	// startCtx is the context given to the start hooks, set once they ran.
+	startCtx context.Context
+	stopped  bool
+}
+
+// OnStart registers a callback that is called inside [App.Start], right before it starts blocking and after the
+// callbacks registered with [App.WhenReady]. The context given is done once the app starts closing.
+// The callbacks are called in the order in which they were registered and their panics are recovered and logged.
+// A callback registered after the app started is called right away.
+// The callbacks are called again on each restart of the app, as described in [App.Start].
+func (a *App) OnStart(fn func(ctx context.Context)) {
+	if fn == nil {
+		return
+	}
+	a.hooks.m.Lock()
+	ctx := a.hooks.startCtx
+	a.hooks.onStart = append(a.hooks.onStart, fn)
+	a.hooks.m.Unlock()
+	if ctx != nil {
+		a.runHook("start", func() { fn(ctx) })
+	}
+}
+
+// OnStop registers a callback that is called at the beginning of the cleanup of the app, before any of the
+// components is stopped (ie: to flush the metrics).
+// The callbacks are called in the order in which they were registered and their panics are recovered and logged.
+// A callback registered after the cleanup began is called right away.
+// The callbacks are called again on each restart of the app, as described in [App.Start].
+func (a *App) OnStop(fn func()) {
+	if fn == nil {
+		return
+	}
+	a.hooks.m.Lock()
+	stopped := a.hooks.stopped
+	a.hooks.onStop = append(a.hooks.onStop, fn)
+	a.hooks.m.Unlock()
+	if stopped {
+		a.runHook("stop", fn)
+	}
+}
+
+func (a *App) runStartHooks(ctx context.Context) {
+	a.hooks.m.Lock()
+	a.hooks.startCtx = ctx
+	fns := slices.Clone(a.hooks.onStart)
+	a.hooks.m.Unlock()
+	for _, fn := range fns {
+		a.runHook("start", func() { fn(ctx) })
+	}
+}
+
+func (a *App) runStopHooks() {
+	a.hooks.m.Lock()
+	a.hooks.stopped = true
+	fns := slices.Clone(a.hooks.onStop)
+	a.hooks.m.Unlock()
+	for _, fn := range fns {
+		a.runHook("stop", fn)
+	}
+}
+
+// reset prepares the hooks for a new run of the app, once the previous one finished.
+func (h *hooks) reset() {
+	h.m.Lock()
+	defer h.m.Unlock()
+	h.startCtx = nil
+	h.stopped = false
+}
+
+// runHook calls fn, logging its panic instead of crashing the app.
+func (a *App) runHook(kind string, fn func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			a.log().
+				With("hook", kind).
+				With("panic", r).
+				With("stack", string(debug.Stack())).
+				Error("app hook panicked")
+		}
+	}()
+	fn()
+}
@@ -0,0 +1,98 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+	"testing/synctest"
+)
+
+func TestHooks(t *testing.T) {
+	t.Run("order of the hooks in the lifecycle", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			var events []string
+			a := New()
+			a.signalsDisabled = true
+			a.RegisterFunc("db", nil, func() error { events = append(events, "db stopped"); return nil })
+			a.WhenReady(func() { events = append(events, "ready") })
+			a.OnStart(func(ctx context.Context) { events = append(events, "start1") })
+			a.OnStart(func(ctx context.Context) { events = append(events, "start2") })
+			a.OnStop(func() { events = append(events, "stop1") })
+			a.OnStop(func() { events = append(events, "stop2") })
+			a.OnStart(nil)
+			a.OnStop(nil)
+
+			go a.Stop()
+			a.Start()
+			want := []string{"ready", "start1", "start2", "stop1", "stop2", "db stopped"}
+			if !slices.Equal(events, want) {
+				t.Errorf("wrong order of events.\nexpected: %v\ngot: %v", want, events)
+			}
+		})
+	})
+	t.Run("start hooks get the context of the app", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			var hookCtx context.Context
+			a := New()
+			a.signalsDisabled = true
+			a.OnStart(func(ctx context.Context) { hookCtx = ctx })
+			go a.Stop()
+			a.Start()
+			if hookCtx == nil || hookCtx.Err() == nil {
+				t.Errorf("expected the context of the start hook to be done once the app stopped")
+			}
+		})
+	})
+	t.Run("hooks registered late are called right away", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			var events []string
+			a := New()
+			a.signalsDisabled = true
+			a.WhenReady(func() {
+				// the start hooks did not run yet
+				a.OnStart(func(ctx context.Context) { events = append(events, "start") })
+			})
+			a.OnStart(func(ctx context.Context) {
+				a.OnStart(func(ctx context.Context) { events = append(events, "late start") })
+			})
+			a.OnStop(func() {
+				a.OnStop(func() { events = append(events, "late stop") })
+			})
+			go a.Stop()
+			a.Start()
+			// the hook registered by WhenReady runs after the ones registered before it
+			want := []string{"late start", "start", "late stop"}
+			if !slices.Equal(events, want) {
+				t.Errorf("wrong order of events.\nexpected: %v\ngot: %v", want, events)
+			}
+		})
+	})
+	t.Run("panics are recovered and logged", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			var b bytes.Buffer
+			var stopped bool
+			a := New()
+			a.logger = slog.New(slog.NewTextHandler(&b, nil))
+			a.signalsDisabled = true
+			a.RegisterFunc("db", nil, func() error { stopped = true; return nil })
+			a.OnStart(func(ctx context.Context) { panic("start hook failure") })
+			a.OnStop(func() { panic("stop hook failure") })
+			go a.Stop()
+			a.Start()
+			if !stopped {
+				t.Errorf("expected the components to be stopped after a stop hook panicked")
+			}
+			for _, want := range []string{
+				`msg="app hook panicked" hook=start panic="start hook failure"`,
+				`msg="app hook panicked" hook=stop panic="stop hook failure"`,
+			} {
+				if !strings.Contains(b.String(), want) {
+					t.Errorf("expected logs to contain %q but got:\n%s", want, b.String())
+				}
+			}
+		})
+	})
+}
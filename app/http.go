@@ -0,0 +1,97 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+)
+
+// HTTPServer is the contract of the servers that can be adapted into components with [HTTPComponent],
+// like the [github.com/yottta/go-core/chix.Server].
+// Start is expected to block until the server is closed, either by the cancellation of the given context or by Close.
+type HTTPServer interface {
+	Start(ctx context.Context) error
+	Close()
+}
+
+// readier is implemented by the servers that signal when they are ready to accept connections.
+type readier interface {
+	Ready() <-chan struct{}
+}
+
+// HTTPComponent adapts s into a component named name that can be given to [App.Register].
+// The server is started in its own goroutine and closed on stop, waiting for its Start to return.
+//
+// When s also implements Ready() <-chan struct{}, like the [github.com/yottta/go-core/chix.Server], the registration
+// waits for the server to be ready, returning its error when it fails to start (ie: the port is in use).
+// Otherwise, the registration does not wait and such failures stop the app right after, as with any [Runner].
+func HTTPComponent(name string, s HTTPServer) Component {
+	if name == "" {
+		panic("app: the name of the component cannot be empty")
+	}
+	return &httpComponent{name: name, s: s}
+}
+
+type httpComponent struct {
+	name string
+	s    HTTPServer
+
+	doneCh chan struct{}
+	// err is the error returned by the Start of the server, set before doneCh is closed.
+	err error
+	// reported is set when err was already returned by Run.
+	reported atomic.Bool
+}
+
+func (c *httpComponent) String() string {
+	return c.name
+}
+
+func (c *httpComponent) Start() error {
+	return c.StartContext(context.Background())
+}
+
+func (c *httpComponent) StartContext(ctx context.Context) error {
+	// the state of a previous run is dropped, since the component is started again when the app restarts
+	doneCh := make(chan struct{})
+	c.doneCh, c.err = doneCh, nil
+	c.reported.Store(false)
+	go func() {
+		defer close(doneCh)
+		// the server is closed by Stop, while ctx may be bound to the start timeout
+		c.err = c.s.Start(context.WithoutCancel(ctx))
+	}()
+	r, ok := c.s.(readier)
+	if !ok {
+		return nil
+	}
+	select {
+	case <-r.Ready():
+		return nil
+	case <-doneCh:
+		if c.err != nil {
+			return c.err
+		}
+		return fmt.Errorf("server returned before being ready")
+	}
+}
+
+// Run reports the failure of the server while the app is running.
+func (c *httpComponent) Run(ctx context.Context) error {
+	select {
+	case <-c.doneCh:
+		c.reported.Store(true)
+		return c.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *httpComponent) Stop() error {
+	c.s.Close()
+	<-c.doneCh
+	if c.reported.Load() {
+		return nil
+	}
+	return c.err
+}
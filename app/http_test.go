@@ -0,0 +1,135 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/yottta/go-core/chix"
+)
+
+// fakeServer is a [HTTPServer] blocking until its context is done or it is closed.
+type fakeServer struct {
+	startErr error
+	closeCh  chan struct{}
+}
+
+func (s *fakeServer) Start(ctx context.Context) error {
+	if s.startErr != nil {
+		return s.startErr
+	}
+	select {
+	case <-ctx.Done():
+	case <-s.closeCh:
+	}
+	return nil
+}
+
+func (s *fakeServer) Close() {
+	close(s.closeCh)
+}
+
+func TestHTTPComponent(t *testing.T) {
+	t.Run("serves until the app stops", func(t *testing.T) {
+		l, err := net.Listen("tcp", "localhost:0")
+		if err != nil {
+			t.Fatalf("failed to find a free port: %s", err)
+		}
+		port := l.Addr().(*net.TCPAddr).Port
+		_ = l.Close()
+
+		srv := (&chix.Config{Host: "localhost", Port: port}).NewServer()
+		srv.Router().Get("/ping", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("pong"))
+		})
+		a := New()
+		a.signalsDisabled = true
+		a.Register(HTTPComponent("http", srv))
+
+		// no waiting needed, the registration returns once the server is ready
+		resp, err := http.Get(fmt.Sprintf("http://localhost:%d/ping", port))
+		if err != nil {
+			t.Fatalf("expected the server to be serving but got: %s", err)
+		}
+		_ = resp.Body.Close()
+
+		go a.Stop()
+		a.Start()
+		if _, err := http.Get(fmt.Sprintf("http://localhost:%d/ping", port)); err == nil {
+			t.Errorf("expected the server to be closed after the app stopped")
+		}
+	})
+	t.Run("failing to bind fails the registration", func(t *testing.T) {
+		l, err := net.Listen("tcp", "localhost:0")
+		if err != nil {
+			t.Fatalf("failed to listen: %s", err)
+		}
+		defer func() { _ = l.Close() }()
+		srv := (&chix.Config{Host: "localhost", Port: l.Addr().(*net.TCPAddr).Port}).NewServer()
+
+		a := New()
+		defer func() {
+			r := recover()
+			err, ok := r.(error)
+			var opErr *net.OpError
+			if !ok || !errors.As(err, &opErr) {
+				t.Fatalf("expected the registration to panic with the bind error but got: %v", r)
+			}
+		}()
+		a.Register(HTTPComponent("http", srv))
+	})
+	t.Run("failure of a server without readiness stops the app", func(t *testing.T) {
+		a := New()
+		a.signalsDisabled = true
+		a.Register(HTTPComponent("http", &fakeServer{startErr: errors.New("address in use"), closeCh: make(chan struct{})}))
+		doneCh := make(chan struct{})
+		go func() {
+			defer close(doneCh)
+			a.Start()
+		}()
+		select {
+		case <-doneCh:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("expected the app to stop")
+		}
+		if got, want := fmt.Sprint(a.Err()), "component http failed: address in use"; got != want {
+			t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+		}
+	})
+	t.Run("stop closes the server", func(t *testing.T) {
+		s := &fakeServer{closeCh: make(chan struct{})}
+		c := HTTPComponent("http", s).(*httpComponent)
+		if err := c.Start(); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		if err := c.Stop(); err != nil {
+			t.Errorf("expected no error but got: %s", err)
+		}
+	})
+	t.Run("restart runs the server again", func(t *testing.T) {
+		s := &fakeServer{}
+		c := HTTPComponent("http", s).(*httpComponent)
+		for range 2 {
+			s.closeCh = make(chan struct{})
+			if err := c.Start(); err != nil {
+				t.Fatalf("expected no error but got: %s", err)
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			if err := c.Run(ctx); !errors.Is(err, context.Canceled) {
+				t.Errorf("expected the run to wait for the new server but got: %v", err)
+			}
+			if err := c.Stop(); err != nil {
+				t.Errorf("expected no error but got: %s", err)
+			}
+		}
+	})
+	t.Run("panics on empty name", func(t *testing.T) {
+		defer expectPanic(t, "app: the name of the component cannot be empty")
+		HTTPComponent("", &fakeServer{})
+	})
+}
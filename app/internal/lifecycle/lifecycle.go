@@ -0,0 +1,59 @@
+// Package lifecycle records the starts and the stops of the components, for the test helpers of [app.Harness] and
+// of the apptest package.
+package lifecycle
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// EventKind describes what happened to a recorded component.
+type EventKind string
+
+const (
+	EventStart EventKind = "start"
+	EventStop  EventKind = "stop"
+)
+
+// Event is recorded by a [Recorder] for each start and stop of a component.
+type Event struct {
+	Component string
+	Kind      EventKind
+	At        time.Time
+}
+
+// Recorder records, in order, the starts and the stops of the components.
+type Recorder struct {
+	m      sync.Mutex
+	events []Event
+}
+
+// Record adds an event of the given kind for the component.
+func (r *Recorder) Record(name string, kind EventKind) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.events = append(r.events, Event{Component: name, Kind: kind, At: time.Now()})
+}
+
+// Events returns a copy of all the events recorded so far.
+func (r *Recorder) Events() []Event {
+	r.m.Lock()
+	defer r.m.Unlock()
+	return slices.Clone(r.events)
+}
+
+// AssertOrder fails the test if the events of the given kind were not recorded for the components in the given order.
+func (r *Recorder) AssertOrder(t testing.TB, kind EventKind, want []string) {
+	t.Helper()
+	var got []string
+	for _, e := range r.Events() {
+		if e.Kind == kind {
+			got = append(got, e.Component)
+		}
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("wrong %s order of the components.\nexpected: %v\ngot: %v", kind, want, got)
+	}
+}
@@ -0,0 +1,176 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// WithParallelStop configures the app to stop concurrently the components registered together, each stop being
+// bounded by the given timeout. The components registered together are the ones given to the same
+// [App.RegisterParallel] or [App.RegisterParallelN] call, while each component given to [App.Register] is stopped on
+// its own. The groups of components are still stopped one after another, in the reverse order of their registration.
+//
+// The components whose stop returns an error or exceeds the timeout are logged together with the time they took.
+// The cleanup, and thus [App.Start], returns only once all the components stopped or timed out.
+// A timeout of 0 disables the concurrent stop.
+func WithParallelStop(perComponentTimeout time.Duration) Option {
+	return func(a *App) {
+		a.parallelStopTimeout = perComponentTimeout
+	}
+}
+
+// RegisterParallel is the same as [App.Register] but starts all the given components concurrently.
+// Use this when the components are independent of each other and the startup time matters.
+// If any of the components fails to start, all the successfully started ones, together with the ones
+// previously registered, are cleaned up and the startup panics with all the errors encountered.
+func (a *App) RegisterParallel(components ...Component) {
+	a.RegisterParallelN(len(components), components...)
+}
+
+// RegisterParallelN is the same as [App.RegisterParallel] but starts at most max components at the same time.
+// This is useful on resource constrained hosts, where starting too many components at once (ie: opening hundreds
+// of connections) can cause resource spikes.
+// A max lower than 1 is treated as 1.
+func (a *App) RegisterParallelN(max int, components ...Component) {
+	names := make([]string, len(components))
+	for i, c := range components {
+		if c == nil {
+			a.exit(fmt.Errorf("given component is nil"))
+			return
+		}
+		names[i] = c.String()
+	}
+	for _, c := range components {
+		if err := a.checkDependencies(c, names); err != nil {
+			a.exit(err)
+			return
+		}
+	}
+	waves, err := startWaves(components)
+	if err != nil {
+		a.exit(err)
+		return
+	}
+	if err := a.enterGroup(0); err != nil {
+		a.exit(err)
+		return
+	}
+	if err := a.reserve(names...); err != nil {
+		a.exit(err)
+		return
+	}
+	if max < 1 {
+		max = 1
+	}
+	var (
+		wg        sync.WaitGroup
+		sem       = make(chan struct{}, max)
+		errs      = make([]error, len(components))
+		durations = make([]time.Duration, len(components))
+		attempted = make([]bool, len(components))
+	)
+	// the components are started in waves, each one after the components it depends on
+	for _, wave := range waves {
+		for _, i := range wave {
+			c := components[i]
+			attempted[i] = true
+			wg.Go(func() {
+				sem <- struct{}{}
+				defer func() { <-sem }()
+				begin := time.Now()
+				if err := a.startWithTimeout(c, a.startTimeout); err != nil {
+					errs[i] = startError(c, err)
+				}
+				durations[i] = time.Since(begin)
+			})
+		}
+		wg.Wait()
+		if slices.ContainsFunc(errs, func(err error) bool { return err != nil }) {
+			break
+		}
+	}
+
+	// keep the registration order for the successful components to have a predictable cleanup
+	var started []Component
+	for i, c := range components {
+		if errs[i] != nil || !attempted[i] {
+			a.release(c.String())
+			continue
+		}
+		a.recordStart(c.String(), durations[i])
+		a.componentLog(c.String()).
+			With("duration", durations[i]).
+			Debug("component registered successfully")
+		started = append(started, c)
+	}
+	a.addComponents(0, started...)
+	if err := errors.Join(errs...); err != nil {
+		a.exit(err)
+		return
+	}
+	a.remember(func() error {
+		a.RegisterParallelN(max, components...)
+		return nil
+	})
+}
+
+// stopParallel stops concurrently the components of each group, one group after another, returning the errors
+// encountered.
+func (a *App) stopParallel(ctx context.Context) []error {
+	// the errors are kept in the order of the components, regardless of when they were encountered
+	errs := make([]error, len(a.components))
+	for start := 0; start < len(a.components); {
+		end := start + 1
+		for end < len(a.components) && a.batches[end] == a.batches[start] {
+			end++
+		}
+		a.logGroupStopping(start)
+		var wg sync.WaitGroup
+		for i := start; i < end; i++ {
+			c := a.components[i]
+			wg.Go(func() {
+				if err := a.stopWithTimeout(ctx, c); err != nil {
+					errs[i] = stopError(c, err)
+				}
+			})
+		}
+		wg.Wait()
+		start = end
+	}
+	return errs
+}
+
+// stopWithTimeout stops c, giving up waiting for it once the timeout configured by [WithParallelStop] elapses.
+func (a *App) stopWithTimeout(ctx context.Context, c Component) error {
+	ctx, cancel := context.WithTimeout(ctx, a.parallelStopTimeout)
+	defer cancel()
+	begin := time.Now()
+	l := a.componentLog(c.String())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- a.stopTracked(ctx, c)
+	}()
+	defer func() {
+		a.recordStop(c.String(), time.Since(begin))
+	}()
+	select {
+	case err := <-errCh:
+		if err != nil {
+			a.logStopError(c, err)
+			return err
+		}
+		l.
+			With("elapsed", time.Since(begin)).
+			Debug("component stopped")
+		return nil
+	case <-ctx.Done():
+		l.
+			With("elapsed", time.Since(begin)).
+			Warn("component did not stop in time")
+		return fmt.Errorf("did not stop within %s: %w", a.parallelStopTimeout, ctx.Err())
+	}
+}
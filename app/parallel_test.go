@@ -0,0 +1,227 @@
+package app
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestRegisterParallel(t *testing.T) {
+	t.Run("starts no more than max components at the same time", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			var running, maxRunning, started atomic.Int32
+			comps := make([]Component, 10)
+			for i := range comps {
+				comps[i] = &mockComp{
+					name: fmt.Sprintf("comp-%d", i),
+					startF: func() error {
+						n := running.Add(1)
+						for {
+							m := maxRunning.Load()
+							if n <= m || maxRunning.CompareAndSwap(m, n) {
+								break
+							}
+						}
+						<-time.After(time.Second)
+						running.Add(-1)
+						started.Add(1)
+						return nil
+					},
+					stopF: func() error { return nil },
+				}
+			}
+			a := New()
+			begin := time.Now()
+			a.RegisterParallelN(3, comps...)
+
+			if got, want := maxRunning.Load(), int32(3); got != want {
+				t.Errorf("got a different max of concurrent starts than the wanted one. expected: %d; got: %d", want, got)
+			}
+			if got, want := started.Load(), int32(10); got != want {
+				t.Errorf("got a different number of started components than the wanted one. expected: %d; got: %d", want, got)
+			}
+			if got, want := len(a.components), 10; got != want {
+				t.Errorf("got a different number of registered components than the wanted one. expected: %d; got: %d", want, got)
+			}
+			// 10 components in batches of 3 take 4 rounds
+			if got, want := time.Since(begin), 4*time.Second; got != want {
+				t.Errorf("got a different startup duration than the wanted one. expected: %s; got: %s", want, got)
+			}
+		})
+	})
+	t.Run("without a cap all components start at the same time", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			comps := make([]Component, 5)
+			for i := range comps {
+				comps[i] = &mockComp{
+					name:   fmt.Sprintf("comp-%d", i),
+					startF: func() error { <-time.After(time.Second); return nil },
+					stopF:  func() error { return nil },
+				}
+			}
+			a := New()
+			begin := time.Now()
+			a.RegisterParallel(comps...)
+			if got, want := time.Since(begin), time.Second; got != want {
+				t.Errorf("got a different startup duration than the wanted one. expected: %s; got: %s", want, got)
+			}
+		})
+	})
+	t.Run("failure cleans up the successfully started components", func(t *testing.T) {
+		var stopped atomic.Int32
+		newOk := func(name string) *mockComp {
+			return &mockComp{
+				name:   name,
+				startF: func() error { return nil },
+				stopF:  func() error { stopped.Add(1); return nil },
+			}
+		}
+		failing := &mockComp{
+			name:   "failing",
+			startF: func() error { return fmt.Errorf("failed to start") },
+			stopF: func() error {
+				t.Errorf("expected the failed component not to be stopped")
+				return nil
+			},
+		}
+		a := New()
+		defer func() {
+			if got, want := stopped.Load(), int32(2); got != want {
+				t.Errorf("got a different number of stopped components than the wanted one. expected: %d; got: %d", want, got)
+			}
+		}()
+		defer expectPanic(t, `component "failing" failed to start: failed to start`)
+		a.RegisterParallelN(2, newOk("first"), failing, newOk("second"))
+	})
+	t.Run("nil component", func(t *testing.T) {
+		a := New()
+		defer expectPanic(t, "given component is nil")
+		a.RegisterParallel(&mockComp{}, nil)
+	})
+}
+
+func TestParallelStop(t *testing.T) {
+	t.Run("components registered together are stopped concurrently", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			comps := make([]Component, 5)
+			for i := range comps {
+				comps[i] = &mockComp{
+					name:   fmt.Sprintf("comp-%d", i),
+					startF: func() error { return nil },
+					stopF:  func() error { <-time.After(2 * time.Second); return nil },
+				}
+			}
+			a := New(WithParallelStop(5*time.Second), WithStopTimeout(0))
+			a.signalsDisabled = true
+			a.RegisterParallel(comps...)
+			go a.Stop()
+			synctest.Wait()
+			begin := time.Now()
+			a.Start()
+			if got, want := time.Since(begin), 2*time.Second; got != want {
+				t.Errorf("got a different shutdown duration than the wanted one. expected: %s; got: %s", want, got)
+			}
+		})
+	})
+	t.Run("groups are stopped in the reverse registration order", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			var (
+				stoppedM sync.Mutex
+				stopped  []string
+			)
+			stop := func(name string, d time.Duration) func() error {
+				return func() error {
+					<-time.After(d)
+					stoppedM.Lock()
+					defer stoppedM.Unlock()
+					stopped = append(stopped, name)
+					return nil
+				}
+			}
+			a := New(WithParallelStop(5*time.Second), WithStopTimeout(0))
+			a.signalsDisabled = true
+			a.RegisterFunc("first", nil, stop("first", time.Second))
+			a.RegisterParallel(
+				ComponentFunc("second", nil, stop("second", 3*time.Second)),
+				ComponentFunc("third", nil, stop("third", 2*time.Second)),
+			)
+			a.RegisterFunc("fourth", nil, stop("fourth", 4*time.Second))
+			go a.Stop()
+			a.Start()
+			if want := []string{"fourth", "third", "second", "first"}; !slices.Equal(stopped, want) {
+				t.Errorf("got a different stop order than the wanted one. expected: %v; got: %v", want, stopped)
+			}
+		})
+	})
+	t.Run("same stop order as the sequential stop", func(t *testing.T) {
+		stopOrder := func(opts ...Option) []string {
+			var (
+				stoppedM sync.Mutex
+				stopped  []string
+			)
+			a := New(opts...)
+			a.signalsDisabled = true
+			for _, name := range []string{"a", "b", "c"} {
+				a.RegisterFunc(name, nil, func() error {
+					stoppedM.Lock()
+					defer stoppedM.Unlock()
+					stopped = append(stopped, name)
+					return nil
+				})
+			}
+			a.cleanup()
+			return stopped
+		}
+		sequential, parallel := stopOrder(), stopOrder(WithParallelStop(time.Second))
+		if want := []string{"c", "b", "a"}; !slices.Equal(sequential, want) {
+			t.Errorf("got a different stop order than the wanted one. expected: %v; got: %v", want, sequential)
+		}
+		if !slices.Equal(parallel, sequential) {
+			t.Errorf("got a different stop order than the sequential one. expected: %v; got: %v", sequential, parallel)
+		}
+	})
+	t.Run("slow components are logged and not waited for", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			var b bytes.Buffer
+			release := make(chan struct{})
+			defer close(release)
+			a := New(WithParallelStop(time.Second), WithStopTimeout(0))
+			a.signalsDisabled = true
+			a.logger = slog.New(slog.NewTextHandler(&b, nil))
+			a.RegisterParallel(
+				ComponentFunc("slow", nil, func() error { <-release; return nil }),
+				ComponentFunc("failing", nil, func() error { return errors.New("stop failed") }),
+			)
+			go a.Stop()
+			synctest.Wait()
+			begin := time.Now()
+			a.Start()
+			if got, want := time.Since(begin), time.Second; got != want {
+				t.Errorf("got a different shutdown duration than the wanted one. expected: %s; got: %s", want, got)
+			}
+			want := "component \"slow\" failed to stop: did not stop within 1s: context deadline exceeded\n" +
+				"component \"failing\" failed to stop: stop failed"
+			if err := a.Err(); err == nil || err.Error() != want {
+				t.Errorf("got a different value than the wanted one.\nexpected:\n%s\ngot:\n%v", want, err)
+			}
+			logs := b.String()
+			for _, want := range []string{
+				`msg="component did not stop in time" component=slow elapsed=1s`,
+				`msg="stop error encountered during closing component"`,
+				`component=failing`,
+			} {
+				if !strings.Contains(logs, want) {
+					t.Errorf("expected the logs to contain %q but got:\n%s", want, logs)
+				}
+			}
+		})
+	})
+}
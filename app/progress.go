@@ -0,0 +1,112 @@
+package app
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"runtime/debug"
+	"slices"
+	"sync"
+	"time"
+)
+
+// stopProgressInterval is how often the components that are still stopping are logged during the cleanup.
+const stopProgressInterval = time.Second
+
+// stopProgress tracks the components being stopped, to tell which ones are blocking the shutdown.
+type stopProgress struct {
+	m     sync.Mutex
+	since map[string]time.Time
+}
+
+func (p *stopProgress) begin(name string) {
+	p.m.Lock()
+	defer p.m.Unlock()
+	if p.since == nil {
+		p.since = map[string]time.Time{}
+	}
+	p.since[name] = time.Now()
+}
+
+func (p *stopProgress) end(name string) {
+	p.m.Lock()
+	defer p.m.Unlock()
+	delete(p.since, name)
+}
+
+// pendingStop is a component that did not finish stopping yet.
+type pendingStop struct {
+	component string
+	elapsed   time.Duration
+}
+
+func (p pendingStop) String() string {
+	return fmt.Sprintf("%s (%s)", p.component, p.elapsed.Round(time.Millisecond))
+}
+
+// pending returns the components that did not finish stopping yet, the ones stopping for the longest time first.
+func (p *stopProgress) pending() []pendingStop {
+	p.m.Lock()
+	defer p.m.Unlock()
+	res := make([]pendingStop, 0, len(p.since))
+	for name, since := range p.since {
+		res = append(res, pendingStop{component: name, elapsed: time.Since(since)})
+	}
+	slices.SortFunc(res, func(x, y pendingStop) int {
+		return cmp.Or(cmp.Compare(y.elapsed, x.elapsed), cmp.Compare(x.component, y.component))
+	})
+	return res
+}
+
+// blocking returns the components blocking the shutdown, formatted to be logged (ie: "db (2.5s)").
+func (p *stopProgress) blocking() []string {
+	pending := p.pending()
+	res := make([]string, len(pending))
+	for i, s := range pending {
+		res[i] = s.String()
+	}
+	return res
+}
+
+// stopTracked stops c, tracking it as pending until its stop returns.
+// A panic of the stop is logged and returned as an error, allowing the cleanup to continue with the other components.
+func (a *App) stopTracked(ctx context.Context, c Component) (err error) {
+	a.stopProgress.begin(c.String())
+	defer a.stopProgress.end(c.String())
+	defer func() {
+		if r := recover(); r != nil {
+			a.componentLog(c.String()).
+				With("panic", r).
+				With("stack", string(debug.Stack())).
+				Error("component panicked while stopping")
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return stopComponent(ctx, c)
+}
+
+// logStopProgress logs periodically the components that are still stopping, until the returned function is called.
+func (a *App) logStopProgress() func() {
+	doneCh := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		ticker := time.NewTicker(stopProgressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-doneCh:
+				return
+			case <-ticker.C:
+				for _, s := range a.stopProgress.pending() {
+					a.componentLog(s.component).
+						With("elapsed", s.elapsed).
+						Debug("waiting for component to stop")
+				}
+			}
+		}
+	})
+	return func() {
+		close(doneCh)
+		wg.Wait()
+	}
+}
@@ -0,0 +1,76 @@
+package app
+
+import (
+	"log/slog"
+	"slices"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/yottta/go-core/logging/logtest"
+)
+
+func TestStopProgress(t *testing.T) {
+	blocking := func(name string, releaseCh <-chan struct{}) *mockComp {
+		return &mockComp{
+			name:   name,
+			startF: func() error { return nil },
+			stopF: func() error {
+				<-releaseCh
+				return nil
+			},
+		}
+	}
+	t.Run("the forceful stop names the component blocking the shutdown", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			h := logtest.NewHandler()
+			a := New(WithLogger(slog.New(h)), WithStopTimeout(2500*time.Millisecond))
+			a.signalsDisabled = true
+			releaseCh := make(chan struct{})
+			a.Register(&mockComp{name: "db", startF: func() error { return nil }, stopF: func() error { return nil }})
+			a.Register(blocking("cache", releaseCh))
+			go a.Start()
+			synctest.Wait()
+			a.Stop()
+
+			attrs := h.AttrsFor("app stopped forcefully after timeout")
+			if got, want := attrs["blocking"].Any(), []string{"cache (2.5s)"}; !slices.Equal(got.([]string), want) {
+				t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+			}
+			var waiting []string
+			for _, r := range h.Records() {
+				if r.Message == "waiting for component to stop" {
+					waiting = append(waiting, r.Attrs["component"].String()+" "+r.Attrs["elapsed"].String())
+				}
+			}
+			if want := []string{"cache 1s", "cache 2s"}; !slices.Equal(waiting, want) {
+				t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, waiting)
+			}
+			close(releaseCh)
+			<-a.closingCh
+		})
+	})
+	t.Run("the forceful stop lists all the components not stopped in parallel", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			h := logtest.NewHandler()
+			a := New(WithLogger(slog.New(h)), WithStopTimeout(2*time.Second), WithParallelStop(time.Minute))
+			a.signalsDisabled = true
+			releaseCh := make(chan struct{})
+			a.RegisterParallel(
+				blocking("queue", releaseCh),
+				&mockComp{name: "db", startF: func() error { return nil }, stopF: func() error { return nil }},
+				blocking("cache", releaseCh),
+			)
+			go a.Start()
+			synctest.Wait()
+			a.Stop()
+
+			attrs := h.AttrsFor("app stopped forcefully after timeout")
+			if got, want := attrs["blocking"].Any(), []string{"cache (2s)", "queue (2s)"}; !slices.Equal(got.([]string), want) {
+				t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+			}
+			close(releaseCh)
+			<-a.closingCh
+		})
+	})
+}
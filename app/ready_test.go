@@ -0,0 +1,36 @@
+package app
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestWhenReady(t *testing.T) {
+	var events []string
+	a := New()
+	a.WhenReady(func() { events = append(events, "ready1") })
+	for _, name := range []string{"comp1", "comp2"} {
+		a.Register(&mockComp{
+			name: name,
+			startF: func() error {
+				events = append(events, name)
+				return nil
+			},
+			stopF: func() error { return nil },
+		})
+	}
+	a.WhenReady(func() { events = append(events, "ready2") })
+	a.WhenReady(nil)
+
+	go func() {
+		<-time.After(time.Second)
+		a.Stop()
+	}()
+	a.Start()
+
+	want := []string{"comp1", "comp2", "ready1", "ready2"}
+	if !slices.Equal(events, want) {
+		t.Errorf("wrong order of events.\nexpected: %v\ngot: %v", want, events)
+	}
+}
@@ -0,0 +1,64 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+)
+
+// Component returns the registered component with the given name, as returned by its [Component.String].
+func (a *App) Component(name string) (Component, bool) {
+	a.componentsM.Lock()
+	defer a.componentsM.Unlock()
+	for _, c := range a.components {
+		if c.String() == name {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// Components returns the names of the registered components, in the order in which they were registered.
+func (a *App) Components() []string {
+	a.componentsM.Lock()
+	defer a.componentsM.Unlock()
+	res := make([]string, len(a.components))
+	for i, c := range a.components {
+		res[i] = c.String()
+	}
+	return res
+}
+
+// reserve records the names of the components about to be started, returning an error when any of them is already
+// used by another component.
+func (a *App) reserve(names ...string) error {
+	a.componentsM.Lock()
+	defer a.componentsM.Unlock()
+	for i, name := range names {
+		if _, ok := a.names[name]; ok || slices.Contains(names[:i], name) {
+			return fmt.Errorf("component %q is already registered", name)
+		}
+	}
+	if a.names == nil {
+		a.names = map[string]struct{}{}
+	}
+	for _, name := range names {
+		a.names[name] = struct{}{}
+	}
+	return nil
+}
+
+// componentLog returns the logger carrying the name of the component. It's created on each call, so it follows the
+// default logger replaced by a reload of the logging configuration.
+func (a *App) componentLog(name string) *slog.Logger {
+	return a.log().With("component", name)
+}
+
+// release frees the names of the components that failed to start.
+func (a *App) release(names ...string) {
+	a.componentsM.Lock()
+	defer a.componentsM.Unlock()
+	for _, name := range names {
+		delete(a.names, name)
+	}
+}
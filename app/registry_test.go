@@ -0,0 +1,57 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Run("lookup of the registered components", func(t *testing.T) {
+		a := New()
+		a.RegisterFunc("db", nil, nil)
+		a.RegisterParallel(ComponentFunc("cache", nil, nil), ComponentFunc("queue", nil, nil))
+
+		if got, want := a.Components(), []string{"db", "cache", "queue"}; !slices.Equal(got, want) {
+			t.Errorf("got a different value than the wanted one. expected: %v; got: %v", want, got)
+		}
+		c, ok := a.Component("cache")
+		if !ok {
+			t.Fatalf("expected to find the component")
+		}
+		if got, want := c.String(), "cache"; got != want {
+			t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+		}
+		if _, ok := a.Component("missing"); ok {
+			t.Errorf("expected not to find a component that was not registered")
+		}
+	})
+	t.Run("duplicate name panics", func(t *testing.T) {
+		a := New()
+		a.RegisterFunc("metrics", nil, nil)
+		defer expectPanic(t, `component "metrics" is already registered`)
+		a.RegisterFunc("metrics", func() error {
+			t.Errorf("expected the duplicate not to be started")
+			return nil
+		}, nil)
+	})
+	t.Run("duplicate name in the same parallel registration panics", func(t *testing.T) {
+		a := New()
+		defer expectPanic(t, `component "metrics" is already registered`)
+		a.RegisterParallel(ComponentFunc("metrics", nil, nil), ComponentFunc("metrics", nil, nil))
+	})
+	t.Run("concurrent registration", func(t *testing.T) {
+		a := New()
+		var wg sync.WaitGroup
+		for i := range 20 {
+			wg.Go(func() {
+				a.RegisterFunc(fmt.Sprintf("comp-%d", i), nil, nil)
+			})
+		}
+		wg.Wait()
+		if got, want := len(a.Components()), 20; got != want {
+			t.Errorf("got a different value than the wanted one. expected: %d; got: %d", want, got)
+		}
+	})
+}
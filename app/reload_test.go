@@ -0,0 +1,152 @@
+package app
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/yottta/go-core/logging"
+	"github.com/yottta/go-core/logging/logtest"
+)
+
+const (
+	envKeyForReload = "app_reload_subprocess"
+
+	reloadReadyMarker  = "ready for reload"
+	logBeforeReload    = "debug log before reload"
+	logAfterReload     = "debug log after reload"
+	reloadTimeoutExit  = 3
+	reloadWaitDuration = 5 * time.Second
+)
+
+func TestMain(m *testing.M) {
+	if _, ok := os.LookupEnv(envKeyForReload); ok {
+		os.Exit(runReloadSubprocess())
+	}
+	if _, ok := os.LookupEnv(envKeyForHardDeadline); ok {
+		os.Exit(runHardDeadlineSubprocess())
+	}
+	if _, ok := os.LookupEnv(envKeyForSignals); ok {
+		os.Exit(runSignalsSubprocess())
+	}
+	os.Exit(m.Run())
+}
+
+// runReloadSubprocess starts an app with LOG_LEVEL=error, changes the LOG_LEVEL to debug and waits for
+// the parent process to send the syscall.SIGHUP to reload the logging configuration.
+func runReloadSubprocess() int {
+	logging.Setup()
+	a := New()
+	go a.Start()
+	defer a.Stop()
+	<-time.After(200 * time.Millisecond) // allow Start to register the signals
+
+	slog.Debug(logBeforeReload)
+	if err := os.Setenv("LOG_LEVEL", "debug"); err != nil {
+		return 2
+	}
+	fmt.Println(reloadReadyMarker)
+
+	deadline := time.After(reloadWaitDuration)
+	for !slog.Default().Enabled(context.Background(), slog.LevelDebug) {
+		select {
+		case <-deadline:
+			return reloadTimeoutExit
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	slog.Debug(logAfterReload)
+	return 0
+}
+
+func TestReloadOnSIGHUP(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("signals cannot be sent to a process on windows")
+	}
+	var stderr bytes.Buffer
+	cmd := exec.Command(os.Args[0])
+	cmd.Env = []string{fmt.Sprintf("%s=1", envKeyForReload), "LOG_LEVEL=error"}
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatalf("failed to get stdout of the subprocess: %s", err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("failed to start the subprocess: %s", err)
+	}
+
+	readyCh := make(chan struct{})
+	go func() {
+		s := bufio.NewScanner(stdout)
+		for s.Scan() {
+			if s.Text() == reloadReadyMarker {
+				close(readyCh)
+				break
+			}
+		}
+		_, _ = io.Copy(io.Discard, stdout)
+	}()
+	select {
+	case <-readyCh:
+	case <-time.After(reloadWaitDuration):
+		_ = cmd.Process.Kill()
+		t.Fatalf("subprocess did not become ready in time")
+	}
+
+	if err := cmd.Process.Signal(syscall.SIGHUP); err != nil {
+		t.Fatalf("failed to send SIGHUP to the subprocess: %s", err)
+	}
+	if err := cmd.Wait(); err != nil {
+		t.Fatalf("subprocess failed: %s\nstderr:\n%s", err, stderr.String())
+	}
+
+	logs := stderr.String()
+	if strings.Contains(logs, logBeforeReload) {
+		t.Errorf("expected the logs before the reload to not contain debug logs but they did:\n%s", logs)
+	}
+	if !strings.Contains(logs, logAfterReload) {
+		t.Errorf("expected the new log level to be applied after SIGHUP but it wasn't:\n%s", logs)
+	}
+}
+
+func TestReloadKeepsCustomDefaultLogger(t *testing.T) {
+	prev := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(prev) })
+	logging.SetupWithWriter(io.Discard)
+	global := logtest.Install(t)
+	custom := slog.Default()
+	a := New()
+	a.reload()
+	if slog.Default() != custom {
+		t.Errorf("expected the default logger installed after the logging setup to be kept on reload")
+	}
+	if !global.ContainsMessage("app reloaded") {
+		t.Errorf("expected the reload to be logged through the kept logger")
+	}
+}
+
+func TestReloadUpdatesComponentLoggers(t *testing.T) {
+	prev := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(prev) })
+	logging.SetupWithWriter(io.Discard)
+	a := New()
+	a.signalsDisabled = true
+	a.Register(&mockComp{name: "db", startF: func() error { return nil }, stopF: func() error { return nil }})
+	// the default logger replaced after the registration, as a reload of the logging configuration does
+	global := logtest.Install(t)
+	a.cleanup()
+	attrs := global.AttrsFor("component stopped")
+	if got, want := attrs["component"].String(), "db"; got != want {
+		t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+	}
+}
@@ -0,0 +1,48 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/yottta/go-core/shutdown"
+)
+
+// remember records a successful registration, to be replayed when the app is started again.
+func (a *App) remember(registration func() error) {
+	a.componentsM.Lock()
+	defer a.componentsM.Unlock()
+	a.registrations = append(a.registrations, registration)
+}
+
+// restart prepares the app for a new run once the previous one finished, starting again the components registered
+// during the previous run, in the order in which they were registered.
+func (a *App) restart() {
+	if !a.finished.CompareAndSwap(true, false) {
+		return
+	}
+	// the stop that ended the previous run might not have returned yet
+	if a.stopping.Load() {
+		<-a.stoppedCh
+	}
+	a.log().Info("app restarting")
+	a.ctx, a.cancel = context.WithCancelCause(context.Background())
+	a.closingCh = make(chan struct{}, 1)
+	a.stoppedCh = make(chan struct{})
+	a.stopping.Store(false)
+	a.runErr.Store(nil)
+	a.cleanupErr.Store(nil)
+	a.signals = shutdown.Observe()
+	a.createdAt = time.Now()
+
+	a.componentsM.Lock()
+	registrations := a.registrations
+	a.registrations = nil
+	a.timings = nil
+	a.lastPhase = 0
+	a.componentsM.Unlock()
+	for _, register := range registrations {
+		if err := register(); err != nil {
+			panic(err)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package app
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"testing/synctest"
+)
+
+func TestRestart(t *testing.T) {
+	t.Run("components are started again", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			var starts, stops, hooks atomic.Int32
+			a := New()
+			a.signalsDisabled = true
+			a.Register(&mockComp{
+				name:   "db",
+				startF: func() error { starts.Add(1); return nil },
+				stopF:  func() error { stops.Add(1); return nil },
+			})
+			a.OnStart(func(context.Context) { hooks.Add(1) })
+			for run := 1; run <= 2; run++ {
+				go func() {
+					synctest.Wait()
+					a.Stop()
+				}()
+				a.Start()
+				if got, want := starts.Load(), int32(run); got != want {
+					t.Errorf("got a different value than the wanted one. expected: %d; got: %d", want, got)
+				}
+				if got, want := stops.Load(), int32(run); got != want {
+					t.Errorf("got a different value than the wanted one. expected: %d; got: %d", want, got)
+				}
+				if got, want := hooks.Load(), int32(run); got != want {
+					t.Errorf("got a different value than the wanted one. expected: %d; got: %d", want, got)
+				}
+			}
+			if got, want := a.Components(), 0; len(got) != want {
+				t.Errorf("expected no component registered after the stop but got %v", got)
+			}
+		})
+	})
+	t.Run("the context of a new run is not done", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			a := New()
+			a.signalsDisabled = true
+			a.Stop()
+			a.Start()
+			doneCh := make(chan struct{})
+			go func() {
+				defer close(doneCh)
+				a.Start()
+			}()
+			synctest.Wait()
+			if err := a.Context().Err(); err != nil {
+				t.Errorf("expected the context of the restarted app to not be done but got: %s", err)
+			}
+			a.Stop()
+			<-doneCh
+		})
+	})
+	t.Run("run can be called again", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			a := New()
+			a.signalsDisabled = true
+			a.Register(&mockComp{name: "db", startF: func() error { return nil }, stopF: func() error { return nil }})
+			for range 2 {
+				go func() {
+					synctest.Wait()
+					a.Stop()
+				}()
+				if got, want := a.Run(), 0; got != want {
+					t.Errorf("got a different value than the wanted one. expected: %d; got: %d", want, got)
+				}
+			}
+		})
+	})
+	t.Run("start while running panics", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			a := New()
+			a.signalsDisabled = true
+			doneCh := make(chan struct{})
+			go func() {
+				defer close(doneCh)
+				a.Start()
+			}()
+			synctest.Wait()
+			func() {
+				defer expectPanic(t, "app: the app is already running")
+				a.Start()
+			}()
+			a.Stop()
+			<-doneCh
+		})
+	})
+}
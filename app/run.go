@@ -0,0 +1,75 @@
+package app
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// FailureExitCode is returned by [App.Run] when the app stopped because of a failure.
+	FailureExitCode = 1
+	// StopTimeoutExitCode is returned by [App.Run] when the cleanup did not finish in the stop timeout.
+	StopTimeoutExitCode = 2
+)
+
+// Run is the same as [App.Start] but returns the exit code of the process, allowing the main function to end with
+// os.Exit(a.Run()). The exit code is:
+//   - 0 when the app was stopped cleanly, by a signal or by [App.Stop];
+//   - [FailureExitCode] when a [Runner] failed, as reported by [App.Err], or when [App.Start] panicked;
+//   - [StopTimeoutExitCode] when the cleanup did not finish in the stop timeout configured by [WithStopTimeout].
+//     In this case, Run returns while the cleanup is still in progress.
+//
+// The exit code is logged together with the cause of the shutdown.
+func (a *App) Run() int {
+	// restarted before the goroutine of Start, for the app context below to be the one of the new run
+	a.restart()
+	doneCh := make(chan any, 1)
+	go func() {
+		defer func() {
+			doneCh <- recover()
+		}()
+		a.Start()
+	}()
+
+	select {
+	case r := <-doneCh:
+		return a.exitCode(r)
+	case <-a.Done():
+	}
+	var timeoutCh <-chan time.Time
+	if a.forcefullyTimeout > 0 {
+		timeoutCh = time.After(a.forcefullyTimeout)
+	}
+	select {
+	case r := <-doneCh:
+		return a.exitCode(r)
+	case <-timeoutCh:
+		a.log().
+			With("cause", context.Cause(a.ctx)).
+			With("timeout", a.forcefullyTimeout).
+			With("blocking", a.stopProgress.blocking()).
+			With("exit_code", StopTimeoutExitCode).
+			Error("app cleanup did not finish in time, exiting")
+		return StopTimeoutExitCode
+	}
+}
+
+// exitCode returns the exit code of the app once [App.Start] returned or panicked with r.
+func (a *App) exitCode(r any) int {
+	l := a.log().With("cause", context.Cause(a.ctx))
+	code := 0
+	switch {
+	case r != nil:
+		code = FailureExitCode
+		l = l.With("panic", r)
+	case a.Err() != nil:
+		code = FailureExitCode
+	}
+	l = l.With("exit_code", code)
+	if code != 0 {
+		l.Error("app exited with failure")
+		return code
+	}
+	l.Info("app exited")
+	return code
+}
@@ -0,0 +1,95 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+// syncBuffer is a [bytes.Buffer] safe for the concurrent writes of the app and of the callers of [App.Stop].
+type syncBuffer struct {
+	m sync.Mutex
+	b bytes.Buffer
+}
+
+func (s *syncBuffer) Write(p []byte) (int, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.b.Write(p)
+}
+
+func (s *syncBuffer) String() string {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.b.String()
+}
+
+func TestRun(t *testing.T) {
+	cases := []struct {
+		name     string
+		setup    func(a *App)
+		wantCode int
+		wantLog  string
+	}{
+		{
+			name: "clean stop",
+			setup: func(a *App) {
+				a.OnStart(func(ctx context.Context) { go a.Stop() })
+			},
+			wantCode: 0,
+			wantLog:  `msg="app exited" cause="app stopped" exit_code=0`,
+		},
+		{
+			name: "failed runner",
+			setup: func(a *App) {
+				a.Register(&runnerComp{runF: func(ctx context.Context) error {
+					<-time.After(time.Second)
+					return errors.New("consumer disconnected")
+				}})
+			},
+			wantCode: FailureExitCode,
+			wantLog:  `msg="app exited with failure" cause="component runnerComp failed: consumer disconnected" exit_code=1`,
+		},
+		{
+			name: "panic during start",
+			setup: func(a *App) {
+				a.WhenReady(func() { panic("not ready") })
+			},
+			wantCode: FailureExitCode,
+			wantLog:  `msg="app exited with failure" cause="context canceled" panic="not ready" exit_code=1`,
+		},
+		{
+			name: "cleanup exceeding the stop timeout",
+			setup: func(a *App) {
+				a.RegisterFunc("slow", nil, func() error { <-time.After(time.Minute); return nil })
+				a.OnStart(func(ctx context.Context) { go a.Stop() })
+			},
+			wantCode: StopTimeoutExitCode,
+			wantLog:  `msg="app cleanup did not finish in time, exiting" cause="app stopped" timeout=3s blocking="[slow (3s)]" exit_code=2`,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			synctest.Test(t, func(t *testing.T) {
+				var b syncBuffer
+				a := New()
+				a.logger = slog.New(slog.NewTextHandler(&b, nil))
+				a.signalsDisabled = true
+				tt.setup(a)
+				if got := a.Run(); got != tt.wantCode {
+					t.Errorf("got a different value than the wanted one. expected: %d; got: %d", tt.wantCode, got)
+				}
+				if !strings.Contains(b.String(), tt.wantLog) {
+					t.Errorf("expected logs to contain %q but got:\n%s", tt.wantLog, b.String())
+				}
+				<-a.closingCh // let the cleanup finish
+			})
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/yottta/go-core/logging"
+)
+
+// Runner can be implemented by the components that run for the whole lifetime of the app (ie: a server or a
+// consumer). Once the component is started, its Run is called in its own goroutine with the context returned
+// by [App.Context], and it is expected to return once the context is done.
+//
+// When Run returns an error other than [context.Canceled], the app is stopped the same way as on [App.Stop],
+// with the error as the [context.Cause] of the app context. The error is also returned by [App.Err].
+// The cleanup of the app waits for the Run calls to return, at most for the configured stop timeout.
+type Runner interface {
+	Run(ctx context.Context) error
+}
+
+// run calls [Runner.Run] in its own goroutine, stopping the app when it fails.
+func (a *App) run(c fmt.Stringer, r Runner) {
+	// a runner outliving the cleanup must not stop the next run of the app
+	ctx, cancel := a.ctx, a.cancel
+	a.runners.Go(func() {
+		err := r.Run(ctx)
+		if err == nil || errors.Is(err, context.Canceled) {
+			return
+		}
+		err = fmt.Errorf("component %s failed: %w", c.String(), err)
+		if !a.runErr.CompareAndSwap(nil, &err) {
+			return
+		}
+		a.componentLog(c.String()).
+			With(logging.Err(err)).
+			Error("component failed, stopping the app")
+		cancel(err)
+	})
+}
+
+// waitRunners waits for the [Runner.Run] calls to return, but not longer than ctx.
+func (a *App) waitRunners(ctx context.Context) {
+	doneCh := make(chan struct{})
+	go func() {
+		defer close(doneCh)
+		a.runners.Wait()
+	}()
+	select {
+	case <-doneCh:
+	case <-ctx.Done():
+		a.log().Warn("not all the running components returned in time")
+	}
+}
@@ -0,0 +1,108 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+// runnerComp is a component implementing [Runner], with no-op start and stop.
+type runnerComp struct {
+	runF func(ctx context.Context) error
+}
+
+func (m runnerComp) String() string {
+	return "runnerComp"
+}
+
+func (m runnerComp) Start() error {
+	return nil
+}
+
+func (m runnerComp) Stop() error {
+	return nil
+}
+
+func (m runnerComp) Run(ctx context.Context) error {
+	return m.runF(ctx)
+}
+
+func TestRunner(t *testing.T) {
+	t.Run("failure stops the app", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			var b bytes.Buffer
+			var stopped bool
+			a := New()
+			a.logger = slog.New(slog.NewTextHandler(&b, nil))
+			a.signalsDisabled = true
+			a.RegisterFunc("db", nil, func() error { stopped = true; return nil })
+			a.Register(&runnerComp{runF: func(ctx context.Context) error {
+				<-time.After(time.Second)
+				return errors.New("consumer disconnected")
+			}})
+			begin := time.Now()
+			a.Start()
+			if got, want := time.Since(begin), time.Second; got != want {
+				t.Errorf("got a different value than the wanted one. expected: %s; got: %s", want, got)
+			}
+			if !stopped {
+				t.Errorf("expected the other components to be stopped")
+			}
+			want := "component runnerComp failed: consumer disconnected"
+			if err := a.Err(); err == nil || err.Error() != want {
+				t.Fatalf("got a different value than the wanted one. expected: %q; got: %v", want, err)
+			}
+			if got := context.Cause(a.Context()); !errors.Is(a.Err(), got) {
+				t.Errorf("got a different value than the wanted one. expected: %v; got: %v", a.Err(), got)
+			}
+			if want := `msg="component failed, stopping the app"`; !strings.Contains(b.String(), want) {
+				t.Errorf("expected logs to contain %q but got:\n%s", want, b.String())
+			}
+		})
+	})
+	t.Run("run ends with the app", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			var returned bool
+			a := New()
+			a.signalsDisabled = true
+			a.Register(&runnerComp{runF: func(ctx context.Context) error {
+				<-ctx.Done()
+				<-time.After(time.Second) // the cleanup waits for it
+				returned = true
+				return ctx.Err()
+			}})
+			go a.Stop()
+			a.Start()
+			if !returned {
+				t.Errorf("expected the cleanup to wait for the run to return")
+			}
+			if err := a.Err(); err != nil {
+				t.Errorf("expected no error but got: %s", err)
+			}
+		})
+	})
+	t.Run("cleanup does not wait longer than the stop timeout", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			release := make(chan struct{})
+			defer close(release)
+			a := New(WithStopTimeout(2 * time.Second))
+			a.signalsDisabled = true
+			a.Register(&runnerComp{runF: func(ctx context.Context) error {
+				<-release // ignores the context
+				return nil
+			}})
+			go a.Stop()
+			synctest.Wait()
+			begin := time.Now()
+			a.Start()
+			if got, want := time.Since(begin), 2*time.Second; got != want {
+				t.Errorf("got a different value than the wanted one. expected: %s; got: %s", want, got)
+			}
+		})
+	})
+}
@@ -0,0 +1,95 @@
+package app
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+const (
+	envKeyForSignals = "app_signals_subprocess"
+
+	signalsReadyMarker = "ready for signals"
+)
+
+// runSignalsSubprocess starts an app stopped only by syscall.SIGTERM.
+func runSignalsSubprocess() int {
+	a := New(WithSignals(syscall.SIGTERM))
+	go func() {
+		<-time.After(200 * time.Millisecond) // allow Start to register the signals
+		fmt.Println(signalsReadyMarker)
+	}()
+	a.Start()
+	return 0
+}
+
+func TestWithSignals(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("signals cannot be sent to a process on windows")
+	}
+	var stderr bytes.Buffer
+	cmd := exec.Command(os.Args[0])
+	cmd.Env = []string{fmt.Sprintf("%s=1", envKeyForSignals)}
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatalf("failed to get stdout of the subprocess: %s", err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("failed to start the subprocess: %s", err)
+	}
+	readyCh := make(chan struct{})
+	go func() {
+		s := bufio.NewScanner(stdout)
+		for s.Scan() {
+			if s.Text() == signalsReadyMarker {
+				close(readyCh)
+				break
+			}
+		}
+		_, _ = io.Copy(io.Discard, stdout)
+	}()
+	doneCh := make(chan error, 1)
+	select {
+	case <-readyCh:
+	case <-time.After(5 * time.Second):
+		_ = cmd.Process.Kill()
+		t.Fatalf("subprocess did not become ready in time")
+	}
+	go func() {
+		doneCh <- cmd.Wait()
+	}()
+
+	if err := cmd.Process.Signal(syscall.SIGHUP); err != nil {
+		t.Fatalf("failed to send SIGHUP to the subprocess: %s", err)
+	}
+	select {
+	case err := <-doneCh:
+		t.Fatalf("expected the subprocess to keep running after SIGHUP but it exited with: %v\nstderr:\n%s", err, stderr.String())
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	if err := cmd.Process.Signal(syscall.SIGTERM); err != nil {
+		t.Fatalf("failed to send SIGTERM to the subprocess: %s", err)
+	}
+	select {
+	case err := <-doneCh:
+		if err != nil {
+			t.Fatalf("subprocess failed: %s\nstderr:\n%s", err, stderr.String())
+		}
+		if want := "app reloaded"; !strings.Contains(stderr.String(), want) {
+			t.Errorf("expected SIGHUP to reload the app, with the logs containing %q, but got:\n%s", want, stderr.String())
+		}
+	case <-time.After(5 * time.Second):
+		_ = cmd.Process.Kill()
+		t.Fatalf("expected the subprocess to stop on SIGTERM")
+	}
+}
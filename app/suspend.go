@@ -0,0 +1,52 @@
+package app
+
+import (
+	"fmt"
+)
+
+// Suspendable is an optional interface for the components that can be paused temporarily without being stopped
+// (ie: a worker during a maintenance window). Use [App.Suspend] and [App.Resume] to drive it.
+type Suspendable interface {
+	Suspend() error
+	Resume() error
+}
+
+// Suspend pauses the registered component with the given name, as returned by its [Component.String].
+// An error is returned when there is no such component or when it does not implement [Suspendable].
+func (a *App) Suspend(name string) error {
+	s, err := a.suspendable(name)
+	if err != nil {
+		return err
+	}
+	if err := s.Suspend(); err != nil {
+		return fmt.Errorf("failed to suspend component %q: %w", name, err)
+	}
+	a.componentLog(name).Info("component suspended")
+	return nil
+}
+
+// Resume resumes the registered component with the given name, previously paused with [App.Suspend].
+// An error is returned when there is no such component or when it does not implement [Suspendable].
+func (a *App) Resume(name string) error {
+	s, err := a.suspendable(name)
+	if err != nil {
+		return err
+	}
+	if err := s.Resume(); err != nil {
+		return fmt.Errorf("failed to resume component %q: %w", name, err)
+	}
+	a.componentLog(name).Info("component resumed")
+	return nil
+}
+
+func (a *App) suspendable(name string) (Suspendable, error) {
+	c, ok := a.Component(name)
+	if !ok {
+		return nil, fmt.Errorf("component %q is not registered", name)
+	}
+	s, ok := c.(Suspendable)
+	if !ok {
+		return nil, fmt.Errorf("component %q cannot be suspended", name)
+	}
+	return s, nil
+}
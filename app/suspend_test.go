@@ -0,0 +1,137 @@
+package app
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestSuspendResume(t *testing.T) {
+	t.Run("suspended worker stops processing until resumed", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			w := newWorker()
+			a := New()
+			a.Register(w)
+			defer a.cleanup()
+
+			w.jobs <- 1
+			synctest.Wait()
+			if got, want := w.processed.Load(), int32(1); got != want {
+				t.Fatalf("got a different number of processed jobs than the wanted one. expected: %d; got: %d", want, got)
+			}
+
+			if err := a.Suspend("worker"); err != nil {
+				t.Fatalf("expected no error but got: %s", err)
+			}
+			go func() { w.jobs <- 2 }()
+			<-time.After(time.Second)
+			synctest.Wait()
+			if got, want := w.processed.Load(), int32(1); got != want {
+				t.Fatalf("expected no job processed while suspended. expected: %d; got: %d", want, got)
+			}
+
+			if err := a.Resume("worker"); err != nil {
+				t.Fatalf("expected no error but got: %s", err)
+			}
+			synctest.Wait()
+			if got, want := w.processed.Load(), int32(2); got != want {
+				t.Fatalf("expected the job to be processed after resume. expected: %d; got: %d", want, got)
+			}
+		})
+	})
+	t.Run("component that cannot be suspended", func(t *testing.T) {
+		a := New()
+		a.Register(&mockComp{startF: func() error { return nil }, stopF: func() error { return nil }})
+		err := a.Suspend("mockComp")
+		if want := `component "mockComp" cannot be suspended`; err == nil || err.Error() != want {
+			t.Errorf("got a different error than the wanted one. expected: %q; got: %v", want, err)
+		}
+		err = a.Resume("mockComp")
+		if want := `component "mockComp" cannot be suspended`; err == nil || err.Error() != want {
+			t.Errorf("got a different error than the wanted one. expected: %q; got: %v", want, err)
+		}
+	})
+	t.Run("component not registered", func(t *testing.T) {
+		a := New()
+		err := a.Suspend("worker")
+		if want := `component "worker" is not registered`; err == nil || err.Error() != want {
+			t.Errorf("got a different error than the wanted one. expected: %q; got: %v", want, err)
+		}
+	})
+}
+
+// worker processes jobs until stopped, holding them while it's suspended.
+type worker struct {
+	jobs      chan int
+	processed atomic.Int32
+
+	m       sync.Mutex
+	resume  chan struct{}
+	stopCh  chan struct{}
+	stopped chan struct{}
+}
+
+func newWorker() *worker {
+	return &worker{
+		jobs:    make(chan int),
+		stopCh:  make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+}
+
+func (w *worker) String() string {
+	return "worker"
+}
+
+func (w *worker) Start() error {
+	go func() {
+		defer close(w.stopped)
+		for {
+			select {
+			case <-w.jobs:
+			case <-w.stopCh:
+				return
+			}
+			// a job received while suspended waits for the resume
+			w.m.Lock()
+			resume := w.resume
+			w.m.Unlock()
+			if resume != nil {
+				select {
+				case <-resume:
+				case <-w.stopCh:
+					return
+				}
+			}
+			w.processed.Add(1)
+		}
+	}()
+	return nil
+}
+
+func (w *worker) Stop() error {
+	close(w.stopCh)
+	<-w.stopped
+	return nil
+}
+
+func (w *worker) Suspend() error {
+	w.m.Lock()
+	defer w.m.Unlock()
+	if w.resume == nil {
+		w.resume = make(chan struct{})
+	}
+	return nil
+}
+
+func (w *worker) Resume() error {
+	w.m.Lock()
+	defer w.m.Unlock()
+	if w.resume != nil {
+		close(w.resume)
+		w.resume = nil
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package app
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// slowestComponents is the number of components listed in the startup summary logged by [App.Start].
+const slowestComponents = 3
+
+// ComponentTiming holds how long a component took to start and to stop.
+type ComponentTiming struct {
+	Component string
+	Start     time.Duration
+	// Stop is 0 until the component is stopped. When the stop is not waited for, as with [WithParallelStop],
+	// this is the time waited for it.
+	Stop time.Duration
+}
+
+// StartupReport returns how long each of the registered components took to start and, once the app stopped, to
+// stop, in the order in which they were registered.
+func (a *App) StartupReport() []ComponentTiming {
+	a.componentsM.Lock()
+	defer a.componentsM.Unlock()
+	return slices.Clone(a.timings)
+}
+
+func (a *App) recordStart(name string, d time.Duration) {
+	a.componentsM.Lock()
+	defer a.componentsM.Unlock()
+	a.timings = append(a.timings, ComponentTiming{Component: name, Start: d})
+}
+
+func (a *App) recordStop(name string, d time.Duration) {
+	a.componentsM.Lock()
+	defer a.componentsM.Unlock()
+	for i := range a.timings {
+		if a.timings[i].Component == name {
+			a.timings[i].Stop = d
+		}
+	}
+}
+
+// logStartup logs the time passed since the app was created, together with the slowest components to start.
+func (a *App) logStartup() {
+	timings := a.StartupReport()
+	slices.SortStableFunc(timings, func(x, y ComponentTiming) int {
+		return cmp.Compare(y.Start, x.Start)
+	})
+	slowest := make([]string, 0, slowestComponents)
+	for _, t := range timings[:min(len(timings), slowestComponents)] {
+		slowest = append(slowest, fmt.Sprintf("%s (%s)", t.Component, t.Start))
+	}
+	a.log().
+		With("duration", time.Since(a.createdAt)).
+		With("slowest", slowest).
+		Info("app startup finished")
+}
@@ -0,0 +1,55 @@
+package app
+
+import (
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestStartupReport(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var b syncBuffer
+		a := New()
+		a.logger = slog.New(slog.NewTextHandler(&b, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		a.signalsDisabled = true
+		sleep := func(d time.Duration) func() error {
+			return func() error { <-time.After(d); return nil }
+		}
+		a.RegisterFunc("db", sleep(2*time.Second), sleep(time.Second))
+		a.RegisterFunc("cache", sleep(time.Second), nil)
+		a.RegisterParallel(
+			ComponentFunc("http", sleep(3*time.Second), sleep(2*time.Second)),
+			ComponentFunc("metrics", nil, nil),
+		)
+
+		want := []ComponentTiming{
+			{Component: "db", Start: 2 * time.Second},
+			{Component: "cache", Start: time.Second},
+			{Component: "http", Start: 3 * time.Second},
+			{Component: "metrics"},
+		}
+		if got := a.StartupReport(); !slices.Equal(got, want) {
+			t.Errorf("got a different value than the wanted one.\nexpected: %v\ngot: %v", want, got)
+		}
+
+		go a.Stop()
+		a.Start()
+		want[0].Stop = time.Second
+		want[2].Stop = 2 * time.Second
+		if got := a.StartupReport(); !slices.Equal(got, want) {
+			t.Errorf("got a different value than the wanted one.\nexpected: %v\ngot: %v", want, got)
+		}
+		for _, line := range []string{
+			`msg="component registered successfully" component=db duration=2s`,
+			`msg="app startup finished" duration=6s slowest="[http (3s) db (2s) cache (1s)]"`,
+			`msg="component stopped" component=http duration=2s`,
+		} {
+			if !strings.Contains(b.String(), line) {
+				t.Errorf("expected logs to contain %q but got:\n%s", line, b.String())
+			}
+		}
+	})
+}
@@ -0,0 +1,41 @@
+package chix
+
+import (
+	"net"
+	"net/http"
+	"sync"
+)
+
+// connTracker keeps the state of each connection of the server, as reported by [http.Server.ConnState].
+type connTracker struct {
+	m      sync.Mutex
+	states map[net.Conn]http.ConnState
+}
+
+func newConnTracker() *connTracker {
+	return &connTracker{states: map[net.Conn]http.ConnState{}}
+}
+
+func (t *connTracker) track(c net.Conn, state http.ConnState) {
+	t.m.Lock()
+	defer t.m.Unlock()
+	switch state {
+	case http.StateClosed, http.StateHijacked:
+		delete(t.states, c)
+	default:
+		t.states[c] = state
+	}
+}
+
+// active returns the number of connections that are currently serving a request.
+func (t *connTracker) active() int {
+	t.m.Lock()
+	defer t.m.Unlock()
+	var n int
+	for _, s := range t.states {
+		if s == http.StateActive {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,93 @@
+package chix
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestDrainHooks(t *testing.T) {
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("failed to find a free port: %s", err)
+	}
+	port := l.Addr().(*net.TCPAddr).Port
+	_ = l.Close()
+
+	drainStartCh := make(chan int, 1)
+	drainCompleteCh := make(chan DrainStats, 1)
+	cfg := &Config{
+		Host: "localhost",
+		Port: port,
+	}
+	srv := cfg.NewServer(
+		WithOnDrainStart(func(inFlight int) { drainStartCh <- inFlight }),
+		WithOnDrainComplete(func(s DrainStats) { drainCompleteCh <- s }),
+	)
+	handlingCh := make(chan struct{})
+	srv.Router().Get("/slow", func(w http.ResponseWriter, r *http.Request) {
+		close(handlingCh)
+		<-time.After(300 * time.Millisecond)
+		_, _ = w.Write([]byte("done"))
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.Start(ctx)
+	}()
+	<-time.After(100 * time.Millisecond)
+
+	respCh := make(chan string, 1)
+	go func() {
+		resp, err := http.Get(fmt.Sprintf("http://localhost:%d/slow", port))
+		if err != nil {
+			respCh <- err.Error()
+			return
+		}
+		defer func() { _ = resp.Body.Close() }()
+		body, _ := io.ReadAll(resp.Body)
+		respCh <- string(body)
+	}()
+	<-handlingCh
+	cancel()
+
+	select {
+	case got := <-drainStartCh:
+		if got != 1 {
+			t.Errorf("expected 1 in-flight request at drain start but got %d", got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("drain start hook was not called")
+	}
+	select {
+	case got := <-drainCompleteCh:
+		if got.InFlight != 1 {
+			t.Errorf("expected 1 in-flight request in the stats but got %d", got.InFlight)
+		}
+		if got.TimedOut {
+			t.Errorf("expected the draining to finish before the timeout")
+		}
+		if got.Duration < 100*time.Millisecond {
+			t.Errorf("expected the draining to wait for the slow request but it took %s", got.Duration)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("drain complete hook was not called")
+	}
+	if got := <-respCh; got != "done" {
+		t.Errorf("expected the in-flight request to finish successfully but got %q", got)
+	}
+	select {
+	case err := <-errCh:
+		if err != nil {
+			t.Errorf("expected no error on graceful shutdown, got: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("server did not shut down in time")
+	}
+}
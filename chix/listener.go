@@ -0,0 +1,77 @@
+package chix
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	acceptRetryMinDelay = 5 * time.Millisecond
+	acceptRetryMaxDelay = time.Second
+)
+
+// acceptErrorListener wraps a [net.Listener] and gives the errors returned by [net.Listener.Accept]
+// to a handler that decides if the listener should continue accepting connections or not.
+type acceptErrorListener struct {
+	net.Listener
+
+	handler   func(error) bool
+	closeCh   chan struct{}
+	closeOnce sync.Once
+}
+
+func newAcceptErrorListener(l net.Listener, handler func(error) bool) net.Listener {
+	if handler == nil {
+		return l
+	}
+	return &acceptErrorListener{
+		Listener: l,
+		handler:  handler,
+		closeCh:  make(chan struct{}),
+	}
+}
+
+// Accept retries accepting connections as long as the handler says so.
+// Between retries, a backoff similar to the one from [http.Server.Serve] is used.
+// Once the listener is closed, the errors are returned directly without asking the handler, and so is
+// [net.ErrClosed], which no retry can recover from. Closing the listener interrupts the backoff.
+func (l *acceptErrorListener) Accept() (net.Conn, error) {
+	var delay time.Duration
+	for {
+		c, err := l.Listener.Accept()
+		if err == nil {
+			return c, nil
+		}
+		if l.isClosed() || errors.Is(err, net.ErrClosed) || !l.handler(err) {
+			return nil, err
+		}
+		if delay == 0 {
+			delay = acceptRetryMinDelay
+		} else {
+			delay = min(2*delay, acceptRetryMaxDelay)
+		}
+		select {
+		case <-time.After(delay):
+		case <-l.closeCh:
+			return nil, err
+		}
+	}
+}
+
+func (l *acceptErrorListener) Close() error {
+	l.closeOnce.Do(func() {
+		close(l.closeCh)
+	})
+	return l.Listener.Close()
+}
+
+func (l *acceptErrorListener) isClosed() bool {
+	select {
+	case <-l.closeCh:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,155 @@
+package chix
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestWithAcceptErrorHandler(t *testing.T) {
+	c := &Config{}
+	called := false
+	c.NewServer(WithAcceptErrorHandler(func(err error) bool {
+		called = true
+		return true
+	}))
+	if c.acceptErrorHandler == nil {
+		t.Fatalf("expected the accept error handler to be configured")
+	}
+	c.acceptErrorHandler(nil)
+	if !called {
+		t.Fatalf("expected the configured accept error handler to be the given one")
+	}
+}
+
+func TestAcceptErrorListener(t *testing.T) {
+	injectedErr := errors.New("injected accept error")
+	t.Run("handler returning true continues serving", func(t *testing.T) {
+		fl := &failingListener{errs: 3, err: injectedErr, blockCh: make(chan struct{})}
+		var calls atomic.Int32
+		l := newAcceptErrorListener(fl, func(err error) bool {
+			if !errors.Is(err, injectedErr) {
+				t.Errorf("expected the injected error but got: %s", err)
+			}
+			calls.Add(1)
+			return true
+		})
+		srv := http.Server{Handler: http.NewServeMux()}
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- srv.Serve(l)
+		}()
+		<-time.After(200 * time.Millisecond)
+		if got, want := calls.Load(), int32(3); got != want {
+			t.Errorf("expected the handler to be called %d times but got %d", want, got)
+		}
+		_ = srv.Close()
+		select {
+		case err := <-errCh:
+			if !errors.Is(err, http.ErrServerClosed) {
+				t.Errorf("expected the server to be closed gracefully but got: %s", err)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("server did not shut down in time")
+		}
+	})
+	t.Run("handler returning false stops serving", func(t *testing.T) {
+		fl := &failingListener{errs: 3, err: injectedErr, blockCh: make(chan struct{})}
+		var calls atomic.Int32
+		l := newAcceptErrorListener(fl, func(err error) bool {
+			calls.Add(1)
+			return false
+		})
+		srv := http.Server{Handler: http.NewServeMux()}
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- srv.Serve(l)
+		}()
+		select {
+		case err := <-errCh:
+			if !errors.Is(err, injectedErr) {
+				t.Errorf("expected the server to return the injected error but got: %v", err)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("server did not stop in time")
+		}
+		if got, want := calls.Load(), int32(1); got != want {
+			t.Errorf("expected the handler to be called %d times but got %d", want, got)
+		}
+	})
+	t.Run("net.ErrClosed stops serving regardless of the handler", func(t *testing.T) {
+		fl := &failingListener{errs: 1, err: net.ErrClosed, blockCh: make(chan struct{})}
+		var calls atomic.Int32
+		l := newAcceptErrorListener(fl, func(err error) bool {
+			calls.Add(1)
+			return true
+		})
+		if _, err := l.Accept(); !errors.Is(err, net.ErrClosed) {
+			t.Errorf("expected net.ErrClosed but got: %v", err)
+		}
+		if got := calls.Load(); got != 0 {
+			t.Errorf("expected the handler to not be called but it was called %d times", got)
+		}
+	})
+	t.Run("close interrupts the backoff", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			fl := &failingListener{errs: 100, err: injectedErr, blockCh: make(chan struct{})}
+			l := newAcceptErrorListener(fl, func(err error) bool {
+				return true
+			})
+			errCh := make(chan error, 1)
+			go func() {
+				_, err := l.Accept()
+				errCh <- err
+			}()
+			<-time.After(5 * time.Second) // let the backoff reach its maximum
+			begin := time.Now()
+			_ = l.Close()
+			if err := <-errCh; !errors.Is(err, injectedErr) {
+				t.Errorf("expected the last accept error but got: %v", err)
+			}
+			if got := time.Since(begin); got != 0 {
+				t.Errorf("expected accept to return right after close but it took %s", got)
+			}
+		})
+	})
+	t.Run("nil handler returns the listener as is", func(t *testing.T) {
+		fl := &failingListener{}
+		if l := newAcceptErrorListener(fl, nil); l != fl {
+			t.Errorf("expected the listener to not be wrapped")
+		}
+	})
+}
+
+// failingListener returns the configured error for the first [failingListener.errs] calls of
+// Accept and afterward blocks until it's closed.
+type failingListener struct {
+	errs    int
+	err     error
+	calls   atomic.Int32
+	blockCh chan struct{}
+	closed  atomic.Bool
+}
+
+func (f *failingListener) Accept() (net.Conn, error) {
+	if int(f.calls.Add(1)) <= f.errs {
+		return nil, f.err
+	}
+	<-f.blockCh
+	return nil, net.ErrClosed
+}
+
+func (f *failingListener) Close() error {
+	if f.closed.CompareAndSwap(false, true) {
+		close(f.blockCh)
+	}
+	return nil
+}
+
+func (f *failingListener) Addr() net.Addr {
+	return &net.TCPAddr{}
+}
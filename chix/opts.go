@@ -3,11 +3,14 @@ package chix
 import (
 	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/httplog/v3"
 )
 
+const defaultDrainTimeout = 5 * time.Second
+
 // Config can be embedded in your configs and map flags and env vars directly to the
 // [Config.Host] and [Config.Port] attributes.
 //
@@ -17,7 +20,16 @@ type Config struct {
 	Host string
 	Port int
 
-	middlewares []func(http.Handler) http.Handler
+	middlewares        []func(http.Handler) http.Handler
+	acceptErrorHandler func(error) bool
+	warnEmptyRouter    bool
+
+	drainTimeout    time.Duration
+	shutdownGrace   time.Duration
+	onDrainStart    func(inFlight int)
+	onDrainComplete func(DrainStats)
+
+	http2 *http.HTTP2Config
 }
 
 // setDefaults configures defaults on the config.
@@ -30,6 +42,9 @@ func (c *Config) setDefaults() {
 		middleware.RealIP,
 		httplog.RequestLogger(slog.Default(), &httplog.Options{}), // Using slog.Default() because this is configured at the app level. Check main.go
 	}
+	if c.drainTimeout == 0 {
+		c.drainTimeout = defaultDrainTimeout
+	}
 }
 
 type Opt func(*Config)
@@ -57,3 +72,74 @@ func WithMiddlewares(m ...func(http.Handler) http.Handler) Opt {
 		config.middlewares = m
 	}
 }
+
+// WithAcceptErrorHandler configures a handler that is called with each error returned while accepting
+// new connections. The returned bool decides if the server should continue serving (true) or should
+// stop and return the error from [Server.Start] (false).
+// Without this option, the server is handling the errors as [http.Server.Serve] does.
+func WithAcceptErrorHandler(h func(error) bool) Opt {
+	return func(config *Config) {
+		config.acceptErrorHandler = h
+	}
+}
+
+// WithEmptyRouterWarning enables logging a warning when [Server.Start] is called on a router without
+// any route configured. This catches the "server starts but has no routes" misconfiguration.
+func WithEmptyRouterWarning() Opt {
+	return func(config *Config) {
+		config.warnEmptyRouter = true
+	}
+}
+
+// DrainStats describes how the draining of the connections went during the server shutdown.
+type DrainStats struct {
+	// InFlight is the number of requests that were in progress when the draining started.
+	InFlight int
+	// Duration is how long the draining took.
+	Duration time.Duration
+	// TimedOut is true when the draining did not finish in the configured timeout and the
+	// remaining connections were closed forcefully.
+	TimedOut bool
+}
+
+// WithDrainTimeout configures how long the server waits for the in-flight requests to finish on shutdown,
+// before closing the connections forcefully. Default: 5s
+func WithDrainTimeout(d time.Duration) Opt {
+	return func(config *Config) {
+		config.drainTimeout = d
+	}
+}
+
+// WithShutdownGrace configures the server to keep serving for the grace period after a system signal is received,
+// and only then to start draining the connections, as described in [github.com/yottta/go-core/shutdown.Graceful]. This gives the load
+// balancers the time to stop routing new requests to the server. A second signal starts the draining right away.
+// Without this option, the draining starts as soon as the signal is received.
+func WithShutdownGrace(d time.Duration) Opt {
+	return func(config *Config) {
+		config.shutdownGrace = d
+	}
+}
+
+// WithOnDrainStart configures a hook called when the server starts draining the connections, with the
+// number of requests in progress at that moment.
+func WithOnDrainStart(fn func(inFlight int)) Opt {
+	return func(config *Config) {
+		config.onDrainStart = fn
+	}
+}
+
+// WithOnDrainComplete configures a hook called once the draining of the connections is over.
+func WithOnDrainComplete(fn func(DrainStats)) Opt {
+	return func(config *Config) {
+		config.onDrainComplete = fn
+	}
+}
+
+// WithHTTP2 configures the HTTP/2 settings of the server (ie: MaxConcurrentStreams, MaxReadFrameSize, idle timeouts).
+// Since the server is listening without TLS, this also enables the unencrypted HTTP/2 (h2c, with prior knowledge)
+// next to HTTP/1. Without this option, the server is using the [net/http] defaults.
+func WithHTTP2(cfg http.HTTP2Config) Opt {
+	return func(config *Config) {
+		config.http2 = &cfg
+	}
+}
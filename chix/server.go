@@ -7,9 +7,12 @@ import (
 	"log/slog"
 	"net"
 	"net/http"
+	"reflect"
 	"sync"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/yottta/go-core/logging"
 	"github.com/yottta/go-core/shutdown"
 )
 
@@ -27,8 +30,9 @@ func (c *Config) NewServer(opts ...Opt) *Server {
 		c.middlewares...,
 	)
 	return &Server{
-		config: *c,
-		router: r,
+		config:  *c,
+		router:  r,
+		readyCh: make(chan struct{}),
 	}
 }
 
@@ -43,10 +47,13 @@ type Server struct {
 
 	started  bool
 	startedM sync.Mutex
+
+	readyCh chan struct{}
 }
 
 // Start is starting the listening for connections.
-// The received [ctx] is used to close the server on cancellation.
+// The received [ctx] is used to close the server on cancellation. On close, the in-flight requests are
+// given time to finish, as configured by [WithDrainTimeout], before the connections are closed forcefully.
 //
 // This method uses the [Config.Host] and [Config.Port] to start the listener. If
 // these are not configured, the [net] package will allocate an available one.
@@ -57,12 +64,24 @@ func (r *Server) Start(ctx context.Context) error {
 	var cancel context.CancelFunc
 	var l net.Listener
 	var err error
+	conns := newConnTracker()
 	configure := func() { // anonymous function for locking
 		r.startedM.Lock()
 		defer r.startedM.Unlock()
+		if err = r.validateRoutes(); err != nil {
+			return
+		}
 		// No need to defer this cancel since this will be called in [Server.Close] or the cancel
 		// will be canceled when a sys signal will be issued.
-		ctx, cancel = shutdown.Context(ctx)
+		// the logger of the signals configured by the app, if any, is kept
+		if !shutdown.SignalsLogged() {
+			shutdown.LogSignals(slog.Default())
+		}
+		if r.config.shutdownGrace > 0 {
+			ctx, cancel = shutdown.Graceful(ctx, r.config.shutdownGrace)
+		} else {
+			ctx, cancel = shutdown.Context(ctx)
+		}
 		r.closeFn = cancel
 
 		addr := fmt.Sprintf("%s:%d", r.config.Host, r.config.Port)
@@ -70,10 +89,24 @@ func (r *Server) Start(ctx context.Context) error {
 		if err != nil {
 			return
 		}
+		l = newAcceptErrorListener(l, r.config.acceptErrorHandler)
 
+		if !r.started {
+			close(r.readyCh)
+		}
 		r.started = true
 		srv = http.Server{
-			Handler: r.router,
+			Handler:   r.router,
+			ConnState: conns.track,
+			ErrorLog:  logging.StdLogger(slog.LevelWarn),
+		}
+		if r.config.http2 != nil {
+			h2 := *r.config.http2
+			srv.HTTP2 = &h2
+			var protocols http.Protocols
+			protocols.SetHTTP1(true)
+			protocols.SetUnencryptedHTTP2(true)
+			srv.Protocols = &protocols
 		}
 	}
 	configure()
@@ -81,12 +114,13 @@ func (r *Server) Start(ctx context.Context) error {
 		return err
 	}
 
+	drainedCh := make(chan struct{})
 	go func() {
+		defer close(drainedCh)
 		select {
 		case <-ctx.Done():
-			if err := srv.Close(); err != nil {
-				slog.With("error", err).Info("http server closing on context.Done returned error")
-			}
+			slog.With("cause", context.Cause(ctx)).Info("http server shutting down")
+			r.drain(&srv, conns)
 		}
 	}()
 
@@ -95,11 +129,72 @@ func (r *Server) Start(ctx context.Context) error {
 		slog.With("error", err).Warn("http server closed with error")
 		return err
 	}
+	<-drainedCh
 	slog.Debug("http server closed gracefully")
 
 	return nil
 }
 
+// drain shuts down the server waiting for the in-flight requests to finish, but not longer than the
+// configured drain timeout. After the timeout, the remaining connections are closed forcefully.
+// The drain hooks are called around this process.
+func (r *Server) drain(srv *http.Server, conns *connTracker) {
+	inFlight := conns.active()
+	if r.config.onDrainStart != nil {
+		r.config.onDrainStart(inFlight)
+	}
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), r.config.drainTimeout)
+	defer cancel()
+	err := srv.Shutdown(ctx)
+	if err != nil {
+		slog.With("error", err).Info("http server draining did not finish, closing remaining connections")
+		if err := srv.Close(); err != nil {
+			slog.With("error", err).Info("http server closing on context.Done returned error")
+		}
+	}
+	if r.config.onDrainComplete != nil {
+		r.config.onDrainComplete(DrainStats{
+			InFlight: inFlight,
+			Duration: time.Since(start),
+			TimedOut: err != nil,
+		})
+	}
+}
+
+// validateRoutes checks that none of the configured routes has a nil handler.
+// When enabled by [WithEmptyRouterWarning], it also logs a warning when there is no route configured.
+func (r *Server) validateRoutes() error {
+	var count int
+	err := chi.Walk(r.router, func(method string, route string, handler http.Handler, _ ...func(http.Handler) http.Handler) error {
+		count++
+		if isNilHandler(handler) {
+			return fmt.Errorf("route %s %s has a nil handler", method, route)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if count == 0 && r.config.warnEmptyRouter {
+		slog.Warn("http server has no routes configured")
+	}
+	return nil
+}
+
+// isNilHandler checks also the handlers that are typed nils, like a nil [http.HandlerFunc].
+func isNilHandler(h http.Handler) bool {
+	if h == nil {
+		return true
+	}
+	v := reflect.ValueOf(h)
+	switch v.Kind() {
+	case reflect.Func, reflect.Pointer, reflect.Map, reflect.Slice, reflect.Chan, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
+
 // Close is stopping the listening. If the server was not started, this
 // method will do nothing.
 func (r *Server) Close() {
@@ -112,6 +207,12 @@ func (r *Server) Close() {
 	r.closeFn()
 }
 
+// Ready returns a channel that is closed once the server is listening for connections, allowing to tell apart
+// a server that failed to start (ie: the port is in use), whose [Server.Start] returns an error, from a running one.
+func (r *Server) Ready() <-chan struct{} {
+	return r.readyCh
+}
+
 // Router returns the inner router to allow configuration of routes.
 // Calling this method after [Server.Start] has been called, will panic.
 func (r *Server) Router() chi.Router {
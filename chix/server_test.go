@@ -1,14 +1,20 @@
 package chix
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
+	"log/slog"
+	"net"
 	"net/http"
 	"strings"
 	"sync"
+	"syscall"
 	"testing"
 	"time"
+
+	"github.com/yottta/go-core/shutdown"
 )
 
 func TestServerStartStop(t *testing.T) {
@@ -210,3 +216,229 @@ func TestServerStartStop(t *testing.T) {
 		})
 	})
 }
+
+func TestServerRouteValidation(t *testing.T) {
+	t.Run("warns on empty router when enabled", func(t *testing.T) {
+		var b bytes.Buffer
+		prev := slog.Default()
+		slog.SetDefault(slog.New(slog.NewTextHandler(&b, nil)))
+		defer slog.SetDefault(prev)
+
+		cfg := &Config{
+			Host: "localhost",
+			Port: 0,
+		}
+		srv := cfg.NewServer(WithEmptyRouterWarning())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- srv.Start(ctx)
+		}()
+		<-time.After(100 * time.Millisecond)
+		cancel()
+		select {
+		case err := <-errCh:
+			if err != nil {
+				t.Errorf("expected no error on graceful shutdown, got: %v", err)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("server did not shut down in time")
+		}
+		if want := "http server has no routes configured"; !strings.Contains(b.String(), want) {
+			t.Errorf("expected logs to contain %q but got:\n%s", want, b.String())
+		}
+	})
+	t.Run("fails on nil handler", func(t *testing.T) {
+		cfg := &Config{
+			Host: "localhost",
+			Port: 0,
+		}
+		srv := cfg.NewServer()
+		srv.Router().Get("/ping", func(w http.ResponseWriter, r *http.Request) {})
+		srv.Router().Get("/nil", nil)
+
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- srv.Start(context.Background())
+		}()
+		select {
+		case err := <-errCh:
+			want := "route GET /nil has a nil handler"
+			if err == nil || err.Error() != want {
+				t.Errorf("expected error %q but got: %v", want, err)
+			}
+		case <-time.After(2 * time.Second):
+			srv.Close()
+			t.Fatal("server started even though a route has a nil handler")
+		}
+	})
+}
+
+func TestServerHTTP2(t *testing.T) {
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("failed to find a free port: %s", err)
+	}
+	port := l.Addr().(*net.TCPAddr).Port
+	_ = l.Close()
+
+	cfg := &Config{
+		Host: "localhost",
+		Port: port,
+	}
+	srv := cfg.NewServer(WithHTTP2(http.HTTP2Config{MaxConcurrentStreams: 2}))
+	// the streams are counted per connection since the client opens new connections when the limit is reached
+	var (
+		m          sync.Mutex
+		running    = map[string]int{}
+		maxRunning int
+	)
+	srv.Router().Get("/slow", func(w http.ResponseWriter, r *http.Request) {
+		m.Lock()
+		running[r.RemoteAddr]++
+		maxRunning = max(maxRunning, running[r.RemoteAddr])
+		m.Unlock()
+		<-time.After(100 * time.Millisecond)
+		m.Lock()
+		running[r.RemoteAddr]--
+		m.Unlock()
+		_, _ = w.Write([]byte(r.Proto))
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.Start(ctx)
+	}()
+	<-time.After(100 * time.Millisecond)
+
+	var protocols http.Protocols
+	protocols.SetUnencryptedHTTP2(true)
+	client := &http.Client{Transport: &http.Transport{Protocols: &protocols}}
+	var wg sync.WaitGroup
+	for range 6 {
+		wg.Go(func() {
+			resp, err := client.Get(fmt.Sprintf("http://localhost:%d/slow", port))
+			if err != nil {
+				t.Errorf("failed to send the request: %s", err)
+				return
+			}
+			defer func() { _ = resp.Body.Close() }()
+			body, _ := io.ReadAll(resp.Body)
+			if got, want := string(body), "HTTP/2.0"; got != want {
+				t.Errorf("got a different protocol than the wanted one. expected: %q; got: %q", want, got)
+			}
+		})
+	}
+	wg.Wait()
+	if got, want := maxRunning, 2; got != want {
+		t.Errorf("got a different max of concurrent streams than the wanted one. expected: %d; got: %d", want, got)
+	}
+
+	cancel()
+	select {
+	case <-errCh:
+	case <-time.After(2 * time.Second):
+		t.Fatal("server did not shut down in time")
+	}
+}
+
+func TestServerShutdownGrace(t *testing.T) {
+	shutdown.EnableTestMode()
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("failed to find a free port: %s", err)
+	}
+	port := l.Addr().(*net.TCPAddr).Port
+	_ = l.Close()
+
+	cfg := &Config{
+		Host: "localhost",
+		Port: port,
+	}
+	srv := cfg.NewServer(WithShutdownGrace(500 * time.Millisecond))
+	srv.Router().Get("/ping", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("pong"))
+	})
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.Start(context.Background())
+	}()
+	<-time.After(100 * time.Millisecond)
+
+	shutdown.Trigger(syscall.SIGTERM)
+	<-time.After(100 * time.Millisecond)
+	resp, err := http.Get(fmt.Sprintf("http://localhost:%d/ping", port))
+	if err != nil {
+		t.Fatalf("expected the server to keep serving during the grace period but got: %s", err)
+	}
+	_ = resp.Body.Close()
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			t.Errorf("expected no error on graceful shutdown, got: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("server did not shut down after the grace period")
+	}
+}
+
+func TestServerKeepsTheSignalsLogger(t *testing.T) {
+	shutdown.EnableTestMode()
+	var b bytes.Buffer
+	shutdown.LogSignals(slog.New(slog.NewTextHandler(&b, nil)))
+	srv := (&Config{Host: "localhost"}).NewServer()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.Start(context.Background())
+	}()
+	<-srv.Ready()
+	shutdown.Trigger(syscall.SIGTERM)
+	if err := <-errCh; err != nil {
+		t.Errorf("expected no error on graceful shutdown, got: %v", err)
+	}
+	if want := `msg="received SIGTERM"`; !strings.Contains(b.String(), want) {
+		t.Errorf("expected logs to contain %q but got:\n%s", want, b.String())
+	}
+}
+
+func TestServerReady(t *testing.T) {
+	t.Run("ready once listening", func(t *testing.T) {
+		srv := (&Config{Host: "localhost"}).NewServer()
+		ctx, cancel := context.WithCancel(context.Background())
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- srv.Start(ctx)
+		}()
+		select {
+		case <-srv.Ready():
+		case err := <-errCh:
+			t.Fatalf("expected the server to be ready but it returned: %v", err)
+		case <-time.After(2 * time.Second):
+			t.Fatal("server did not become ready in time")
+		}
+		cancel()
+		if err := <-errCh; err != nil {
+			t.Errorf("expected no error on graceful shutdown, got: %v", err)
+		}
+	})
+	t.Run("not ready when the port is in use", func(t *testing.T) {
+		l, err := net.Listen("tcp", "localhost:0")
+		if err != nil {
+			t.Fatalf("failed to listen: %s", err)
+		}
+		defer func() { _ = l.Close() }()
+		srv := (&Config{Host: "localhost", Port: l.Addr().(*net.TCPAddr).Port}).NewServer()
+		if err := srv.Start(context.Background()); err == nil {
+			t.Fatalf("expected the server to fail to start")
+		}
+		select {
+		case <-srv.Ready():
+			t.Errorf("expected the server not to be ready")
+		default:
+		}
+	})
+}
@@ -0,0 +1,122 @@
+package chix
+
+import (
+	"errors"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// StaticOpt configures how the static files are served by [StaticFS] and [StaticDir].
+type StaticOpt func(*staticConfig)
+
+type staticConfig struct {
+	spaFallback      string
+	directoryListing bool
+	dotFiles         bool
+}
+
+// WithSPAFallback serves the given file, instead of answering with http.StatusNotFound, when the requested
+// file does not exist. This is needed by the single page applications that handle the routing in the browser.
+func WithSPAFallback(file string) StaticOpt {
+	return func(c *staticConfig) {
+		c.spaFallback = strings.TrimPrefix(path.Clean("/"+file), "/")
+	}
+}
+
+// WithDirectoryListing enables listing the content of the directories without an index.html.
+// By default, such requests are answered with http.StatusNotFound.
+func WithDirectoryListing() StaticOpt {
+	return func(c *staticConfig) {
+		c.directoryListing = true
+	}
+}
+
+// WithDotFiles allows serving the files and directories whose name starts with a dot.
+// By default, these are hidden to avoid leaking files like .env or .git.
+func WithDotFiles() StaticOpt {
+	return func(c *staticConfig) {
+		c.dotFiles = true
+	}
+}
+
+// StaticFS configures the router to serve the files from the given [fs.FS] under the given url prefix.
+// This is useful to serve the assets embedded with go:embed.
+func StaticFS(router chi.Router, urlPrefix string, fsys fs.FS, opts ...StaticOpt) {
+	var c staticConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+	prefix := strings.TrimSuffix(urlPrefix, "/")
+	if prefix != "" {
+		router.Get(prefix, http.RedirectHandler(prefix+"/", http.StatusMovedPermanently).ServeHTTP)
+	}
+	h := http.StripPrefix(prefix, &staticHandler{fsys: fsys, config: c})
+	router.Get(prefix+"/*", h.ServeHTTP)
+	router.Head(prefix+"/*", h.ServeHTTP)
+}
+
+// StaticDir is the same as [StaticFS] but serves the files from the given directory.
+func StaticDir(router chi.Router, urlPrefix string, dir string, opts ...StaticOpt) {
+	StaticFS(router, urlPrefix, os.DirFS(dir), opts...)
+}
+
+type staticHandler struct {
+	fsys   fs.FS
+	config staticConfig
+}
+
+func (h *staticHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+	if name == "" {
+		name = "."
+	}
+	if !h.config.dotFiles && hasDotSegment(name) {
+		http.NotFound(w, r)
+		return
+	}
+	info, err := fs.Stat(h.fsys, name)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		h.fallback(w, r)
+		return
+	case err != nil:
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	if !info.IsDir() {
+		http.ServeFileFS(w, r, h.fsys, name)
+		return
+	}
+	index := path.Join(name, "index.html")
+	if _, err := fs.Stat(h.fsys, index); err == nil {
+		http.ServeFileFS(w, r, h.fsys, index)
+		return
+	}
+	if h.config.directoryListing {
+		http.ServeFileFS(w, r, h.fsys, name)
+		return
+	}
+	h.fallback(w, r)
+}
+
+func (h *staticHandler) fallback(w http.ResponseWriter, r *http.Request) {
+	if h.config.spaFallback == "" {
+		http.NotFound(w, r)
+		return
+	}
+	http.ServeFileFS(w, r, h.fsys, h.config.spaFallback)
+}
+
+func hasDotSegment(name string) bool {
+	for _, s := range strings.Split(name, "/") {
+		if strings.HasPrefix(s, ".") && s != "." {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package chix
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestStaticFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"index.html":     {Data: []byte("index")},
+		"css/app.css":    {Data: []byte("body{}")},
+		"docs/readme.md": {Data: []byte("readme")},
+		".env":           {Data: []byte("SECRET=1")},
+	}
+	get := func(t *testing.T, h http.Handler, target string) (int, string) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		body, _ := io.ReadAll(rec.Result().Body)
+		return rec.Code, string(body)
+	}
+
+	r := chi.NewRouter()
+	StaticFS(r, "/assets/", fsys)
+	cases := map[string]struct {
+		target     string
+		wantStatus int
+		wantBody   string
+	}{
+		"found file":           {target: "/assets/css/app.css", wantStatus: http.StatusOK, wantBody: "body{}"},
+		"index of the root":    {target: "/assets/", wantStatus: http.StatusOK, wantBody: "index"},
+		"missing file":         {target: "/assets/missing.js", wantStatus: http.StatusNotFound},
+		"directory listing":    {target: "/assets/docs/", wantStatus: http.StatusNotFound},
+		"dot file":             {target: "/assets/.env", wantStatus: http.StatusNotFound},
+		"path traversal":       {target: "/assets/../assets/.env", wantStatus: http.StatusNotFound},
+		"prefix without slash": {target: "/assets", wantStatus: http.StatusMovedPermanently},
+	}
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			status, body := get(t, r, tt.target)
+			if status != tt.wantStatus {
+				t.Errorf("expected status %d but got %d", tt.wantStatus, status)
+			}
+			if tt.wantBody != "" && body != tt.wantBody {
+				t.Errorf("expected body %q but got %q", tt.wantBody, body)
+			}
+		})
+	}
+
+	t.Run("spa fallback", func(t *testing.T) {
+		r := chi.NewRouter()
+		StaticFS(r, "/", fsys, WithSPAFallback("index.html"))
+		status, body := get(t, r, "/users/123")
+		if status != http.StatusOK || body != "index" {
+			t.Errorf("expected the fallback to be served but got %d %q", status, body)
+		}
+	})
+	t.Run("directory listing enabled", func(t *testing.T) {
+		r := chi.NewRouter()
+		StaticFS(r, "/", fsys, WithDirectoryListing())
+		status, body := get(t, r, "/docs/")
+		if status != http.StatusOK || body == "" {
+			t.Errorf("expected the directory listing but got %d %q", status, body)
+		}
+	})
+	t.Run("dot files enabled", func(t *testing.T) {
+		r := chi.NewRouter()
+		StaticFS(r, "/", fsys, WithDotFiles())
+		status, body := get(t, r, "/.env")
+		if status != http.StatusOK || body != "SECRET=1" {
+			t.Errorf("expected the dot file to be served but got %d %q", status, body)
+		}
+	})
+}
@@ -0,0 +1,35 @@
+package env
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// CIDRs reads the env var as a comma separated list of CIDRs (ie: 10.0.0.0/8,192.168.0.0/16).
+// Each entry is validated and, on the first invalid one, an error naming it is returned.
+// When the env var is not set, nil is returned without an error.
+func CIDRs(k string) ([]netip.Prefix, error) {
+	return defaultScope().CIDRs(k)
+}
+
+func (s *Scope) CIDRs(k string) ([]netip.Prefix, error) {
+	v := s.get(k)
+	if v == "" {
+		return nil, nil
+	}
+	parts := strings.Split(v, ",")
+	res := make([]netip.Prefix, 0, len(parts))
+	for i, p := range parts {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		prefix, err := netip.ParsePrefix(p)
+		if err != nil {
+			return nil, fmt.Errorf("env var %s contains invalid CIDR %q at index %d: %w", k, p, i, err)
+		}
+		res = append(res, prefix)
+	}
+	return res, nil
+}
@@ -0,0 +1,51 @@
+package env
+
+import (
+	"net/netip"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCIDRs(t *testing.T) {
+	t.Run("valid cidrs", func(t *testing.T) {
+		setupEnvVars(t, map[string]string{"TRUSTED_CIDRS": "10.0.0.0/8, 192.168.0.0/16,fd00::/8"})
+		got, err := CIDRs("TRUSTED_CIDRS")
+		if err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		want := []netip.Prefix{
+			netip.MustParsePrefix("10.0.0.0/8"),
+			netip.MustParsePrefix("192.168.0.0/16"),
+			netip.MustParsePrefix("fd00::/8"),
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("got a different value than the wanted one. expected: %v; got: %v", want, got)
+		}
+	})
+	t.Run("env var not found", func(t *testing.T) {
+		got, err := CIDRs("TRUSTED_CIDRS")
+		if err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		if got != nil {
+			t.Errorf("expected nil but got: %v", got)
+		}
+	})
+	t.Run("invalid cidr", func(t *testing.T) {
+		setupEnvVars(t, map[string]string{"TRUSTED_CIDRS": "10.0.0.0/8,10.0.0.300/8"})
+		_, err := CIDRs("TRUSTED_CIDRS")
+		if err == nil {
+			t.Fatalf("expected an error but got nothing")
+		}
+		if want := `"10.0.0.300/8"`; !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to contain %q but got %q", want, err.Error())
+		}
+	})
+	t.Run("ip without prefix length is invalid", func(t *testing.T) {
+		setupEnvVars(t, map[string]string{"TRUSTED_CIDRS": "10.0.0.1"})
+		if _, err := CIDRs("TRUSTED_CIDRS"); err == nil {
+			t.Fatalf("expected an error but got nothing")
+		}
+	})
+}
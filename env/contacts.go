@@ -0,0 +1,73 @@
+package env
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+)
+
+// Emails reads the env var as a comma separated list of email addresses (ie: ops@example.com,Dev <dev@example.com>).
+// Each entry is validated with [mail.ParseAddress] and the bare addresses are returned. When any entry is invalid,
+// an error naming all the invalid entries is returned, so no notification target is silently dropped.
+// When the env var is not set, nil is returned without an error.
+func Emails(k string) ([]string, error) {
+	return defaultScope().Emails(k)
+}
+
+func (s *Scope) Emails(k string) ([]string, error) {
+	return listOf(s, k, "email addresses", func(v string) (string, error) {
+		addr, err := mail.ParseAddress(v)
+		if err != nil {
+			return "", err
+		}
+		return addr.Address, nil
+	})
+}
+
+// URLs reads the env var as a comma separated list of absolute URLs (ie: https://hooks.example.com/alerts).
+// Each entry needs a scheme and a host. When any entry is invalid, an error naming all the invalid entries
+// is returned. When the env var is not set, nil is returned without an error.
+func URLs(k string) ([]*url.URL, error) {
+	return defaultScope().URLs(k)
+}
+
+func (s *Scope) URLs(k string) ([]*url.URL, error) {
+	return listOf(s, k, "URLs", func(v string) (*url.URL, error) {
+		u, err := url.Parse(v)
+		if err != nil {
+			return nil, err
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("missing scheme or host")
+		}
+		return u, nil
+	})
+}
+
+// listOf parses the comma separated entries of the env var, collecting all the invalid ones in the returned error.
+func listOf[T any](s *Scope, k string, what string, parse func(string) (T, error)) ([]T, error) {
+	v := s.get(k)
+	if v == "" {
+		return nil, nil
+	}
+	parts := strings.Split(v, ",")
+	res := make([]T, 0, len(parts))
+	var invalid []string
+	for _, p := range parts {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		parsed, err := parse(p)
+		if err != nil {
+			invalid = append(invalid, fmt.Sprintf("%q", p))
+			continue
+		}
+		res = append(res, parsed)
+	}
+	if len(invalid) > 0 {
+		return nil, fmt.Errorf("env var %s contains invalid %s: %s", k, what, strings.Join(invalid, ", "))
+	}
+	return res, nil
+}
@@ -0,0 +1,66 @@
+package env
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestEmails(t *testing.T) {
+	t.Run("valid emails", func(t *testing.T) {
+		setupEnvVars(t, map[string]string{"ALERT_EMAILS": "ops@example.com, Dev Team <dev@example.com>,"})
+		got, err := Emails("ALERT_EMAILS")
+		if err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		want := []string{"ops@example.com", "dev@example.com"}
+		if !slices.Equal(got, want) {
+			t.Errorf("got a different value than the wanted one. expected: %v; got: %v", want, got)
+		}
+	})
+	t.Run("env var not found", func(t *testing.T) {
+		got, err := Emails("ALERT_EMAILS")
+		if err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		if got != nil {
+			t.Errorf("expected nil but got: %v", got)
+		}
+	})
+	t.Run("invalid emails", func(t *testing.T) {
+		setupEnvVars(t, map[string]string{"ALERT_EMAILS": "ops@example.com,not-an-email,dev@"})
+		got, err := Emails("ALERT_EMAILS")
+		want := `env var ALERT_EMAILS contains invalid email addresses: "not-an-email", "dev@"`
+		if err == nil || err.Error() != want {
+			t.Fatalf("got a different error than the wanted one. expected: %q; got: %v", want, err)
+		}
+		if got != nil {
+			t.Errorf("expected nil but got: %v", got)
+		}
+	})
+}
+
+func TestURLs(t *testing.T) {
+	t.Run("valid urls", func(t *testing.T) {
+		setupEnvVars(t, map[string]string{"ALERT_WEBHOOKS": "https://hooks.example.com/alerts, http://localhost:8080/hook"})
+		got, err := URLs("ALERT_WEBHOOKS")
+		if err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		var gotS []string
+		for _, u := range got {
+			gotS = append(gotS, u.String())
+		}
+		want := []string{"https://hooks.example.com/alerts", "http://localhost:8080/hook"}
+		if !slices.Equal(gotS, want) {
+			t.Errorf("got a different value than the wanted one. expected: %v; got: %v", want, gotS)
+		}
+	})
+	t.Run("invalid urls", func(t *testing.T) {
+		setupEnvVars(t, map[string]string{"ALERT_WEBHOOKS": "hooks.example.com/alerts,https://ok.example.com,http://[::1"})
+		_, err := URLs("ALERT_WEBHOOKS")
+		want := `env var ALERT_WEBHOOKS contains invalid URLs: "hooks.example.com/alerts", "http://[::1"`
+		if err == nil || err.Error() != want {
+			t.Fatalf("got a different error than the wanted one. expected: %q; got: %v", want, err)
+		}
+	})
+}
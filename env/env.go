@@ -1,57 +1,84 @@
 package env
 
 import (
-	"log/slog"
 	"os"
 	"strconv"
 )
 
 func Expand(v string) string {
-	return os.ExpandEnv(v)
+	return defaultScope().Expand(v)
 }
 
 func StringWithDefault(k string, def string) string {
-	v := os.Getenv(k)
+	return defaultScope().StringWithDefault(k, def)
+}
+
+func String(k string) string {
+	return defaultScope().String(k)
+}
+
+func BoolWithDefault(k string, def bool) bool {
+	return defaultScope().BoolWithDefault(k, def)
+}
+
+func Bool(k string) bool {
+	return defaultScope().Bool(k)
+}
+
+func IntWithDefault(k string, def int) int {
+	return defaultScope().IntWithDefault(k, def)
+}
+
+func Int(k string) int {
+	return defaultScope().Int(k)
+}
+
+func (s *Scope) Expand(v string) string {
+	return os.Expand(v, s.get)
+}
+
+func (s *Scope) StringWithDefault(k string, def string) string {
+	v := s.get(k)
 	if v == "" {
 		return def
 	}
 	return v
 }
 
-func String(k string) string {
-	return StringWithDefault(k, "")
+func (s *Scope) String(k string) string {
+	return s.StringWithDefault(k, "")
 }
 
-func BoolWithDefault(k string, def bool) bool {
-	v := os.Getenv(k)
+func (s *Scope) BoolWithDefault(k string, def bool) bool {
+	v := s.get(k)
 	if v == "" {
 		return def
 	}
 	val, err := strconv.ParseBool(v)
 	if err != nil {
-		slog.With("key", k).Warn("env var not a bool")
+		warn(k, v, "env var not a bool")
 		return def
 	}
 	return val
 }
 
-func Bool(k string) bool {
-	return BoolWithDefault(k, false)
+func (s *Scope) Bool(k string) bool {
+	return s.BoolWithDefault(k, false)
 }
 
-func IntWithDefault(k string, def int) int {
-	v := os.Getenv(k)
+func (s *Scope) IntWithDefault(k string, def int) int {
+	v := s.get(k)
 	if v == "" {
 		return def
 	}
 	val, err := strconv.Atoi(v)
 	if err != nil {
-		slog.With("key", k).Warn("env var not an int")
+		warn(k, v, "env var not an int")
 		return def
 	}
 	return val
 }
 
-func Int(k string) int {
-	return IntWithDefault(k, 0)
+func (s *Scope) Int(k string) int {
+	return s.IntWithDefault(k, 0)
 }
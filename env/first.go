@@ -0,0 +1,54 @@
+package env
+
+import (
+	"strconv"
+	"time"
+)
+
+// FirstString returns the first set and non-empty value among the given keys, checked in order.
+// This is useful for backward compatible renames of env vars (ie: check NEW_NAME and then OLD_NAME).
+// The returned bool is false when none of the keys is set.
+func FirstString(keys ...string) (string, bool) {
+	return defaultScope().FirstString(keys...)
+}
+
+// FirstInt is the same as [FirstString] but parses the value as an int.
+// The keys with a value that is not an int are skipped.
+func FirstInt(keys ...string) (int, bool) {
+	return defaultScope().FirstInt(keys...)
+}
+
+// FirstDuration is the same as [FirstString] but parses the value as a [time.Duration].
+// The keys with a value that is not a duration are skipped.
+func FirstDuration(keys ...string) (time.Duration, bool) {
+	return defaultScope().FirstDuration(keys...)
+}
+
+func (s *Scope) FirstString(keys ...string) (string, bool) {
+	return first(s, keys, func(v string) (string, error) { return v, nil })
+}
+
+func (s *Scope) FirstInt(keys ...string) (int, bool) {
+	return first(s, keys, strconv.Atoi)
+}
+
+func (s *Scope) FirstDuration(keys ...string) (time.Duration, bool) {
+	return first(s, keys, time.ParseDuration)
+}
+
+func first[T any](s *Scope, keys []string, parse func(string) (T, error)) (T, bool) {
+	for _, k := range keys {
+		v := s.get(k)
+		if v == "" {
+			continue
+		}
+		val, err := parse(v)
+		if err != nil {
+			warn(k, v, "env var could not be parsed, checking the next key", "error", err)
+			continue
+		}
+		return val, true
+	}
+	var zero T
+	return zero, false
+}
@@ -0,0 +1,60 @@
+package env
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFirstString(t *testing.T) {
+	t.Run("first key wins", func(t *testing.T) {
+		setupEnvVars(t, map[string]string{"NEW_NAME": "new", "OLD_NAME": "old"})
+		got, ok := FirstString("NEW_NAME", "OLD_NAME")
+		if !ok || got != "new" {
+			t.Errorf("got a different value than the wanted one. expected: %q; got: %q (found: %t)", "new", got, ok)
+		}
+	})
+	t.Run("falls back on the next key", func(t *testing.T) {
+		setupEnvVars(t, map[string]string{"NEW_NAME": "", "OLD_NAME": "old"})
+		got, ok := FirstString("NEW_NAME", "OLD_NAME")
+		if !ok || got != "old" {
+			t.Errorf("got a different value than the wanted one. expected: %q; got: %q (found: %t)", "old", got, ok)
+		}
+	})
+	t.Run("all unset", func(t *testing.T) {
+		got, ok := FirstString("NEW_NAME", "OLD_NAME")
+		if ok || got != "" {
+			t.Errorf("expected nothing to be found but got %q (found: %t)", got, ok)
+		}
+	})
+}
+
+func TestFirstInt(t *testing.T) {
+	t.Run("skips invalid values", func(t *testing.T) {
+		setupEnvVars(t, map[string]string{"NEW_NAME": "12a", "OLD_NAME": "12"})
+		got, ok := FirstInt("NEW_NAME", "OLD_NAME")
+		if !ok || got != 12 {
+			t.Errorf("got a different value than the wanted one. expected: %d; got: %d (found: %t)", 12, got, ok)
+		}
+	})
+	t.Run("all unset", func(t *testing.T) {
+		if got, ok := FirstInt("NEW_NAME", "OLD_NAME"); ok || got != 0 {
+			t.Errorf("expected nothing to be found but got %d (found: %t)", got, ok)
+		}
+	})
+}
+
+func TestFirstDuration(t *testing.T) {
+	t.Run("falls back on the next key", func(t *testing.T) {
+		setupEnvVars(t, map[string]string{"OLD_NAME": "5s"})
+		got, ok := FirstDuration("NEW_NAME", "OLD_NAME")
+		if !ok || got != 5*time.Second {
+			t.Errorf("got a different value than the wanted one. expected: %s; got: %s (found: %t)", 5*time.Second, got, ok)
+		}
+	})
+	t.Run("all invalid", func(t *testing.T) {
+		setupEnvVars(t, map[string]string{"NEW_NAME": "5x", "OLD_NAME": "abc"})
+		if got, ok := FirstDuration("NEW_NAME", "OLD_NAME"); ok || got != 0 {
+			t.Errorf("expected nothing to be found but got %s (found: %t)", got, ok)
+		}
+	})
+}
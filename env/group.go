@@ -0,0 +1,55 @@
+package env
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ExactlyOne validates that exactly one of the given groups of env vars is fully populated.
+// A group is considered fully populated when all of its keys are set to a non-empty value.
+// This is useful to express mutually-exclusive configuration, like either DATABASE_URL or
+// DB_HOST+DB_PORT.
+func ExactlyOne(groups ...[]string) error {
+	return defaultScope().ExactlyOne(groups...)
+}
+
+// ExactlyOne is the same as the package-level [ExactlyOne] but reads the values from the [Source] of the [Scope].
+func (s *Scope) ExactlyOne(groups ...[]string) error {
+	if len(groups) == 0 {
+		return fmt.Errorf("no env var groups given")
+	}
+	var populated [][]string
+	for _, g := range groups {
+		if s.groupPopulated(g) {
+			populated = append(populated, g)
+		}
+	}
+	switch len(populated) {
+	case 1:
+		return nil
+	case 0:
+		return fmt.Errorf("expected exactly one of the env var groups to be set but none is: %s", formatGroups(groups))
+	default:
+		return fmt.Errorf("expected exactly one of the env var groups to be set but %d are: %s", len(populated), formatGroups(populated))
+	}
+}
+
+func (s *Scope) groupPopulated(keys []string) bool {
+	if len(keys) == 0 {
+		return false
+	}
+	for _, k := range keys {
+		if s.get(k) == "" {
+			return false
+		}
+	}
+	return true
+}
+
+func formatGroups(groups [][]string) string {
+	parts := make([]string, 0, len(groups))
+	for _, g := range groups {
+		parts = append(parts, "["+strings.Join(g, "+")+"]")
+	}
+	return strings.Join(parts, ", ")
+}
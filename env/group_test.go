@@ -0,0 +1,48 @@
+package env
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExactlyOne(t *testing.T) {
+	groups := [][]string{
+		{"DATABASE_URL"},
+		{"DB_HOST", "DB_PORT"},
+	}
+	t.Run("none set", func(t *testing.T) {
+		err := ExactlyOne(groups...)
+		if err == nil {
+			t.Fatalf("expected an error when no group is set")
+		}
+		if want := "[DATABASE_URL], [DB_HOST+DB_PORT]"; !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to contain %q but got %q", want, err.Error())
+		}
+	})
+	t.Run("partially set group is not counted", func(t *testing.T) {
+		setupEnvVars(t, map[string]string{"DB_HOST": "localhost"})
+		if err := ExactlyOne(groups...); err == nil {
+			t.Fatalf("expected an error when a group is only partially set")
+		}
+	})
+	t.Run("one set", func(t *testing.T) {
+		setupEnvVars(t, map[string]string{"DB_HOST": "localhost", "DB_PORT": "5432"})
+		if err := ExactlyOne(groups...); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+	})
+	t.Run("multiple set", func(t *testing.T) {
+		setupEnvVars(t, map[string]string{
+			"DATABASE_URL": "postgres://localhost:5432",
+			"DB_HOST":      "localhost",
+			"DB_PORT":      "5432",
+		})
+		err := ExactlyOne(groups...)
+		if err == nil {
+			t.Fatalf("expected an error when multiple groups are set")
+		}
+		if want := "but 2 are"; !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to contain %q but got %q", want, err.Error())
+		}
+	})
+}
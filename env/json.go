@@ -0,0 +1,50 @@
+package env
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrNotSet is returned when the requested env var is not set or is empty.
+var ErrNotSet = errors.New("env var not set")
+
+// JSON reads the env var and decodes its value into the given target.
+// When the env var is not set, an error wrapping [ErrNotSet] is returned, allowing the caller to decide
+// if the env var is optional or not.
+// Any content after the JSON document is considered invalid.
+func JSON(k string, out any) error {
+	return defaultScope().JSON(k, out)
+}
+
+// MustJSON is the same as [JSON] but panics on error.
+func MustJSON(k string, out any) {
+	defaultScope().MustJSON(k, out)
+}
+
+func (s *Scope) JSON(k string, out any) error {
+	v := s.get(k)
+	if v == "" {
+		return fmt.Errorf("%w: %s", ErrNotSet, k)
+	}
+	dec := json.NewDecoder(strings.NewReader(v))
+	if err := dec.Decode(out); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return fmt.Errorf("env var %s contains invalid JSON at position %d: %w", k, syntaxErr.Offset, err)
+		}
+		return fmt.Errorf("env var %s could not be decoded from JSON: %w", k, err)
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return fmt.Errorf("env var %s contains unexpected data after the JSON document at position %d", k, dec.InputOffset())
+	}
+	return nil
+}
+
+func (s *Scope) MustJSON(k string, out any) {
+	if err := s.JSON(k, out); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,77 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestJSON(t *testing.T) {
+	type features struct {
+		A bool `json:"a"`
+		B bool `json:"b"`
+	}
+	t.Run("valid json", func(t *testing.T) {
+		setupEnvVars(t, map[string]string{"FEATURES_JSON": `{"a":true,"b":false}`})
+		var got features
+		if err := JSON("FEATURES_JSON", &got); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		if want := (features{A: true}); got != want {
+			t.Errorf("got a different value than the wanted one. expected: %+v; got: %+v", want, got)
+		}
+	})
+	t.Run("env var not set", func(t *testing.T) {
+		var got features
+		err := JSON("FEATURES_JSON", &got)
+		if !errors.Is(err, ErrNotSet) {
+			t.Fatalf("expected error to be ErrNotSet but got: %v", err)
+		}
+	})
+	t.Run("invalid json", func(t *testing.T) {
+		setupEnvVars(t, map[string]string{"FEATURES_JSON": `{"a":tru}`})
+		var got features
+		err := JSON("FEATURES_JSON", &got)
+		if err == nil {
+			t.Fatalf("expected an error but got nothing")
+		}
+		for _, want := range []string{"FEATURES_JSON", "position 9"} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("expected error to contain %q but got %q", want, err.Error())
+			}
+		}
+	})
+	t.Run("trailing garbage", func(t *testing.T) {
+		setupEnvVars(t, map[string]string{"FEATURES_JSON": `{"a":true} {"b":true}`})
+		var got features
+		err := JSON("FEATURES_JSON", &got)
+		if err == nil {
+			t.Fatalf("expected an error but got nothing")
+		}
+		if want := "unexpected data after the JSON document"; !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to contain %q but got %q", want, err.Error())
+		}
+	})
+	t.Run("trailing whitespace is accepted", func(t *testing.T) {
+		setupEnvVars(t, map[string]string{"FEATURES_JSON": "{\"a\":true}  \n"})
+		var got features
+		if err := JSON("FEATURES_JSON", &got); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+	})
+}
+
+func TestMustJSON(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatalf("expected MustJSON to panic")
+		}
+		if got := fmt.Sprintf("%s", r); !strings.Contains(got, "FEATURES_JSON") {
+			t.Errorf("expected panic to contain the key but got %q", got)
+		}
+	}()
+	var out map[string]bool
+	MustJSON("FEATURES_JSON", &out)
+}
@@ -0,0 +1,34 @@
+package env
+
+import (
+	"time"
+)
+
+// LocationWithDefault reads the env var as a time zone name (ie: Europe/Bucharest) and loads it with
+// [time.LoadLocation]. The literal values "UTC" and "Local" are also accepted.
+// When the env var is not set or the zone is unknown, the default is returned.
+func LocationWithDefault(k string, def *time.Location) *time.Location {
+	return defaultScope().LocationWithDefault(k, def)
+}
+
+// Location is the same as [LocationWithDefault] but defaults to [time.UTC].
+func Location(k string) *time.Location {
+	return defaultScope().Location(k)
+}
+
+func (s *Scope) LocationWithDefault(k string, def *time.Location) *time.Location {
+	v := s.get(k)
+	if v == "" {
+		return def
+	}
+	loc, err := time.LoadLocation(v)
+	if err != nil {
+		warn(k, v, "env var not a known time zone", "error", err)
+		return def
+	}
+	return loc
+}
+
+func (s *Scope) Location(k string) *time.Location {
+	return s.LocationWithDefault(k, time.UTC)
+}
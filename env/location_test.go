@@ -0,0 +1,42 @@
+package env
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLocation(t *testing.T) {
+	t.Run("location with no default - env var not found", func(t *testing.T) {
+		if got, want := Location("envvar"), time.UTC; got != want {
+			t.Errorf("got a different value than the wanted one. expected: %s; got: %s", want, got)
+		}
+	})
+	t.Run("location UTC", func(t *testing.T) {
+		setupEnvVars(t, map[string]string{"envvar": "UTC"})
+		if got, want := LocationWithDefault("envvar", time.Local), time.UTC; got != want {
+			t.Errorf("got a different value than the wanted one. expected: %s; got: %s", want, got)
+		}
+	})
+	t.Run("location Local", func(t *testing.T) {
+		setupEnvVars(t, map[string]string{"envvar": "Local"})
+		if got, want := Location("envvar"), time.Local; got != want {
+			t.Errorf("got a different value than the wanted one. expected: %s; got: %s", want, got)
+		}
+	})
+	t.Run("location with default - unknown zone", func(t *testing.T) {
+		setupEnvVars(t, map[string]string{"envvar": "Not/AZone"})
+		if got, want := LocationWithDefault("envvar", time.Local), time.Local; got != want {
+			t.Errorf("got a different value than the wanted one. expected: %s; got: %s", want, got)
+		}
+	})
+	t.Run("location with named zone", func(t *testing.T) {
+		const zone = "Europe/Bucharest"
+		if _, err := time.LoadLocation(zone); err != nil {
+			t.Skipf("tzdata for %s not available on this host: %s", zone, err)
+		}
+		setupEnvVars(t, map[string]string{"envvar": zone})
+		if got, want := Location("envvar").String(), zone; got != want {
+			t.Errorf("got a different value than the wanted one. expected: %s; got: %s", want, got)
+		}
+	})
+}
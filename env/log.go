@@ -0,0 +1,48 @@
+package env
+
+import (
+	"log/slog"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	logger atomic.Pointer[slog.Logger]
+	// warned holds the keys for which a warning was already logged.
+	warned sync.Map
+)
+
+// SetLogger configures the logger used to report the env vars that cannot be parsed.
+// Giving nil restores the default behavior of using [slog.Default].
+func SetLogger(l *slog.Logger) {
+	logger.Store(l)
+}
+
+// ResetWarnings forgets the keys for which a warning was already logged.
+// This is mainly useful in tests.
+func ResetWarnings() {
+	warned.Clear()
+}
+
+func getLogger() *slog.Logger {
+	if l := logger.Load(); l != nil {
+		return l
+	}
+	return slog.Default()
+}
+
+// warn logs a warning about the given key only the first time is called for that key.
+// Afterward, the same message is logged at debug level to avoid flooding the logs when the env
+// var is read in a hot path.
+// The raw value is included in the log so this must not be used for secrets.
+func warn(k, v string, msg string, attrs ...any) {
+	l := getLogger().
+		With("key", k).
+		With("value", v).
+		With(attrs...)
+	if _, loaded := warned.LoadOrStore(k, struct{}{}); loaded {
+		l.Debug(msg)
+		return
+	}
+	l.Warn(msg)
+}
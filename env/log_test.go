@@ -0,0 +1,63 @@
+package env
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestWarnOnce(t *testing.T) {
+	setupLogger := func(t *testing.T) *bytes.Buffer {
+		var b bytes.Buffer
+		SetLogger(slog.New(slog.NewTextHandler(&b, &slog.HandlerOptions{Level: slog.LevelDebug})))
+		ResetWarnings()
+		t.Cleanup(func() {
+			SetLogger(nil)
+			ResetWarnings()
+		})
+		return &b
+	}
+	t.Run("warns once per key", func(t *testing.T) {
+		b := setupLogger(t)
+		setupEnvVars(t, map[string]string{"envvar": "121a"})
+		for range 5 {
+			IntWithDefault("envvar", 1)
+		}
+		logs := b.String()
+		if got, want := strings.Count(logs, "level=WARN"), 1; got != want {
+			t.Errorf("expected %d warning but got %d:\n%s", want, got, logs)
+		}
+		if got, want := strings.Count(logs, "level=DEBUG"), 4; got != want {
+			t.Errorf("expected %d debug logs but got %d:\n%s", want, got, logs)
+		}
+		if want := "value=121a"; !strings.Contains(logs, want) {
+			t.Errorf("expected logs to contain the raw value %q but got:\n%s", want, logs)
+		}
+	})
+	t.Run("reset warnings", func(t *testing.T) {
+		b := setupLogger(t)
+		setupEnvVars(t, map[string]string{"envvar": "notabool"})
+		Bool("envvar")
+		ResetWarnings()
+		Bool("envvar")
+		if got, want := strings.Count(b.String(), "level=WARN"), 2; got != want {
+			t.Errorf("expected %d warnings but got %d:\n%s", want, got, b.String())
+		}
+	})
+	t.Run("concurrent reads warn once", func(t *testing.T) {
+		b := setupLogger(t)
+		setupEnvVars(t, map[string]string{"envvar": "1,x"})
+		var wg sync.WaitGroup
+		for range 20 {
+			wg.Go(func() {
+				IntSlice("envvar")
+			})
+		}
+		wg.Wait()
+		if got, want := strings.Count(b.String(), "level=WARN"), 1; got != want {
+			t.Errorf("expected %d warning but got %d:\n%s", want, got, b.String())
+		}
+	})
+}
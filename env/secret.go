@@ -0,0 +1,61 @@
+package env
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"log/slog"
+)
+
+const redacted = "[REDACTED]"
+
+// SecretString holds a sensitive value read from an env var.
+// All the formatting methods are returning "[REDACTED]" so that logging or printing a config
+// struct does not expose the credentials. The real value is returned only by [SecretString.Reveal].
+type SecretString string
+
+var (
+	_ slog.LogValuer = SecretString("")
+	_ json.Marshaler = SecretString("")
+)
+
+// Reveal returns the real value of the secret.
+func (s SecretString) Reveal() string {
+	return string(s)
+}
+
+// Equal compares the secret with the given value in constant time.
+func (s SecretString) Equal(other string) bool {
+	return subtle.ConstantTimeCompare([]byte(s), []byte(other)) == 1
+}
+
+func (s SecretString) String() string {
+	return redacted
+}
+
+func (s SecretString) GoString() string {
+	return redacted
+}
+
+func (s SecretString) MarshalJSON() ([]byte, error) {
+	return json.Marshal(redacted)
+}
+
+func (s SecretString) LogValue() slog.Value {
+	return slog.StringValue(redacted)
+}
+
+func SecretWithDefault(k string, def string) SecretString {
+	return defaultScope().SecretWithDefault(k, def)
+}
+
+func Secret(k string) SecretString {
+	return defaultScope().Secret(k)
+}
+
+func (s *Scope) SecretWithDefault(k string, def string) SecretString {
+	return SecretString(s.StringWithDefault(k, def))
+}
+
+func (s *Scope) Secret(k string) SecretString {
+	return s.SecretWithDefault(k, "")
+}
@@ -0,0 +1,64 @@
+package env
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSecret(t *testing.T) {
+	const password = "sup3r-s3cr3t"
+	t.Run("reveal returns the real value", func(t *testing.T) {
+		setupEnvVars(t, map[string]string{"envvar": password})
+		if got, want := Secret("envvar").Reveal(), password; got != want {
+			t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+		}
+	})
+	t.Run("secret with default - env var not found", func(t *testing.T) {
+		if got, want := SecretWithDefault("envvar", "def").Reveal(), "def"; got != want {
+			t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+		}
+	})
+	t.Run("formatting is redacted", func(t *testing.T) {
+		setupEnvVars(t, map[string]string{"envvar": password})
+		cfg := struct {
+			User     string
+			Password SecretString
+		}{User: "admin", Password: Secret("envvar")}
+
+		var logs bytes.Buffer
+		slog.New(slog.NewJSONHandler(&logs, nil)).Info("config", "cfg", cfg, "password", cfg.Password)
+		js, err := json.Marshal(cfg)
+		if err != nil {
+			t.Fatalf("failed to marshal the config: %s", err)
+		}
+		outputs := map[string]string{
+			"%s":   fmt.Sprintf("%s", cfg.Password),
+			"%v":   fmt.Sprintf("%v", cfg),
+			"%+v":  fmt.Sprintf("%+v", cfg),
+			"%#v":  fmt.Sprintf("%#v", cfg),
+			"json": string(js),
+			"slog": logs.String(),
+		}
+		for name, out := range outputs {
+			if strings.Contains(out, password) {
+				t.Errorf("%s output leaked the secret: %s", name, out)
+			}
+			if !strings.Contains(out, redacted) {
+				t.Errorf("%s output expected to contain %q but got: %s", name, redacted, out)
+			}
+		}
+	})
+	t.Run("equal", func(t *testing.T) {
+		s := SecretString(password)
+		if !s.Equal(password) {
+			t.Errorf("expected the secret to be equal to the same value")
+		}
+		if s.Equal("other") {
+			t.Errorf("expected the secret to not be equal to a different value")
+		}
+	})
+}
@@ -0,0 +1,68 @@
+package env
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+func IntSliceWithDefault(k string, def []int) []int {
+	return defaultScope().IntSliceWithDefault(k, def)
+}
+
+func IntSlice(k string) []int {
+	return defaultScope().IntSlice(k)
+}
+
+func DurationSliceWithDefault(k string, def []time.Duration) []time.Duration {
+	return defaultScope().DurationSliceWithDefault(k, def)
+}
+
+func DurationSlice(k string) []time.Duration {
+	return defaultScope().DurationSlice(k)
+}
+
+func (s *Scope) IntSliceWithDefault(k string, def []int) []int {
+	return sliceWithDefault(s, k, def, strconv.Atoi)
+}
+
+func (s *Scope) IntSlice(k string) []int {
+	return s.IntSliceWithDefault(k, nil)
+}
+
+func (s *Scope) DurationSliceWithDefault(k string, def []time.Duration) []time.Duration {
+	return sliceWithDefault(s, k, def, time.ParseDuration)
+}
+
+func (s *Scope) DurationSlice(k string) []time.Duration {
+	return s.DurationSliceWithDefault(k, nil)
+}
+
+// sliceWithDefault splits the value of the env var on comma and parses each element with the given parse function.
+// The elements that cannot be parsed are skipped and their indexes are reported in a warning. When none of the
+// elements can be parsed, the default is returned instead of an empty slice.
+func sliceWithDefault[T any](s *Scope, k string, def []T, parse func(string) (T, error)) []T {
+	v := s.get(k)
+	if v == "" {
+		return def
+	}
+	parts := strings.Split(v, ",")
+	res := make([]T, 0, len(parts))
+	var invalid []int
+	for i, p := range parts {
+		val, err := parse(strings.TrimSpace(p))
+		if err != nil {
+			invalid = append(invalid, i)
+			continue
+		}
+		res = append(res, val)
+	}
+	if len(res) == 0 {
+		warn(k, v, "env var contains no valid element", "invalid.indexes", invalid)
+		return def
+	}
+	if len(invalid) > 0 {
+		warn(k, v, "env var contains invalid elements, skipping them", "invalid.indexes", invalid)
+	}
+	return res
+}
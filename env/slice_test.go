@@ -0,0 +1,63 @@
+package env
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestIntSlice(t *testing.T) {
+	t.Run("int slice with no default", func(t *testing.T) {
+		setupEnvVars(t, map[string]string{"envvar": "0, 1,2 ,3"})
+		if got, want := IntSlice("envvar"), []int{0, 1, 2, 3}; !slices.Equal(got, want) {
+			t.Errorf("got a different value than the wanted one. expected: %v; got: %v", want, got)
+		}
+	})
+	t.Run("int slice with default - env var not found", func(t *testing.T) {
+		if got, want := IntSliceWithDefault("envvar", []int{5}), []int{5}; !slices.Equal(got, want) {
+			t.Errorf("got a different value than the wanted one. expected: %v; got: %v", want, got)
+		}
+	})
+	t.Run("int slice with default - invalid elements skipped", func(t *testing.T) {
+		setupEnvVars(t, map[string]string{"envvar": "3,a,1,,2"})
+		if got, want := IntSliceWithDefault("envvar", []int{5}), []int{3, 1, 2}; !slices.Equal(got, want) {
+			t.Errorf("got a different value than the wanted one. expected: %v; got: %v", want, got)
+		}
+	})
+	t.Run("int slice with default - all elements invalid", func(t *testing.T) {
+		setupEnvVars(t, map[string]string{"envvar": "a,b"})
+		if got, want := IntSliceWithDefault("envvar", []int{5}), []int{5}; !slices.Equal(got, want) {
+			t.Errorf("got a different value than the wanted one. expected: %v; got: %v", want, got)
+		}
+	})
+}
+
+func TestDurationSlice(t *testing.T) {
+	t.Run("duration slice with no default", func(t *testing.T) {
+		setupEnvVars(t, map[string]string{"envvar": "1s, 2s,5s,10s"})
+		want := []time.Duration{time.Second, 2 * time.Second, 5 * time.Second, 10 * time.Second}
+		if got := DurationSlice("envvar"); !slices.Equal(got, want) {
+			t.Errorf("got a different value than the wanted one. expected: %v; got: %v", want, got)
+		}
+	})
+	t.Run("duration slice with default - env var not found", func(t *testing.T) {
+		want := []time.Duration{time.Minute}
+		if got := DurationSliceWithDefault("envvar", want); !slices.Equal(got, want) {
+			t.Errorf("got a different value than the wanted one. expected: %v; got: %v", want, got)
+		}
+	})
+	t.Run("duration slice with default - invalid elements skipped", func(t *testing.T) {
+		setupEnvVars(t, map[string]string{"envvar": "1s,1x,3ms"})
+		want := []time.Duration{time.Second, 3 * time.Millisecond}
+		if got := DurationSliceWithDefault("envvar", nil); !slices.Equal(got, want) {
+			t.Errorf("got a different value than the wanted one. expected: %v; got: %v", want, got)
+		}
+	})
+	t.Run("duration slice with default - all elements invalid", func(t *testing.T) {
+		setupEnvVars(t, map[string]string{"envvar": "1x,2y"})
+		want := []time.Duration{time.Minute}
+		if got := DurationSliceWithDefault("envvar", want); !slices.Equal(got, want) {
+			t.Errorf("got a different value than the wanted one. expected: %v; got: %v", want, got)
+		}
+	})
+}
@@ -0,0 +1,25 @@
+package env
+
+import (
+	"os"
+	"strings"
+)
+
+// Snapshot captures the current environment of the process and returns a function that restores it:
+// the env vars set in the meantime are removed and the changed or removed ones get back their values.
+// This is meant for tests in which [testing.T.Setenv] is not available (ie: TestMain or helpers without a T).
+//
+//	restore := env.Snapshot()
+//	defer restore()
+//
+// Like os.Setenv, this is not safe to use in parallel tests.
+func Snapshot() func() {
+	saved := os.Environ()
+	return func() {
+		os.Clearenv()
+		for _, kv := range saved {
+			k, v, _ := strings.Cut(kv, "=")
+			_ = os.Setenv(k, v)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package env
+
+import (
+	"os"
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+	setupEnvVars(t, map[string]string{
+		"SNAPSHOT_CHANGED": "original",
+		"SNAPSHOT_REMOVED": "removed",
+		"SNAPSHOT_EMPTY":   "",
+	})
+	restore := Snapshot()
+	_ = os.Setenv("SNAPSHOT_CHANGED", "changed")
+	_ = os.Unsetenv("SNAPSHOT_REMOVED")
+	_ = os.Unsetenv("SNAPSHOT_EMPTY")
+	_ = os.Setenv("SNAPSHOT_ADDED", "added")
+	restore()
+
+	for k, want := range map[string]string{
+		"SNAPSHOT_CHANGED": "original",
+		"SNAPSHOT_REMOVED": "removed",
+		"SNAPSHOT_EMPTY":   "",
+	} {
+		got, ok := os.LookupEnv(k)
+		if !ok {
+			t.Errorf("expected %s to be restored but it's missing", k)
+			continue
+		}
+		if got != want {
+			t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+		}
+	}
+	if v, ok := os.LookupEnv("SNAPSHOT_ADDED"); ok {
+		t.Errorf("expected SNAPSHOT_ADDED to be removed but got %q", v)
+	}
+}
@@ -0,0 +1,90 @@
+package env
+
+import (
+	"os"
+	"sync"
+)
+
+// Source is the contract for any construct that can provide values for env vars.
+// [Source.Lookup] returns the value of the given key and a bool indicating if the key
+// was found or not.
+type Source interface {
+	Lookup(key string) (string, bool)
+}
+
+// OSSource is the [Source] backed by the environment of the process.
+// This is the default source used by all the package-level functions.
+type OSSource struct{}
+
+func (OSSource) Lookup(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+// MapSource is a [Source] backed by a map.
+// This is mainly useful in tests to avoid changing the environment of the process.
+type MapSource map[string]string
+
+func (m MapSource) Lookup(key string) (string, bool) {
+	v, ok := m[key]
+	return v, ok
+}
+
+// Chain composes multiple sources into one.
+// On lookup, the sources are queried in the given order and the first one that contains the key wins.
+func Chain(sources ...Source) Source {
+	return chain(sources)
+}
+
+type chain []Source
+
+func (c chain) Lookup(key string) (string, bool) {
+	for _, s := range c {
+		if v, ok := s.Lookup(key); ok {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+var (
+	defaultSource  Source = OSSource{}
+	defaultSourceM sync.RWMutex
+)
+
+// SetSource replaces the [Source] used by all the package-level functions.
+// Giving nil restores the default [OSSource].
+func SetSource(s Source) {
+	if s == nil {
+		s = OSSource{}
+	}
+	defaultSourceM.Lock()
+	defer defaultSourceM.Unlock()
+	defaultSource = s
+}
+
+// Scope exposes the same getters as the package-level functions but reads the values from
+// its own [Source] instead of the default one.
+type Scope struct {
+	source Source
+}
+
+// WithSource returns a new [Scope] that reads the values from the given [Source].
+func WithSource(s Source) *Scope {
+	if s == nil {
+		s = OSSource{}
+	}
+	return &Scope{source: s}
+}
+
+// defaultScope returns a [Scope] that is using the currently configured default [Source].
+func defaultScope() *Scope {
+	defaultSourceM.RLock()
+	defer defaultSourceM.RUnlock()
+	return &Scope{source: defaultSource}
+}
+
+// get returns the value of the given key or an empty string when the key is missing.
+func (s *Scope) get(k string) string {
+	v, _ := s.source.Lookup(k)
+	return v
+}
@@ -0,0 +1,60 @@
+package env
+
+import (
+	"testing"
+)
+
+func TestWithSource(t *testing.T) {
+	t.Run("reads from the given source", func(t *testing.T) {
+		s := WithSource(MapSource{"envvar": "myval", "intvar": "12", "boolvar": "true"})
+		if got, want := s.String("envvar"), "myval"; got != want {
+			t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+		}
+		if got, want := s.Int("intvar"), 12; got != want {
+			t.Errorf("got a different value than the wanted one. expected: %d; got: %d", want, got)
+		}
+		if got, want := s.Bool("boolvar"), true; got != want {
+			t.Errorf("got a different value than the wanted one. expected: %t; got: %t", want, got)
+		}
+	})
+	t.Run("does not read from the os env", func(t *testing.T) {
+		setupEnvVars(t, map[string]string{"envvar": "from os"})
+		s := WithSource(MapSource{})
+		if got, want := s.StringWithDefault("envvar", "def"), "def"; got != want {
+			t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+		}
+	})
+	t.Run("expand uses the given source", func(t *testing.T) {
+		s := WithSource(MapSource{"env1": "env1 val"})
+		if got, want := s.Expand("contains ${env1}"), "contains env1 val"; got != want {
+			t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+		}
+	})
+}
+
+func TestChain(t *testing.T) {
+	setupEnvVars(t, map[string]string{"envvar": "from os", "osonly": "os val"})
+	s := WithSource(Chain(MapSource{"envvar": "from map"}, OSSource{}))
+	if got, want := s.String("envvar"), "from map"; got != want {
+		t.Errorf("expected first source to win. expected: %q; got: %q", want, got)
+	}
+	if got, want := s.String("osonly"), "os val"; got != want {
+		t.Errorf("expected fallback on the second source. expected: %q; got: %q", want, got)
+	}
+	if got, want := s.StringWithDefault("missing", "def"), "def"; got != want {
+		t.Errorf("expected default for a key missing from all sources. expected: %q; got: %q", want, got)
+	}
+}
+
+func TestSetSource(t *testing.T) {
+	t.Cleanup(func() { SetSource(nil) })
+	SetSource(MapSource{"envvar": "from map"})
+	if got, want := String("envvar"), "from map"; got != want {
+		t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+	}
+	SetSource(nil)
+	setupEnvVars(t, map[string]string{"envvar": "from os"})
+	if got, want := String("envvar"), "from os"; got != want {
+		t.Errorf("expected the os source to be restored. expected: %q; got: %q", want, got)
+	}
+}
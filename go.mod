@@ -5,6 +5,11 @@ go 1.25.5
 require (
 	github.com/go-chi/chi/v5 v5.2.4
 	github.com/go-chi/httplog/v3 v3.3.0
+	go.opentelemetry.io/otel/trace v1.46.0
 )
 
-require github.com/google/uuid v1.6.0 // indirect
+require (
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	go.opentelemetry.io/otel v1.46.0 // indirect
+)
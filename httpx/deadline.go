@@ -0,0 +1,44 @@
+package httpx
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// defaultDeadlineMargin is the margin used by [DeadlineWarningMiddleware].
+const defaultDeadlineMargin = 100 * time.Millisecond
+
+// DeadlineWarningMiddleware is a middleware that logs a warning when a handler finishes within
+// a small margin of the deadline of its request context.
+// This is giving an early warning about the endpoints that are at risk of timing out.
+// Requests whose context has no deadline are ignored.
+func DeadlineWarningMiddleware(next http.Handler) http.Handler {
+	return DeadlineWarningMiddlewareWithMargin(next, defaultDeadlineMargin)
+}
+
+// DeadlineWarningMiddlewareWithMargin is the same as [DeadlineWarningMiddleware] but allows the user to
+// configure the margin before the deadline in which the warning is logged.
+func DeadlineWarningMiddlewareWithMargin(next http.Handler, margin time.Duration) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		deadline, ok := r.Context().Deadline()
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		start := time.Now()
+		next.ServeHTTP(w, r)
+		end := time.Now()
+		remaining := deadline.Sub(end)
+		if remaining > margin {
+			return
+		}
+		slog.
+			With(requestAttrs(r)).
+			With("duration", end.Sub(start)).
+			With("deadline.remaining", remaining).
+			With("deadline.margin", margin).
+			Warn("request finished close to its deadline")
+	}
+	return http.HandlerFunc(fn)
+}
@@ -0,0 +1,64 @@
+package httpx
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDeadlineWarningMiddleware(t *testing.T) {
+	t.Run("warns when finishing close to the deadline", func(t *testing.T) {
+		b := captureLogs(t)
+		h := DeadlineWarningMiddlewareWithMargin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-time.After(60 * time.Millisecond)
+		}), 50*time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		req := httptest.NewRequest(http.MethodGet, "/slow", nil).WithContext(ctx)
+		h.ServeHTTP(httptest.NewRecorder(), req)
+
+		if want, got := "request finished close to its deadline", b.String(); !strings.Contains(got, want) {
+			t.Errorf("expected logs to contain %q but got:\n%s", want, got)
+		}
+	})
+	t.Run("does not warn when finishing well before the deadline", func(t *testing.T) {
+		b := captureLogs(t)
+		h := DeadlineWarningMiddlewareWithMargin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}), 50*time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		req := httptest.NewRequest(http.MethodGet, "/fast", nil).WithContext(ctx)
+		h.ServeHTTP(httptest.NewRecorder(), req)
+
+		if got := b.String(); got != "" {
+			t.Errorf("expected no logs but got:\n%s", got)
+		}
+	})
+	t.Run("does nothing when there is no deadline", func(t *testing.T) {
+		b := captureLogs(t)
+		h := DeadlineWarningMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if got := b.String(); got != "" {
+			t.Errorf("expected no logs but got:\n%s", got)
+		}
+	})
+}
+
+// captureLogs is replacing the default slog logger with one writing in the returned buffer.
+// The original default logger is restored at the end of the test.
+func captureLogs(t *testing.T) *bytes.Buffer {
+	var b bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&b, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	t.Cleanup(func() {
+		slog.SetDefault(prev)
+	})
+	return &b
+}
@@ -0,0 +1,34 @@
+package httpx
+
+import (
+	"net/http"
+
+	"github.com/yottta/go-core/env"
+)
+
+// ServedByHeader is the name of the header set by [InstanceHeaderMiddleware].
+const ServedByHeader = "X-Served-By"
+
+// InstanceHeaderMiddleware returns a middleware that sets the X-Served-By header on every response, identifying
+// the replica that served it. This is useful for debugging inconsistent behavior (ie: caching) across a fleet.
+// The value of the header is the given name, or the HOSTNAME env var when the name is empty. When the SERVICE_VERSION
+// env var is set, the version is appended to it (ie: "pod-1; version=1.2.3").
+// The env vars are read once, when the middleware is created.
+func InstanceHeaderMiddleware(name string) func(http.Handler) http.Handler {
+	if name == "" {
+		name = env.String("HOSTNAME")
+	}
+	value := name
+	if version := env.String("SERVICE_VERSION"); version != "" {
+		value += "; version=" + version
+	}
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			if value != "" {
+				w.Header().Set(ServedByHeader, value)
+			}
+			next.ServeHTTP(w, r)
+		}
+		return http.HandlerFunc(fn)
+	}
+}
@@ -0,0 +1,64 @@
+package httpx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestInstanceHeaderMiddleware(t *testing.T) {
+	cases := []struct {
+		name     string
+		instance string
+		envs     map[string]string
+		want     string
+	}{
+		{
+			name:     "configured name",
+			instance: "pod-1",
+			envs:     map[string]string{"HOSTNAME": "host-1"},
+			want:     "pod-1",
+		},
+		{
+			name: "name from HOSTNAME",
+			envs: map[string]string{"HOSTNAME": "host-1"},
+			want: "host-1",
+		},
+		{
+			name:     "with version",
+			instance: "pod-1",
+			envs:     map[string]string{"SERVICE_VERSION": "1.2.3"},
+			want:     "pod-1; version=1.2.3",
+		},
+		{
+			name: "nothing configured",
+			envs: map[string]string{"HOSTNAME": ""},
+			want: "",
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOSTNAME", "")
+			t.Setenv("SERVICE_VERSION", "")
+			for k, v := range tt.envs {
+				t.Setenv(k, v)
+			}
+			h := InstanceHeaderMiddleware(tt.instance)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			got, ok := rec.Result().Header[ServedByHeader]
+			if tt.want == "" {
+				if ok {
+					t.Errorf("expected no %s header but got %q", ServedByHeader, got)
+				}
+				return
+			}
+			if len(got) != 1 || got[0] != tt.want {
+				t.Errorf("got a different value than the wanted one. expected: %q; got: %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package httpx
+
+import (
+	"net/http"
+	"net/netip"
+)
+
+// IPFilterMiddleware returns a middleware that allows or blocks the requests based on the client IP.
+// The denied requests are answered with http.StatusForbidden.
+// The deny list takes precedence over the allow list and an empty allow list means that all IPs are allowed.
+//
+// The client IP is read from [http.Request.RemoteAddr], so when the server is running behind trusted proxies,
+// a middleware that resolves the real IP (ie: chi's middleware.RealIP) should run before this one.
+// The lists can be read from env vars with env.CIDRs.
+func IPFilterMiddleware(allow, deny []netip.Prefix) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			ip, ok := clientIP(r)
+			if !ok || !ipAllowed(ip, allow, deny) {
+				http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// clientIP parses the [http.Request.RemoteAddr] that can be either in the "ip:port" format or just an IP.
+func clientIP(r *http.Request) (netip.Addr, bool) {
+	if ap, err := netip.ParseAddrPort(r.RemoteAddr); err == nil {
+		return ap.Addr().Unmap(), true
+	}
+	if ip, err := netip.ParseAddr(r.RemoteAddr); err == nil {
+		return ip.Unmap(), true
+	}
+	return netip.Addr{}, false
+}
+
+func ipAllowed(ip netip.Addr, allow, deny []netip.Prefix) bool {
+	for _, p := range deny {
+		if p.Contains(ip) {
+			return false
+		}
+	}
+	if len(allow) == 0 {
+		return true
+	}
+	for _, p := range allow {
+		if p.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package httpx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+func TestIPFilterMiddleware(t *testing.T) {
+	prefixes := func(in ...string) []netip.Prefix {
+		var res []netip.Prefix
+		for _, p := range in {
+			res = append(res, netip.MustParsePrefix(p))
+		}
+		return res
+	}
+	cases := map[string]struct {
+		allow, deny []netip.Prefix
+		remoteAddr  string
+		wantStatus  int
+	}{
+		"allowed ip": {
+			allow:      prefixes("10.0.0.0/8"),
+			remoteAddr: "10.1.2.3:5555",
+			wantStatus: http.StatusOK,
+		},
+		"ip not in allow list": {
+			allow:      prefixes("10.0.0.0/8"),
+			remoteAddr: "192.168.1.1:5555",
+			wantStatus: http.StatusForbidden,
+		},
+		"denied ip": {
+			deny:       prefixes("192.168.0.0/16"),
+			remoteAddr: "192.168.1.1:5555",
+			wantStatus: http.StatusForbidden,
+		},
+		"deny takes precedence over allow": {
+			allow:      prefixes("10.0.0.0/8"),
+			deny:       prefixes("10.1.0.0/16"),
+			remoteAddr: "10.1.2.3:5555",
+			wantStatus: http.StatusForbidden,
+		},
+		"empty lists allow all": {
+			remoteAddr: "172.16.0.1:5555",
+			wantStatus: http.StatusOK,
+		},
+		"remote addr without port as set by real ip middlewares": {
+			allow:      prefixes("10.0.0.0/8"),
+			remoteAddr: "10.1.2.3",
+			wantStatus: http.StatusOK,
+		},
+		"ipv4 mapped ipv6 address": {
+			allow:      prefixes("10.0.0.0/8"),
+			remoteAddr: "[::ffff:10.1.2.3]:5555",
+			wantStatus: http.StatusOK,
+		},
+		"unparsable remote addr": {
+			remoteAddr: "not-an-ip",
+			wantStatus: http.StatusForbidden,
+		},
+	}
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			h := IPFilterMiddleware(tt.allow, tt.deny)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			req.RemoteAddr = tt.remoteAddr
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if got := rec.Code; got != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, got)
+			}
+		})
+	}
+}
@@ -4,48 +4,31 @@ import (
 	"log/slog"
 	"net/http"
 	"time"
+
+	"github.com/yottta/go-core/logging"
 )
 
 // SloggingMiddleware is a basic middleware that prints basic information into logs by using [slog].
+// The request and the response are described with the attributes of [logging.HTTPRequestAttrs], including the
+// request id returned by [GetReqID], and [logging.HTTPResponseAttrs], so that all the services log the HTTP activity
+// with the same fields.
 func SloggingMiddleware(next http.Handler) http.Handler {
 	fn := func(w http.ResponseWriter, r *http.Request) {
-		reqAttrs := requestAttributes(r)
+		reqAttrs := requestAttrs(r)
 		start := time.Now()
 		slog.
-			With(reqAttrs...).
-			With("at", start.Format(time.RFC3339Nano)).
+			With(reqAttrs).
 			Debug("request received")
 		rw := NewInterceptor(w)
 		next.ServeHTTP(rw, r)
-		end := time.Now()
-		duration := end.Sub(start)
 		slog.
-			With(responseInfo(rw)...).
-			With("at", end.Format(time.RFC3339Nano)).
-			With("duration", duration).
+			With(reqAttrs).
+			With(logging.HTTPResponseAttrs(rw.StatusCode, rw.Size, time.Since(start))).
 			Debug("request finished")
-
 	}
 	return http.HandlerFunc(fn)
 }
 
-func requestAttributes(r *http.Request) []any {
-	var attrs []any
-	if ra := r.RemoteAddr; len(ra) > 0 {
-		attrs = append(attrs, "remote.addr", ra)
-	}
-	attrs = append(attrs, "headers", r.Header)
-	attrs = append(attrs, "url.full", r.RequestURI)
-	return attrs
-}
-
-func responseInfo(w *ResponseWriterCoder) []any {
-	var attrs []any
-	attrs = append(attrs, "response.size", w.Size)
-	attrs = append(attrs, "response.code", w.StatusCode)
-	return attrs
-}
-
 type ResponseWriterCoder struct {
 	base       http.ResponseWriter
 	Size       int
@@ -74,3 +57,23 @@ func (i *ResponseWriterCoder) WriteHeader(statusCode int) {
 	i.StatusCode = statusCode
 	i.base.WriteHeader(statusCode)
 }
+
+// ContextLoggerMiddleware stores into the request context a logger that has the request id attached, so
+// that the handlers can get it with logging.FromContext and have all the logs correlated.
+// To have a request id, this should be used after [RequestIDMiddleware] or [RequireRequestID].
+func ContextLoggerMiddleware(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		l := logging.FromContext(ctx)
+		if reqID := GetReqID(ctx); reqID != "" {
+			l = l.With(slog.Group(logging.HTTPRequestKey, slog.String(logging.HTTPRequestIDKey, reqID)))
+		}
+		next.ServeHTTP(w, r.WithContext(logging.IntoContext(ctx, l)))
+	}
+	return http.HandlerFunc(fn)
+}
+
+// requestAttrs returns the [logging.HTTPRequestAttrs] of r, with the request id returned by [GetReqID].
+func requestAttrs(r *http.Request) slog.Attr {
+	return logging.HTTPRequestAttrs(r, GetReqID(r.Context()))
+}
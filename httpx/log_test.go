@@ -0,0 +1,58 @@
+package httpx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/yottta/go-core/logging"
+)
+
+func TestContextLoggerMiddleware(t *testing.T) {
+	t.Run("logger carries the request id", func(t *testing.T) {
+		b := captureLogs(t)
+		h := Middlewares{RequestIDMiddleware, ContextLoggerMiddleware}.ApplyOn(func(w http.ResponseWriter, r *http.Request) {
+			logging.FromContext(r.Context()).Info("handler log")
+		})
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(defaultRequestIDHeader, "req-123")
+		h.ServeHTTP(httptest.NewRecorder(), req)
+
+		if want := "request.request_id=req-123"; !strings.Contains(b.String(), want) {
+			t.Errorf("expected logs to contain %q but got:\n%s", want, b.String())
+		}
+	})
+	t.Run("no request id", func(t *testing.T) {
+		b := captureLogs(t)
+		h := ContextLoggerMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			logging.FromContext(r.Context()).Info("handler log")
+		}))
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if got := b.String(); !strings.Contains(got, "handler log") || strings.Contains(got, "request_id") {
+			t.Errorf("expected logs without request id but got:\n%s", got)
+		}
+	})
+}
+
+func TestSloggingMiddleware(t *testing.T) {
+	b := captureLogs(t)
+	h := Middlewares{RequestIDMiddleware, SloggingMiddleware}.ApplyOn(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("created"))
+	})
+	req := httptest.NewRequest(http.MethodPost, "/users?verbose=true", nil)
+	req.Header.Set(defaultRequestIDHeader, "req-123")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	for _, want := range []string{
+		`msg="request received" request.method=POST request.path=/users request.query="verbose=true"`,
+		"request.request_id=req-123",
+		"response.status=201 response.bytes=7 response.duration_ms=",
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("expected logs to contain %q but got:\n%s", want, b.String())
+		}
+	}
+}
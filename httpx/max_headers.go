@@ -0,0 +1,90 @@
+package httpx
+
+import (
+	"log/slog"
+	"net/http"
+	"slices"
+)
+
+// MaxResponseHeadersMiddleware returns a middleware that limits the response headers set by the handlers.
+// Before the first write, or once the handler returns without writing anything, the headers exceeding maxCount header names or maxBytes total size (names and values)
+// are removed and a warning is logged with the removed names. The headers are kept in the alphabetical order
+// of their names, so the trimming is deterministic.
+// A limit lower or equal to 0 is not enforced.
+// This is a defensive measure for proxy-like handlers that might echo an unbounded number of headers.
+func MaxResponseHeadersMiddleware(maxCount int, maxBytes int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			lw := &headersLimitWriter{
+				ResponseWriter: w,
+				maxCount:       maxCount,
+				maxBytes:       maxBytes,
+				r:              r,
+			}
+			next.ServeHTTP(lw, r)
+			// the handler returned without writing anything, so the headers are sent by the server afterwards
+			lw.enforce()
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+type headersLimitWriter struct {
+	http.ResponseWriter
+	maxCount, maxBytes int
+	r                  *http.Request
+	enforced           bool
+}
+
+func (w *headersLimitWriter) WriteHeader(statusCode int) {
+	w.enforce()
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *headersLimitWriter) Write(bb []byte) (int, error) {
+	w.enforce()
+	return w.ResponseWriter.Write(bb)
+}
+
+// Unwrap allows [http.ResponseController] to reach the original [http.ResponseWriter].
+func (w *headersLimitWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *headersLimitWriter) enforce() {
+	if w.enforced {
+		return
+	}
+	w.enforced = true
+	h := w.ResponseWriter.Header()
+	keys := slices.Sorted(func(yield func(string) bool) {
+		for k := range h {
+			if !yield(k) {
+				return
+			}
+		}
+	})
+	var (
+		count, size int
+		removed     []string
+	)
+	for _, k := range keys {
+		kSize := len(k)
+		for _, v := range h[k] {
+			kSize += len(v)
+		}
+		if (w.maxCount > 0 && count+1 > w.maxCount) || (w.maxBytes > 0 && size+kSize > w.maxBytes) {
+			removed = append(removed, k)
+			h.Del(k)
+			continue
+		}
+		count++
+		size += kSize
+	}
+	if len(removed) > 0 {
+		slog.
+			With(requestAttrs(w.r)).
+			With("headers.removed", removed).
+			Warn("response headers over the limit were removed")
+	}
+}
@@ -0,0 +1,98 @@
+package httpx
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMaxResponseHeadersMiddleware(t *testing.T) {
+	t.Run("count limit", func(t *testing.T) {
+		b := captureLogs(t)
+		h := MaxResponseHeadersMiddleware(3, 0)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for i := range 10 {
+				w.Header().Set(fmt.Sprintf("X-Echo-%d", i), "value")
+			}
+			_, _ = w.Write([]byte("ok"))
+		}))
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		var echoed int
+		for k := range rec.Result().Header {
+			if strings.HasPrefix(k, "X-Echo-") {
+				echoed++
+			}
+		}
+		if got, want := echoed, 3; got != want {
+			t.Errorf("expected %d headers but got %d: %v", want, got, rec.Result().Header)
+		}
+		for _, k := range []string{"X-Echo-0", "X-Echo-1", "X-Echo-2"} {
+			if rec.Result().Header.Get(k) == "" {
+				t.Errorf("expected header %s to be kept", k)
+			}
+		}
+		if want := "response headers over the limit were removed"; !strings.Contains(b.String(), want) {
+			t.Errorf("expected logs to contain %q but got:\n%s", want, b.String())
+		}
+	})
+	t.Run("bytes limit", func(t *testing.T) {
+		captureLogs(t)
+		h := MaxResponseHeadersMiddleware(0, 20)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("A", "123456789")
+			w.Header().Set("B", strings.Repeat("x", 50))
+			w.Header().Set("C", "12345")
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		got := rec.Result().Header
+		if got.Get("A") == "" || got.Get("C") == "" || got.Get("B") != "" {
+			t.Errorf("expected only the headers fitting the limit to be kept but got: %v", got)
+		}
+		if rec.Code != http.StatusAccepted {
+			t.Errorf("expected status %d but got %d", http.StatusAccepted, rec.Code)
+		}
+	})
+	t.Run("handler that writes nothing", func(t *testing.T) {
+		b := captureLogs(t)
+		h := MaxResponseHeadersMiddleware(2, 0)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for i := range 5 {
+				w.Header().Set(fmt.Sprintf("X-Echo-%d", i), "value")
+			}
+		}))
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		var echoed int
+		for k := range rec.Result().Header {
+			if strings.HasPrefix(k, "X-Echo-") {
+				echoed++
+			}
+		}
+		if got, want := echoed, 2; got != want {
+			t.Errorf("expected %d headers but got %d: %v", want, got, rec.Result().Header)
+		}
+		if want := "response headers over the limit were removed"; !strings.Contains(b.String(), want) {
+			t.Errorf("expected logs to contain %q but got:\n%s", want, b.String())
+		}
+	})
+	t.Run("under the limits", func(t *testing.T) {
+		b := captureLogs(t)
+		h := MaxResponseHeadersMiddleware(5, 1024)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-One", "1")
+			_, _ = w.Write([]byte("ok"))
+		}))
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		if rec.Result().Header.Get("X-One") != "1" {
+			t.Errorf("expected the header to be kept")
+		}
+		if b.String() != "" {
+			t.Errorf("expected no logs but got:\n%s", b.String())
+		}
+	})
+}
@@ -0,0 +1,59 @@
+package httpx
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Tracer starts the spans of [OTelMiddleware]. This is the subset of an OpenTelemetry tracer needed by the
+// middleware, so httpx doesn't depend on the OpenTelemetry API. An adapter over a trace.Tracer starts a span
+// of server kind and returns it together with the context that carries it.
+type Tracer interface {
+	Start(ctx context.Context, spanName string) (context.Context, Span)
+}
+
+// Span is the span started by a [Tracer].
+type Span interface {
+	SetName(name string)
+	// SetAttribute records an attribute with a string or an int value.
+	SetAttribute(key string, value any)
+	RecordError(err error)
+	// SetErrorStatus marks the span as failed with the given description.
+	SetErrorStatus(description string)
+	End()
+}
+
+// OTelMiddleware returns a middleware that starts a span for each request with the given tracer.
+// The span is available to the handlers through the request context returned by [Tracer.Start] and is named
+// after the route pattern of the request, resolved in the same way as in [ProfilerMiddleware].
+// The status code of the response is recorded on the span and the responses with a 5xx status code, or the
+// panics, mark the span as failed.
+// The tracer is an interface, so any OpenTelemetry SDK (or none) can be used through a small adapter.
+func OTelMiddleware(tracer Tracer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			ctx, span := tracer.Start(r.Context(), r.Method)
+			defer span.End()
+			span.SetAttribute("http.request.method", r.Method)
+			span.SetAttribute("url.path", r.URL.Path)
+			r = r.WithContext(ctx)
+			rw := NewInterceptor(w)
+			defer func() {
+				if rec := recover(); rec != nil {
+					span.RecordError(fmt.Errorf("panic: %v", rec))
+					span.SetErrorStatus("panic")
+					panic(rec)
+				}
+			}()
+			next.ServeHTTP(rw, r)
+
+			span.SetName(routePattern(r))
+			span.SetAttribute("http.response.status_code", rw.StatusCode)
+			if rw.StatusCode >= http.StatusInternalServerError {
+				span.SetErrorStatus(http.StatusText(rw.StatusCode))
+			}
+		}
+		return http.HandlerFunc(fn)
+	}
+}
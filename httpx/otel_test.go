@@ -0,0 +1,97 @@
+package httpx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestOTelMiddleware(t *testing.T) {
+	cases := []struct {
+		name       string
+		status     int
+		wantFailed bool
+	}{
+		{name: "successful request", status: http.StatusOK},
+		{name: "client error", status: http.StatusNotFound},
+		{name: "server error", status: http.StatusInternalServerError, wantFailed: true},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			tracer := &fakeTracer{}
+			mux := http.NewServeMux()
+			var handlerSpan Span
+			mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+				handlerSpan, _ = r.Context().Value(ctxKeyFakeSpan{}).(Span)
+				w.WriteHeader(tt.status)
+			})
+			h := OTelMiddleware(tracer)(mux)
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+			if len(tracer.spans) != 1 {
+				t.Fatalf("expected 1 span but got %d", len(tracer.spans))
+			}
+			span := tracer.spans[0]
+			if handlerSpan != span {
+				t.Errorf("expected the span to be propagated to the handler")
+			}
+			if got, want := span.name, "GET /users/{id}"; got != want {
+				t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+			}
+			if got, want := span.attrs["http.response.status_code"], tt.status; got != want {
+				t.Errorf("got a different value than the wanted one. expected: %v; got: %v", want, got)
+			}
+			if !span.ended {
+				t.Errorf("expected the span to be ended")
+			}
+			if got := span.failed; got != tt.wantFailed {
+				t.Errorf("got a different value than the wanted one. expected: %t; got: %t", tt.wantFailed, got)
+			}
+		})
+	}
+	t.Run("panic marks the span as failed", func(t *testing.T) {
+		tracer := &fakeTracer{}
+		h := OTelMiddleware(tracer)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("boom")
+		}))
+		func() {
+			defer func() { _ = recover() }()
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		}()
+		span := tracer.spans[0]
+		if !span.ended || !span.failed || len(span.errs) != 1 {
+			t.Errorf("expected the span to be ended as failed with the panic recorded but got: %+v", span)
+		}
+	})
+}
+
+type ctxKeyFakeSpan struct{}
+
+type fakeTracer struct {
+	m     sync.Mutex
+	spans []*fakeSpan
+}
+
+func (f *fakeTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	f.m.Lock()
+	defer f.m.Unlock()
+	s := &fakeSpan{name: name, attrs: map[string]any{}}
+	f.spans = append(f.spans, s)
+	return context.WithValue(ctx, ctxKeyFakeSpan{}, Span(s)), s
+}
+
+type fakeSpan struct {
+	name   string
+	attrs  map[string]any
+	failed bool
+	errs   []error
+	ended  bool
+}
+
+func (s *fakeSpan) SetName(name string)                { s.name = name }
+func (s *fakeSpan) SetAttribute(key string, value any) { s.attrs[key] = value }
+func (s *fakeSpan) RecordError(err error)              { s.errs = append(s.errs, err) }
+func (s *fakeSpan) SetErrorStatus(string)              { s.failed = true }
+func (s *fakeSpan) End()                               { s.ended = true }
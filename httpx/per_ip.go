@@ -0,0 +1,66 @@
+package httpx
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// PerIPConcurrencyMiddleware returns a middleware that limits the number of in-flight requests of each client IP.
+// When a client exceeds the given max, its requests are answered with http.StatusTooManyRequests, while the
+// other clients are not affected.
+// The counter of an IP is removed as soon as it has no in-flight requests, keeping the memory bounded to the
+// number of clients with requests in progress.
+//
+// The client IP is read in the same way as in [IPFilterMiddleware].
+// This panics when max is not positive, since no request could be served.
+func PerIPConcurrencyMiddleware(max int) func(http.Handler) http.Handler {
+	if max <= 0 {
+		panic(fmt.Sprintf("httpx: the max in-flight requests per IP must be positive, got %d", max))
+	}
+	l := &ipLimiter{
+		max:      max,
+		inFlight: map[string]int{},
+	}
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			key := r.RemoteAddr
+			if ip, ok := clientIP(r); ok {
+				key = ip.String()
+			}
+			if !l.acquire(key) {
+				http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+				return
+			}
+			defer l.release(key)
+			next.ServeHTTP(w, r)
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+type ipLimiter struct {
+	max int
+
+	m        sync.Mutex
+	inFlight map[string]int
+}
+
+func (l *ipLimiter) acquire(key string) bool {
+	l.m.Lock()
+	defer l.m.Unlock()
+	if l.inFlight[key] >= l.max {
+		return false
+	}
+	l.inFlight[key]++
+	return true
+}
+
+func (l *ipLimiter) release(key string) {
+	l.m.Lock()
+	defer l.m.Unlock()
+	l.inFlight[key]--
+	if l.inFlight[key] <= 0 {
+		delete(l.inFlight, key)
+	}
+}
@@ -0,0 +1,94 @@
+package httpx
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPerIPConcurrencyMiddleware(t *testing.T) {
+	releaseCh := make(chan struct{})
+	var handling sync.WaitGroup
+	h := PerIPConcurrencyMiddleware(2)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handling.Done()
+		<-releaseCh
+		w.WriteHeader(http.StatusOK)
+	}))
+	serve := func(remoteAddr string) int {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// fill the slots of the first IP
+	var wg sync.WaitGroup
+	codes := make([]int, 2)
+	for i := range codes {
+		handling.Add(1)
+		wg.Go(func() {
+			codes[i] = serve("10.0.0.1:1000")
+		})
+	}
+	handling.Wait()
+
+	if got, want := serve("10.0.0.1:1001"), http.StatusTooManyRequests; got != want {
+		t.Errorf("expected status %d for the IP over its limit, got %d", want, got)
+	}
+
+	handling.Add(1)
+	otherCh := make(chan int, 1)
+	go func() {
+		otherCh <- serve("10.0.0.2:1000")
+	}()
+	handling.Wait()
+	close(releaseCh)
+	select {
+	case got := <-otherCh:
+		if want := http.StatusOK; got != want {
+			t.Errorf("expected status %d for another IP, got %d", want, got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("request of another IP was not served in time")
+	}
+	wg.Wait()
+	for i, got := range codes {
+		if want := http.StatusOK; got != want {
+			t.Errorf("expected status %d for request %d, got %d", want, i, got)
+		}
+	}
+
+	handling.Add(1)
+	if got, want := serve("10.0.0.1:1002"), http.StatusOK; got != want {
+		t.Errorf("expected status %d after the in-flight requests finished, got %d", want, got)
+	}
+}
+
+func TestIPLimiterEviction(t *testing.T) {
+	l := &ipLimiter{max: 1, inFlight: map[string]int{}}
+	if !l.acquire("10.0.0.1") {
+		t.Fatalf("expected to acquire a slot")
+	}
+	l.release("10.0.0.1")
+	if got := len(l.inFlight); got != 0 {
+		t.Errorf("expected the idle counters to be evicted but got %d", got)
+	}
+}
+
+func TestPerIPConcurrencyMiddlewareInvalidMax(t *testing.T) {
+	for _, max := range []int{0, -1} {
+		t.Run(fmt.Sprint(max), func(t *testing.T) {
+			defer func() {
+				want := fmt.Sprintf("httpx: the max in-flight requests per IP must be positive, got %d", max)
+				if got := recover(); got != want {
+					t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+				}
+			}()
+			PerIPConcurrencyMiddleware(max)
+		})
+	}
+}
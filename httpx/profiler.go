@@ -0,0 +1,134 @@
+package httpx
+
+import (
+	"cmp"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// EndpointLatency describes the latencies recorded by a [Profiler] for one endpoint.
+type EndpointLatency struct {
+	// Endpoint is the method and the route pattern of the requests (ie: "GET /users/{id}").
+	Endpoint string        `json:"endpoint"`
+	Count    int           `json:"count"`
+	Max      time.Duration `json:"max"`
+	Average  time.Duration `json:"average"`
+}
+
+// Profiler keeps the top n slowest endpoints, ranked by their maximum latency.
+// The memory is bounded to n endpoints: when a new endpoint is slower than the fastest one kept, it replaces it,
+// otherwise it's ignored.
+// Use [ProfilerMiddleware] to record the requests and [Profiler.Slowest] or [Profiler.Handler] to read the results.
+type Profiler struct {
+	n int
+
+	m         sync.Mutex
+	endpoints map[string]*endpointStats
+}
+
+type endpointStats struct {
+	count int
+	max   time.Duration
+	total time.Duration
+}
+
+// NewProfiler creates a [Profiler] keeping the top n slowest endpoints.
+func NewProfiler(n int) *Profiler {
+	return &Profiler{
+		n:         n,
+		endpoints: map[string]*endpointStats{},
+	}
+}
+
+// ProfilerMiddleware returns a middleware that records the latency of each request into the given [Profiler].
+// The requests are grouped by their route pattern, read after the request is handled from the chi routing context
+// or from [http.Request.Pattern]. When none is available, the path of the request is used.
+func ProfilerMiddleware(p *Profiler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			next.ServeHTTP(w, r)
+			p.record(routePattern(r), time.Since(start))
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// Slowest returns the recorded endpoints, from the slowest to the fastest.
+func (p *Profiler) Slowest() []EndpointLatency {
+	p.m.Lock()
+	res := make([]EndpointLatency, 0, len(p.endpoints))
+	for e, s := range p.endpoints {
+		res = append(res, EndpointLatency{
+			Endpoint: e,
+			Count:    s.count,
+			Max:      s.max,
+			Average:  s.total / time.Duration(s.count),
+		})
+	}
+	p.m.Unlock()
+	slices.SortFunc(res, func(a, b EndpointLatency) int {
+		if c := cmp.Compare(b.Max, a.Max); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Endpoint, b.Endpoint)
+	})
+	return res
+}
+
+// Handler returns a handler that writes the result of [Profiler.Slowest] as JSON.
+func (p *Profiler) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(p.Slowest())
+	})
+}
+
+func (p *Profiler) record(endpoint string, d time.Duration) {
+	p.m.Lock()
+	defer p.m.Unlock()
+	s, ok := p.endpoints[endpoint]
+	if !ok {
+		if p.n <= 0 {
+			return
+		}
+		if len(p.endpoints) >= p.n {
+			fastest, fastestMax := "", time.Duration(-1)
+			for e, s := range p.endpoints {
+				if fastestMax < 0 || s.max < fastestMax {
+					fastest, fastestMax = e, s.max
+				}
+			}
+			if d <= fastestMax {
+				return
+			}
+			delete(p.endpoints, fastest)
+		}
+		s = &endpointStats{}
+		p.endpoints[endpoint] = s
+	}
+	s.count++
+	s.total += d
+	s.max = max(s.max, d)
+}
+
+func routePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		if pattern := rctx.RoutePattern(); pattern != "" {
+			return r.Method + " " + pattern
+		}
+	}
+	if r.Pattern != "" {
+		if !strings.Contains(r.Pattern, " ") {
+			return r.Method + " " + r.Pattern
+		}
+		return r.Pattern
+	}
+	return r.Method + " " + r.URL.Path
+}
@@ -0,0 +1,79 @@
+package httpx
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestProfilerMiddleware(t *testing.T) {
+	t.Run("keeps the top n slowest endpoints in order", func(t *testing.T) {
+		p := NewProfiler(3)
+		mux := http.NewServeMux()
+		for path, latency := range map[string]time.Duration{
+			"/fast":   0,
+			"/medium": 20 * time.Millisecond,
+			"/slow":   40 * time.Millisecond,
+			"/slower": 60 * time.Millisecond,
+		} {
+			mux.HandleFunc("GET "+path, func(w http.ResponseWriter, r *http.Request) {
+				<-time.After(latency)
+			})
+		}
+		mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+			<-time.After(30 * time.Millisecond)
+		})
+		h := ProfilerMiddleware(p)(mux)
+		for _, path := range []string{"/fast", "/medium", "/users/1", "/users/2", "/slow", "/slower", "/fast"} {
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+		}
+
+		got := p.Slowest()
+		var endpoints []string
+		for _, e := range got {
+			endpoints = append(endpoints, e.Endpoint)
+		}
+		want := []string{"GET /slower", "GET /slow", "GET /users/{id}"}
+		if !slices.Equal(endpoints, want) {
+			t.Fatalf("got a different value than the wanted one. expected: %v; got: %v", want, endpoints)
+		}
+		if got := got[2].Count; got != 2 {
+			t.Errorf("expected the requests of the same pattern to be grouped but got a count of %d", got)
+		}
+		if got[0].Max < 60*time.Millisecond || got[0].Average > got[0].Max {
+			t.Errorf("got unexpected latencies for the slowest endpoint: %+v", got[0])
+		}
+	})
+	t.Run("chi route patterns", func(t *testing.T) {
+		p := NewProfiler(5)
+		r := chi.NewRouter()
+		r.Use(ProfilerMiddleware(p))
+		r.Get("/items/{id}", func(w http.ResponseWriter, r *http.Request) {})
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/items/42", nil))
+
+		got := p.Slowest()
+		if len(got) != 1 || got[0].Endpoint != "GET /items/{id}" {
+			t.Errorf("expected the chi route pattern but got: %+v", got)
+		}
+	})
+	t.Run("handler exposes the endpoints as json", func(t *testing.T) {
+		p := NewProfiler(5)
+		ProfilerMiddleware(p)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).
+			ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/unrouted", nil))
+
+		rec := httptest.NewRecorder()
+		p.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/slowest", nil))
+		var got []EndpointLatency
+		if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+			t.Fatalf("failed to decode the response: %s", err)
+		}
+		if len(got) != 1 || got[0].Endpoint != "POST /unrouted" || got[0].Count != 1 {
+			t.Errorf("got a different value than the wanted one: %+v", got)
+		}
+	})
+}
@@ -2,6 +2,7 @@ package httpx
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 
 	"github.com/google/uuid"
@@ -52,3 +53,23 @@ func GetReqID(ctx context.Context) string {
 	}
 	return ""
 }
+
+// RequireRequestID returns a middleware that requires the given header to be present on the request.
+// When the header is missing, the request is rejected with http.StatusBadRequest instead of generating a
+// new request ID. This is an alternative to [RequestIDMiddleware] for the environments where the upstream
+// correlation is mandatory and a missing ID indicates a misconfigured caller.
+// When the header is present, its value is injected into the context and can be read with [GetReqID].
+func RequireRequestID(header string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			requestID := r.Header.Get(header)
+			if requestID == "" {
+				http.Error(w, fmt.Sprintf("missing %s header", header), http.StatusBadRequest)
+				return
+			}
+			ctx := context.WithValue(r.Context(), ctxKeyRequestID, requestID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		}
+		return http.HandlerFunc(fn)
+	}
+}
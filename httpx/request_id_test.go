@@ -0,0 +1,42 @@
+package httpx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequireRequestID(t *testing.T) {
+	t.Run("header present", func(t *testing.T) {
+		var gotID string
+		h := RequireRequestID("X-Correlation-Id")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotID = GetReqID(r.Context())
+		}))
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Correlation-Id", "abc-123")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		if got, want := rec.Code, http.StatusOK; got != want {
+			t.Errorf("expected status %d, got %d", want, got)
+		}
+		if got, want := gotID, "abc-123"; got != want {
+			t.Errorf("expected request id %q in context, got %q", want, got)
+		}
+	})
+	t.Run("header missing", func(t *testing.T) {
+		called := false
+		h := RequireRequestID("X-Correlation-Id")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		}))
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if got, want := rec.Code, http.StatusBadRequest; got != want {
+			t.Errorf("expected status %d, got %d", want, got)
+		}
+		if called {
+			t.Errorf("expected the next handler to not be called")
+		}
+	})
+}
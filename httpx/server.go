@@ -7,6 +7,8 @@ import (
 	"log/slog"
 	"net"
 	"net/http"
+
+	"github.com/yottta/go-core/logging"
 )
 
 // Config can be embedded in your configs and map flags and env vars directly to the
@@ -41,7 +43,8 @@ func (c *Config) Start(ctx context.Context, h http.Handler) error {
 	}
 
 	srv = http.Server{
-		Handler: h,
+		Handler:  h,
+		ErrorLog: logging.StdLogger(slog.LevelWarn),
 	}
 	go func() {
 		select {
@@ -0,0 +1,115 @@
+package httpx
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// TimeoutMiddleware returns a middleware that limits the time the handlers have to answer a request.
+// The handler runs with a request context that is cancelled after the timeout, and its response is buffered.
+// When the handler does not finish in time, the middleware distinguishes between:
+//   - a server timeout: the client is answered with http.StatusGatewayTimeout and a warning is logged;
+//   - a client cancellation (ie: the client disconnected): nothing is written, since the client is gone, and
+//     the request is logged at debug level to avoid false positive timeouts in the logs.
+//
+// Any write of the handler after the timeout is discarded.
+func TimeoutMiddleware(timeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+			tw := &timeoutWriter{header: http.Header{}}
+			doneCh := make(chan struct{})
+			panicCh := make(chan any, 1)
+			start := time.Now()
+			go func() {
+				defer func() {
+					if p := recover(); p != nil {
+						panicCh <- p
+					}
+				}()
+				next.ServeHTTP(tw, r.WithContext(ctx))
+				close(doneCh)
+			}()
+			select {
+			case p := <-panicCh:
+				panic(p)
+			case <-doneCh:
+				tw.flushTo(w)
+			case <-ctx.Done():
+				tw.discard()
+				if r.Context().Err() != nil {
+					slog.
+						With(requestAttrs(r)).
+						With("duration", time.Since(start)).
+						Debug("request cancelled by the client")
+					return
+				}
+				slog.
+					With(requestAttrs(r)).
+					With("timeout", timeout).
+					Warn("request timed out")
+				http.Error(w, http.StatusText(http.StatusGatewayTimeout), http.StatusGatewayTimeout)
+			}
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// timeoutWriter buffers the response of the handler until it finishes in time.
+type timeoutWriter struct {
+	m         sync.Mutex
+	header    http.Header
+	buf       bytes.Buffer
+	code      int
+	discarded bool
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	tw.m.Lock()
+	defer tw.m.Unlock()
+	if tw.discarded {
+		return 0, http.ErrHandlerTimeout
+	}
+	if tw.code == 0 {
+		tw.code = http.StatusOK
+	}
+	return tw.buf.Write(b)
+}
+
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.m.Lock()
+	defer tw.m.Unlock()
+	if tw.discarded || tw.code != 0 {
+		return
+	}
+	tw.code = code
+}
+
+func (tw *timeoutWriter) discard() {
+	tw.m.Lock()
+	defer tw.m.Unlock()
+	tw.discarded = true
+}
+
+func (tw *timeoutWriter) flushTo(w http.ResponseWriter) {
+	tw.m.Lock()
+	defer tw.m.Unlock()
+	dst := w.Header()
+	for k, v := range tw.header {
+		dst[k] = v
+	}
+	if tw.code == 0 {
+		tw.code = http.StatusOK
+	}
+	w.WriteHeader(tw.code)
+	_, _ = w.Write(tw.buf.Bytes())
+}
@@ -0,0 +1,82 @@
+package httpx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimeoutMiddleware(t *testing.T) {
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		_, _ = w.Write([]byte("too late"))
+	})
+	t.Run("handler finishing in time", func(t *testing.T) {
+		h := TimeoutMiddleware(time.Second)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Handled", "true")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte("created"))
+		}))
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+		if got, want := rec.Code, http.StatusCreated; got != want {
+			t.Errorf("got a different status than the wanted one. expected: %d; got: %d", want, got)
+		}
+		if got, want := rec.Body.String(), "created"; got != want {
+			t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+		}
+		if rec.Header().Get("X-Handled") != "true" {
+			t.Errorf("expected the headers of the handler to be written")
+		}
+	})
+	t.Run("server timeout", func(t *testing.T) {
+		b := captureLogs(t)
+		h := TimeoutMiddleware(50 * time.Millisecond)(slow)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		if got, want := rec.Code, http.StatusGatewayTimeout; got != want {
+			t.Errorf("got a different status than the wanted one. expected: %d; got: %d", want, got)
+		}
+		if strings.Contains(rec.Body.String(), "too late") {
+			t.Errorf("expected the writes after the timeout to be discarded but got: %q", rec.Body.String())
+		}
+		if want := "level=WARN msg=\"request timed out\""; !strings.Contains(b.String(), want) {
+			t.Errorf("expected logs to contain %q but got:\n%s", want, b.String())
+		}
+	})
+	t.Run("client cancellation", func(t *testing.T) {
+		b := captureLogs(t)
+		h := TimeoutMiddleware(time.Second)(slow)
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			<-time.After(50 * time.Millisecond)
+			cancel()
+		}()
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx))
+		if rec.Body.Len() != 0 || rec.Result().Header.Get("Content-Type") != "" {
+			t.Errorf("expected no response for a cancelled request but got: %d %q", rec.Code, rec.Body.String())
+		}
+		logs := b.String()
+		if want := "level=DEBUG msg=\"request cancelled by the client\""; !strings.Contains(logs, want) {
+			t.Errorf("expected logs to contain %q but got:\n%s", want, logs)
+		}
+		if strings.Contains(logs, "level=WARN") {
+			t.Errorf("expected no warning for a cancelled request but got:\n%s", logs)
+		}
+	})
+	t.Run("panics are propagated", func(t *testing.T) {
+		h := TimeoutMiddleware(time.Second)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("boom")
+		}))
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Errorf("expected the panic to be propagated but got: %v", r)
+			}
+		}()
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
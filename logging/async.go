@@ -0,0 +1,215 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// asyncDropReportInterval is how often the number of dropped records is reported.
+const asyncDropReportInterval = 10 * time.Second
+
+// asyncHandlers holds the async handlers that are not closed yet, to be flushed by [Flush].
+var asyncHandlers sync.Map
+
+// AsyncHandler writes the records to the next handler from a single background goroutine, so the callers are never
+// blocked by a slow destination (ie: network syslog or a throttled disk).
+// The records are queued in a buffer of the configured size. When the buffer is full, the records are dropped and
+// counted, and a "dropped N log records" warning is written periodically.
+//
+// The handler must be closed with [AsyncHandler.Close] to write the queued records. [Flush] writes the queued
+// records of all the async handlers, and it's called by the app during its shutdown.
+type AsyncHandler struct {
+	next  slog.Handler
+	state *asyncState
+}
+
+type asyncState struct {
+	// root is the handler used to report the dropped records
+	root      slog.Handler
+	queue     chan asyncItem
+	syncLevel atomic.Pointer[slog.Level]
+
+	dropped        atomic.Uint64
+	droppedPending atomic.Uint64
+
+	// closedM guards closed so no record is queued after the background goroutine drained the queue
+	closedM   sync.RWMutex
+	closed    bool
+	closeOnce sync.Once
+	closeCh   chan struct{}
+	doneCh    chan struct{}
+}
+
+type asyncItem struct {
+	ctx    context.Context
+	next   slog.Handler
+	record slog.Record
+	// flushed is set for the flush markers and closed once all the records queued before it were written
+	flushed chan struct{}
+}
+
+// NewAsyncHandler returns a new [AsyncHandler] writing into next, with a buffer of the given number of records.
+// It panics when buffer is lower than 1, since an unbuffered queue would drop almost all the records.
+func NewAsyncHandler(next slog.Handler, buffer int) *AsyncHandler {
+	if buffer < 1 {
+		panic(fmt.Sprintf("logging: the async buffer must hold at least one record, got %d", buffer))
+	}
+	s := &asyncState{
+		root:    next,
+		queue:   make(chan asyncItem, buffer),
+		closeCh: make(chan struct{}),
+		doneCh:  make(chan struct{}),
+	}
+	asyncHandlers.Store(s, struct{}{})
+	go s.run()
+	return &AsyncHandler{next: next, state: s}
+}
+
+// SetSyncLevel configures the records at or above the given level (ie: errors) to be written synchronously,
+// bypassing the queue, so they are never dropped. Such a record might be written before the records queued
+// ahead of it.
+func (h *AsyncHandler) SetSyncLevel(l slog.Level) {
+	h.state.syncLevel.Store(&l)
+}
+
+// Dropped returns the number of records dropped since the handler was created.
+func (h *AsyncHandler) Dropped() uint64 {
+	return h.state.dropped.Load()
+}
+
+func (h *AsyncHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	return h.next.Enabled(ctx, l)
+}
+
+// Handle queues the record without blocking. After the handler is closed, the records are written synchronously.
+func (h *AsyncHandler) Handle(ctx context.Context, r slog.Record) error {
+	if l := h.state.syncLevel.Load(); l != nil && r.Level >= *l {
+		return h.next.Handle(ctx, r)
+	}
+	h.state.closedM.RLock()
+	defer h.state.closedM.RUnlock()
+	if h.state.closed {
+		return h.next.Handle(ctx, r)
+	}
+	select {
+	case h.state.queue <- asyncItem{ctx: context.WithoutCancel(ctx), next: h.next, record: r.Clone()}:
+	default:
+		h.state.dropped.Add(1)
+		h.state.droppedPending.Add(1)
+	}
+	return nil
+}
+
+func (h *AsyncHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &AsyncHandler{next: h.next.WithAttrs(attrs), state: h.state}
+}
+
+func (h *AsyncHandler) WithGroup(name string) slog.Handler {
+	return &AsyncHandler{next: h.next.WithGroup(name), state: h.state}
+}
+
+// Flush waits until the records queued before the call are written, or until the context is done.
+func (h *AsyncHandler) Flush(ctx context.Context) error {
+	return h.state.flush(ctx)
+}
+
+// Close writes the queued records and stops the background goroutine. The records handled afterward are
+// written synchronously. When the context is done before all the records are written, its error is returned
+// and the remaining records are written in the background.
+func (h *AsyncHandler) Close(ctx context.Context) error {
+	s := h.state
+	s.closeOnce.Do(func() {
+		asyncHandlers.Delete(s)
+		s.closedM.Lock()
+		s.closed = true
+		s.closedM.Unlock()
+		close(s.closeCh)
+	})
+	select {
+	case <-s.doneCh:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Flush forwards the records held by the [ErrorDedupHandler] and writes the queued records of all the
+// [AsyncHandler] that are not closed, waiting at most until the context is done.
+func Flush(ctx context.Context) error {
+	flushDedup()
+	var errs []error
+	asyncHandlers.Range(func(k, _ any) bool {
+		if err := k.(*asyncState).flush(ctx); err != nil {
+			errs = append(errs, err)
+		}
+		return true
+	})
+	return errors.Join(errs...)
+}
+
+func (s *asyncState) flush(ctx context.Context) error {
+	flushed := make(chan struct{})
+	select {
+	case s.queue <- asyncItem{flushed: flushed}:
+	case <-s.doneCh:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-flushed:
+		return nil
+	case <-s.doneCh:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *asyncState) run() {
+	defer close(s.doneCh)
+	t := time.NewTicker(asyncDropReportInterval)
+	defer t.Stop()
+	for {
+		select {
+		case it := <-s.queue:
+			s.write(it)
+		case <-t.C:
+			s.reportDropped()
+		case <-s.closeCh:
+			for {
+				select {
+				case it := <-s.queue:
+					s.write(it)
+				default:
+					s.reportDropped()
+					return
+				}
+			}
+		}
+	}
+}
+
+func (s *asyncState) write(it asyncItem) {
+	if it.flushed != nil {
+		s.reportDropped()
+		close(it.flushed)
+		return
+	}
+	_ = it.next.Handle(it.ctx, it.record)
+}
+
+func (s *asyncState) reportDropped() {
+	n := s.droppedPending.Swap(0)
+	if n == 0 {
+		return
+	}
+	r := slog.NewRecord(time.Now(), slog.LevelWarn, fmt.Sprintf("dropped %d log records", n), 0)
+	r.AddAttrs(slog.Uint64("dropped", n))
+	_ = s.root.Handle(context.Background(), r)
+}
@@ -0,0 +1,123 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAsyncHandler(t *testing.T) {
+	t.Run("callers are not blocked and the overflow is dropped", func(t *testing.T) {
+		var b syncBuffer
+		release := make(chan struct{})
+		h := NewAsyncHandler(&blockingHandler{next: slog.NewTextHandler(&b, nil), release: release}, 5)
+		l := slog.New(h)
+		l.Info("info log here")
+		// wait for the first record to be taken by the blocked handler
+		for deadline := time.Now().Add(time.Second); len(h.state.queue) > 0 && time.Now().Before(deadline); {
+			<-time.After(time.Millisecond)
+		}
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for range 19 {
+				l.Info("info log here")
+			}
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatalf("expected the logging to not block the caller")
+		}
+		// one record is held by the blocked handler and 5 are queued
+		if got, want := h.Dropped(), uint64(14); got != want {
+			t.Errorf("got a different number of dropped records than the wanted one. expected: %d; got: %d", want, got)
+		}
+
+		close(release)
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		if err := h.Close(ctx); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		logs := b.String()
+		if got, want := strings.Count(logs, "info log here"), 6; got != want {
+			t.Errorf("expected %d records written but got %d:\n%s", want, got, logs)
+		}
+		if want := "dropped 14 log records"; !strings.Contains(logs, want) {
+			t.Errorf("expected the logs to contain %q but got:\n%s", want, logs)
+		}
+
+		l.Info("after close")
+		if !strings.Contains(b.String(), "after close") {
+			t.Errorf("expected the records after close to be written synchronously")
+		}
+	})
+	t.Run("records at the sync level bypass the queue", func(t *testing.T) {
+		var b syncBuffer
+		release := make(chan struct{})
+		h := NewAsyncHandler(&blockingHandler{next: slog.NewTextHandler(&b, nil), release: release, level: slog.LevelInfo}, 5)
+		h.SetSyncLevel(slog.LevelError)
+		l := slog.New(h)
+		l.Info("info log here")
+		l.Error("error log here")
+		if got := b.String(); !strings.Contains(got, "error log here") || strings.Contains(got, "info log here") {
+			t.Errorf("expected only the error to be written synchronously but got:\n%s", got)
+		}
+		close(release)
+		_ = h.Close(context.Background())
+	})
+	t.Run("flush writes the queued records of all handlers", func(t *testing.T) {
+		var b syncBuffer
+		h := NewAsyncHandler(slog.NewTextHandler(&b, nil), 100)
+		defer func() { _ = h.Close(context.Background()) }()
+		l := slog.New(h).With("key", "value")
+		for range 10 {
+			l.Info("info log here")
+		}
+		if err := Flush(context.Background()); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		if got, want := strings.Count(b.String(), `"info log here" key=value`), 10; got != want {
+			t.Errorf("expected %d records written but got %d:\n%s", want, got, b.String())
+		}
+	})
+	t.Run("panics on an empty buffer", func(t *testing.T) {
+		defer func() {
+			want := "logging: the async buffer must hold at least one record, got 0"
+			if got := recover(); got != want {
+				t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+			}
+		}()
+		NewAsyncHandler(slog.DiscardHandler, 0)
+	})
+}
+
+// blockingHandler blocks the records at or below the level until released.
+type blockingHandler struct {
+	next    slog.Handler
+	release chan struct{}
+	level   slog.Level
+}
+
+func (h *blockingHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	return h.next.Enabled(ctx, l)
+}
+
+func (h *blockingHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level <= h.level {
+		<-h.release
+	}
+	return h.next.Handle(ctx, r)
+}
+
+func (h *blockingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &blockingHandler{next: h.next.WithAttrs(attrs), release: h.release, level: h.level}
+}
+
+func (h *blockingHandler) WithGroup(name string) slog.Handler {
+	return &blockingHandler{next: h.next.WithGroup(name), release: h.release, level: h.level}
+}
@@ -0,0 +1,29 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+)
+
+type ctxKeyLogger struct{}
+
+// IntoContext returns a new context carrying the given logger.
+// A nil context is replaced with [context.Background].
+func IntoContext(ctx context.Context, l *slog.Logger) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, ctxKeyLogger{}, l)
+}
+
+// FromContext returns the logger stored in the context by [IntoContext].
+// When the context is nil or does not carry a logger, [slog.Default] is returned.
+func FromContext(ctx context.Context) *slog.Logger {
+	if ctx == nil {
+		return slog.Default()
+	}
+	if l, ok := ctx.Value(ctxKeyLogger{}).(*slog.Logger); ok && l != nil {
+		return l
+	}
+	return slog.Default()
+}
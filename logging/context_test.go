@@ -0,0 +1,37 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+)
+
+func TestContext(t *testing.T) {
+	t.Run("logger stored and retrieved", func(t *testing.T) {
+		l := slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))
+		ctx := IntoContext(context.Background(), l)
+		if got := FromContext(ctx); got != l {
+			t.Errorf("expected the stored logger to be returned")
+		}
+	})
+	t.Run("missing logger falls back on default", func(t *testing.T) {
+		if got := FromContext(context.Background()); got != slog.Default() {
+			t.Errorf("expected the default logger to be returned")
+		}
+	})
+	t.Run("nil context", func(t *testing.T) {
+		if got := FromContext(nil); got != slog.Default() {
+			t.Errorf("expected the default logger to be returned")
+		}
+		l := slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))
+		if got := FromContext(IntoContext(nil, l)); got != l {
+			t.Errorf("expected the stored logger to be returned")
+		}
+	})
+	t.Run("nil logger falls back on default", func(t *testing.T) {
+		if got := FromContext(IntoContext(context.Background(), nil)); got != slog.Default() {
+			t.Errorf("expected the default logger to be returned")
+		}
+	})
+}
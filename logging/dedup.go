@@ -0,0 +1,165 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DedupOccurrencesKey is the key of the attribute added by [ErrorDedupHandler] with the number of the identical
+// records received in the window.
+const DedupOccurrencesKey = "dedup.occurrences"
+
+// dedupStates holds the dedup states with held records, to be flushed by [Flush].
+var dedupStates sync.Map
+
+// ErrorDedupHandler returns a handler that collapses the identical error records emitted within the given window.
+// The first occurrence of an error record is held until the end of the window and then forwarded to the next
+// handler with a [DedupOccurrencesKey] attribute counting all the identical records received in the window.
+// The records are identical when they have the same level, message and attributes, including the ones added
+// to the logger with [slog.Logger.With] and [slog.Logger.WithGroup]. The attributes that differ on each record, the
+// "trace_id" and "span_id" ones and the [OccurrencesKey] one of the [FingerprintHandler], are ignored, so the same
+// error of different requests is collapsed too, keeping the attributes of the first occurrence.
+// The records below the error level are forwarded right away. The held records are forwarded by [Flush] too.
+func ErrorDedupHandler(next slog.Handler, window time.Duration) slog.Handler {
+	return &dedupHandler{
+		next:   next,
+		window: window,
+		state:  &dedupState{pending: map[string]*dedupEntry{}},
+	}
+}
+
+// WithErrorDedup enables the [ErrorDedupHandler] with the given window.
+func WithErrorDedup(window time.Duration) Option {
+	return func(c *config) {
+		c.errorDedupWindow = window
+	}
+}
+
+type dedupHandler struct {
+	next   slog.Handler
+	window time.Duration
+	state  *dedupState
+	// scope is the part of the key given by the attributes and the groups of the logger
+	scope string
+}
+
+// dedupState is shared between the handlers derived with WithAttrs and WithGroup.
+type dedupState struct {
+	m       sync.Mutex
+	pending map[string]*dedupEntry
+}
+
+type dedupEntry struct {
+	// ctx is the context of the first occurrence, without its cancellation, since it's used after the window
+	ctx    context.Context
+	record slog.Record
+	next   slog.Handler
+	count  int
+	timer  *time.Timer
+}
+
+func (h *dedupHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	return h.next.Enabled(ctx, l)
+}
+
+func (h *dedupHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level < slog.LevelError {
+		return h.next.Handle(ctx, r)
+	}
+	key := h.scope + dedupKey(r)
+	s := h.state
+	s.m.Lock()
+	defer s.m.Unlock()
+	if e, ok := s.pending[key]; ok {
+		e.count++
+		return nil
+	}
+	e := &dedupEntry{ctx: context.WithoutCancel(ctx), record: r.Clone(), next: h.next, count: 1}
+	e.timer = time.AfterFunc(h.window, func() {
+		s.flush(key, e)
+	})
+	s.pending[key] = e
+	dedupStates.Store(s, struct{}{})
+	return nil
+}
+
+// flush forwards the held record with the number of occurrences, unless it was already forwarded by drain.
+func (s *dedupState) flush(key string, e *dedupEntry) {
+	s.m.Lock()
+	if s.pending[key] != e {
+		s.m.Unlock()
+		return
+	}
+	delete(s.pending, key)
+	if len(s.pending) == 0 {
+		dedupStates.Delete(s)
+	}
+	s.m.Unlock()
+	e.forward()
+}
+
+// drain forwards all the held records, in the order they were received.
+func (s *dedupState) drain() {
+	s.m.Lock()
+	entries := make([]*dedupEntry, 0, len(s.pending))
+	for _, e := range s.pending {
+		e.timer.Stop()
+		entries = append(entries, e)
+	}
+	clear(s.pending)
+	dedupStates.Delete(s)
+	s.m.Unlock()
+	slices.SortFunc(entries, func(a, b *dedupEntry) int {
+		return a.record.Time.Compare(b.record.Time)
+	})
+	for _, e := range entries {
+		e.forward()
+	}
+}
+
+func (e *dedupEntry) forward() {
+	e.record.AddAttrs(slog.Int(DedupOccurrencesKey, e.count))
+	_ = e.next.Handle(e.ctx, e.record)
+}
+
+// flushDedup forwards the records held by all the [ErrorDedupHandler].
+func flushDedup() {
+	dedupStates.Range(func(k, _ any) bool {
+		k.(*dedupState).drain()
+		return true
+	})
+}
+
+func dedupKey(r slog.Record) string {
+	var b strings.Builder
+	b.WriteString(r.Level.String())
+	b.WriteByte(0)
+	b.WriteString(r.Message)
+	r.Attrs(func(a slog.Attr) bool {
+		if a.Key == "trace_id" || a.Key == "span_id" || a.Key == OccurrencesKey {
+			return true
+		}
+		b.WriteByte(0)
+		b.WriteString(a.String())
+		return true
+	})
+	return b.String()
+}
+
+func (h *dedupHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var b strings.Builder
+	b.WriteString(h.scope)
+	for _, a := range attrs {
+		b.WriteString(a.String())
+		b.WriteByte(0)
+	}
+	return &dedupHandler{next: h.next.WithAttrs(attrs), window: h.window, state: h.state, scope: b.String()}
+}
+
+func (h *dedupHandler) WithGroup(name string) slog.Handler {
+	return &dedupHandler{next: h.next.WithGroup(name), window: h.window, state: h.state, scope: h.scope + name + ".\x00"}
+}
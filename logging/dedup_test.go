@@ -0,0 +1,155 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestErrorDedupHandler(t *testing.T) {
+	t.Run("identical errors are summarized once per window", func(t *testing.T) {
+		var b syncBuffer
+		l := slog.New(ErrorDedupHandler(slog.NewTextHandler(&b, nil), 50*time.Millisecond))
+		for range 100 {
+			l.Error("dependency down", "dep", "db")
+		}
+		l.Error("dependency down", "dep", "cache")
+		l.Info("info is not deduplicated")
+		l.Info("info is not deduplicated")
+
+		if got := strings.Count(b.String(), "dependency down"); got != 0 {
+			t.Errorf("expected the errors to be held until the end of the window but got %d", got)
+		}
+		if got, want := strings.Count(b.String(), "info is not deduplicated"), 2; got != want {
+			t.Errorf("expected %d info records but got %d", want, got)
+		}
+		<-time.After(150 * time.Millisecond)
+		logs := b.String()
+		if got, want := strings.Count(logs, "dep=db"), 1; got != want {
+			t.Errorf("expected %d summarized record for db but got %d:\n%s", want, got, logs)
+		}
+		if want := "dep=db dedup.occurrences=100"; !strings.Contains(logs, want) {
+			t.Errorf("expected logs to contain %q but got:\n%s", want, logs)
+		}
+		if want := "dep=cache dedup.occurrences=1"; !strings.Contains(logs, want) {
+			t.Errorf("expected logs to contain %q but got:\n%s", want, logs)
+		}
+
+		l.Error("dependency down", "dep", "db")
+		<-time.After(150 * time.Millisecond)
+		if got, want := strings.Count(b.String(), "dep=db"), 2; got != want {
+			t.Errorf("expected a new summarized record in the next window but got %d", got)
+		}
+	})
+	t.Run("the attributes and the groups of the logger are part of the key", func(t *testing.T) {
+		var b syncBuffer
+		l := slog.New(ErrorDedupHandler(slog.NewTextHandler(&b, nil), time.Hour))
+		l.With("dep", "db").Error("dependency down")
+		l.With("dep", "db").Error("dependency down")
+		l.With("dep", "cache").Error("dependency down")
+		l.WithGroup("g").Error("dependency down", "dep", "db")
+		l.Error("dependency down", "dep", "db")
+		flushDedup()
+
+		logs := b.String()
+		for _, want := range []string{
+			"dep=db dedup.occurrences=2",
+			"dep=cache dedup.occurrences=1",
+			"g.dep=db g.dedup.occurrences=1",
+		} {
+			if !strings.Contains(logs, want) {
+				t.Errorf("expected logs to contain %q but got:\n%s", want, logs)
+			}
+		}
+		if got, want := strings.Count(logs, "dependency down"), 4; got != want {
+			t.Errorf("expected %d summarized records but got %d:\n%s", want, got, logs)
+		}
+	})
+	t.Run("the stack and the trace ids are taken when the record is emitted", func(t *testing.T) {
+		var b syncBuffer
+		l, err := SetupWithWriter(&b,
+			WithFormat(FormatJSON),
+			WithErrorDedup(time.Hour),
+			WithStacktraces(slog.LevelError),
+			WithTraceCorrelation(true),
+		)
+		if err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		sc := trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    trace.TraceID{0x01},
+			SpanID:     trace.SpanID{0x01},
+			TraceFlags: trace.FlagsSampled,
+		})
+		ctx, cancel := context.WithCancel(trace.ContextWithSpanContext(context.Background(), sc))
+		l.ErrorContext(ctx, "dependency down")
+		cancel()
+		if got := b.String(); got != "" {
+			t.Errorf("expected the error to be held but got:\n%s", got)
+		}
+		if err := Flush(context.Background()); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+
+		logs := b.String()
+		for _, want := range []string{
+			`"trace_id":"` + sc.TraceID().String() + `"`,
+			`"stack":"github.com/yottta/go-core/logging.TestErrorDedupHandler`,
+			`"dedup.occurrences":1`,
+		} {
+			if !strings.Contains(logs, want) {
+				t.Errorf("expected logs to contain %q but got:\n%s", want, logs)
+			}
+		}
+	})
+	t.Run("the fingerprints count the occurrences held by the dedup", func(t *testing.T) {
+		var b syncBuffer
+		l, err := SetupWithWriter(&b, WithFormat(FormatJSON), WithErrorDedup(time.Hour), WithFingerprints(10))
+		if err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		for range 3 {
+			l.Error("fingerprinted and deduplicated")
+		}
+		if err := Flush(context.Background()); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		logs := b.String()
+		for _, want := range []string{`"occurrences":1,`, `"dedup.occurrences":3}`} {
+			if !strings.Contains(logs, want) {
+				t.Errorf("expected logs to contain %q but got:\n%s", want, logs)
+			}
+		}
+		var occurrences uint64
+		for _, e := range TopErrors(100) {
+			if e.Message == "fingerprinted and deduplicated" {
+				occurrences = e.Occurrences
+			}
+		}
+		if got, want := occurrences, uint64(3); got != want {
+			t.Errorf("got a different value than the wanted one. expected: %d; got: %d", want, got)
+		}
+	})
+	t.Run("enabled by option in setup", func(t *testing.T) {
+		var b syncBuffer
+		l, err := SetupWithWriter(&b, WithErrorDedup(time.Hour))
+		if err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		l.Error("dependency down")
+		l.Error("dependency down")
+		if got := b.String(); got != "" {
+			t.Errorf("expected the errors to be held but got:\n%s", got)
+		}
+		if err := Flush(context.Background()); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		if want := "dedup.occurrences=2"; !strings.Contains(b.String(), want) {
+			t.Errorf("expected logs to contain %q but got:\n%s", want, b.String())
+		}
+	})
+}
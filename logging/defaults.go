@@ -0,0 +1,117 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"slices"
+	"strings"
+)
+
+// VersionKey is the key of the default attribute holding the version of the main module.
+const VersionKey = "version"
+
+// WithDefaultAttrs overwrites the LOG_DEFAULT_ATTRS env var with the given attributes, added to all the records.
+// See [DefaultAttrsHandler].
+func WithDefaultAttrs(attrs ...slog.Attr) Option {
+	return func(c *config) {
+		c.defaultAttrs = attrs
+	}
+}
+
+// DefaultAttrsHandler returns a handler that adds the given attributes to all the records (ie: the service name,
+// version and environment), making the aggregated logs filterable.
+// Unlike [slog.Handler.WithAttrs], a default attribute is not written when the logger or the record has an
+// attribute with the same key, outside of any group, so the callers can overwrite the defaults.
+func DefaultAttrsHandler(next slog.Handler, attrs ...slog.Attr) slog.Handler {
+	return &defaultAttrsHandler{next: next, defaults: attrs}
+}
+
+type defaultAttrsHandler struct {
+	next slog.Handler
+	// defaults are the default attributes not yet shadowed by the attributes of the logger
+	defaults []slog.Attr
+}
+
+func (h *defaultAttrsHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	return h.next.Enabled(ctx, l)
+}
+
+func (h *defaultAttrsHandler) Handle(ctx context.Context, r slog.Record) error {
+	if len(h.defaults) == 0 {
+		return h.next.Handle(ctx, r)
+	}
+	keys := map[string]struct{}{}
+	r.Attrs(func(a slog.Attr) bool {
+		keys[a.Key] = struct{}{}
+		return true
+	})
+	nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	for _, a := range h.defaults {
+		if _, ok := keys[a.Key]; !ok {
+			nr.AddAttrs(a)
+		}
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		nr.AddAttrs(a)
+		return true
+	})
+	return h.next.Handle(ctx, nr)
+}
+
+func (h *defaultAttrsHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	defaults := slices.DeleteFunc(slices.Clone(h.defaults), func(d slog.Attr) bool {
+		return slices.ContainsFunc(attrs, func(a slog.Attr) bool { return a.Key == d.Key })
+	})
+	return &defaultAttrsHandler{next: h.next.WithAttrs(attrs), defaults: defaults}
+}
+
+// WithGroup writes the remaining defaults before opening the group, since the attributes in the group
+// cannot shadow them anymore.
+func (h *defaultAttrsHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	next := h.next
+	if len(h.defaults) > 0 {
+		next = next.WithAttrs(h.defaults)
+	}
+	return &defaultAttrsHandler{next: next.WithGroup(name)}
+}
+
+// defaultAttrsFromEnv parses the LOG_DEFAULT_ATTRS env var value, formatted as comma separated key=value entries.
+func defaultAttrsFromEnv(v string) ([]slog.Attr, error) {
+	var (
+		attrs []slog.Attr
+		errs  []error
+	)
+	for _, e := range strings.Split(v, ",") {
+		e = strings.TrimSpace(e)
+		if e == "" {
+			continue
+		}
+		k, val, ok := strings.Cut(e, "=")
+		k, val = strings.TrimSpace(k), strings.TrimSpace(val)
+		if !ok || k == "" {
+			errs = append(errs, fmt.Errorf("invalid LOG_DEFAULT_ATTRS entry %q: expected key=value", e))
+			continue
+		}
+		attrs = append(attrs, slog.String(k, val))
+	}
+	return attrs, errors.Join(errs...)
+}
+
+// withBuildVersion adds the version of the main module, as read from the build info, when the attributes
+// do not contain a version already and the binary was built from a tagged module.
+func withBuildVersion(attrs []slog.Attr) []slog.Attr {
+	if slices.ContainsFunc(attrs, func(a slog.Attr) bool { return a.Key == VersionKey }) {
+		return attrs
+	}
+	bi, ok := debug.ReadBuildInfo()
+	if !ok || bi.Main.Version == "" || bi.Main.Version == "(devel)" {
+		return attrs
+	}
+	return append(attrs, slog.String(VersionKey, bi.Main.Version))
+}
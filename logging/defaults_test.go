@@ -0,0 +1,79 @@
+package logging
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestDefaultAttrsHandler(t *testing.T) {
+	newLogger := func(b *bytes.Buffer) *slog.Logger {
+		return slog.New(DefaultAttrsHandler(slog.NewTextHandler(b, nil), slog.String("service", "payments"), slog.String("env", "prod")))
+	}
+	t.Run("defaults are added to all the records", func(t *testing.T) {
+		var b bytes.Buffer
+		newLogger(&b).Info("info log here", "key", "value")
+		if want := "msg=\"info log here\" service=payments env=prod key=value\n"; !strings.HasSuffix(b.String(), want) {
+			t.Errorf("expected %q to end with %q", b.String(), want)
+		}
+	})
+	t.Run("record attributes shadow the defaults", func(t *testing.T) {
+		var b bytes.Buffer
+		newLogger(&b).Info("info log here", "env", "staging")
+		if want := "msg=\"info log here\" service=payments env=staging\n"; !strings.HasSuffix(b.String(), want) {
+			t.Errorf("expected %q to end with %q", b.String(), want)
+		}
+	})
+	t.Run("logger attributes shadow the defaults", func(t *testing.T) {
+		var b bytes.Buffer
+		newLogger(&b).With("service", "refunds").Info("info log here")
+		if got := strings.Count(b.String(), "service="); got != 1 {
+			t.Errorf("expected a single service attribute but got %d: %s", got, b.String())
+		}
+		if want := "service=refunds"; !strings.Contains(b.String(), want) {
+			t.Errorf("expected %q to contain %q", b.String(), want)
+		}
+	})
+	t.Run("attributes in groups do not shadow the defaults", func(t *testing.T) {
+		var b bytes.Buffer
+		newLogger(&b).WithGroup("req").With("env", "header").Info("info log here", "service", "other")
+		if want := "msg=\"info log here\" service=payments env=prod req.env=header req.service=other\n"; !strings.HasSuffix(b.String(), want) {
+			t.Errorf("expected %q to end with %q", b.String(), want)
+		}
+	})
+	t.Run("LOG_DEFAULT_ATTRS", func(t *testing.T) {
+		t.Setenv("LOG_DEFAULT_ATTRS", "service=payments, env=prod")
+		var b bytes.Buffer
+		if _, err := SetupWithWriter(&b); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		slog.Info("info log here", "env", "dev")
+		if want := "service=payments env=dev"; !strings.Contains(b.String(), want) {
+			t.Errorf("expected %q to contain %q", b.String(), want)
+		}
+	})
+	t.Run("invalid LOG_DEFAULT_ATTRS", func(t *testing.T) {
+		t.Setenv("LOG_DEFAULT_ATTRS", "service=payments,prod")
+		var b bytes.Buffer
+		_, err := SetupWithWriter(&b)
+		if want := `invalid LOG_DEFAULT_ATTRS entry "prod": expected key=value`; err == nil || err.Error() != want {
+			t.Errorf("got a different error than the wanted one. expected: %q; got: %v", want, err)
+		}
+		slog.Info("info log here")
+		if want := "service=payments"; !strings.Contains(b.String(), want) {
+			t.Errorf("expected the valid entries to be used but got: %s", b.String())
+		}
+	})
+	t.Run("WithDefaultAttrs overwrites the env var", func(t *testing.T) {
+		t.Setenv("LOG_DEFAULT_ATTRS", "service=payments")
+		var b bytes.Buffer
+		if _, err := SetupWithWriter(&b, WithDefaultAttrs(slog.String("service", "refunds"))); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		slog.Info("info log here")
+		if want := "service=refunds"; !strings.Contains(b.String(), want) {
+			t.Errorf("expected %q to contain %q", b.String(), want)
+		}
+	})
+}
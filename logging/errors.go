@@ -0,0 +1,151 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+const (
+	stackMaxDepth  = 32
+	causesMaxDepth = 10
+)
+
+// Err returns a structured attribute for the given error, under the "error" key, containing:
+//   - message: the message of the error
+//   - type: the Go type of the error (ie: *fs.PathError)
+//   - causes: the messages of the errors wrapped by it, obtained with [errors.Unwrap], up to a depth of 10
+//   - stack: the stack trace, read from the first error in the chain that has a pkg/errors-style StackTrace
+//     method, like the errors created with [WithStack]
+//   - details: the value of the errors implementing [slog.LogValuer]. When it's a group, its attributes are added
+//     directly to the error attribute instead.
+//
+// The causes, the stack and the details are present only when available.
+// A nil error returns an empty attribute which is ignored by the handlers.
+func Err(err error) slog.Attr {
+	if err == nil {
+		return slog.Attr{}
+	}
+	attrs := []any{
+		slog.String("message", err.Error()),
+		slog.String("type", typeOf(err)),
+	}
+	if causes := causesOf(err); len(causes) > 0 {
+		attrs = append(attrs, slog.Any("causes", causes))
+	}
+	if stack := stackOf(err); stack != "" {
+		attrs = append(attrs, slog.String("stack", stack))
+	}
+	if lv, ok := err.(slog.LogValuer); ok {
+		if v := lv.LogValue().Resolve(); v.Kind() == slog.KindGroup {
+			for _, a := range v.Group() {
+				attrs = append(attrs, a)
+			}
+		} else {
+			attrs = append(attrs, slog.Any("details", v))
+		}
+	}
+	return slog.Group("error", attrs...)
+}
+
+// typeOf returns the type of the error, skipping the wrapper added by [WithStack].
+func typeOf(err error) string {
+	if se, ok := err.(*stackError); ok {
+		return typeOf(se.err)
+	}
+	return reflect.TypeOf(err).String()
+}
+
+// causesOf returns the messages of the errors wrapped by the given one. The wrappers keeping the message of
+// the error that they wrap, like the ones added by [WithStack], are not repeated.
+func causesOf(err error) []string {
+	var causes []string
+	prev := err.Error()
+	for cause := errors.Unwrap(err); cause != nil && len(causes) < causesMaxDepth; cause = errors.Unwrap(cause) {
+		if msg := cause.Error(); msg != prev {
+			causes = append(causes, msg)
+			prev = msg
+		}
+	}
+	return causes
+}
+
+// WithStack wraps the given error capturing the stack trace of the caller.
+// The returned error is unwrapping to the given one.
+func WithStack(err error) error {
+	if err == nil {
+		return nil
+	}
+	pcs := make([]uintptr, stackMaxDepth)
+	n := runtime.Callers(2, pcs)
+	return &stackError{err: err, pcs: pcs[:n]}
+}
+
+type stackError struct {
+	err error
+	pcs []uintptr
+}
+
+func (e *stackError) Error() string {
+	return e.err.Error()
+}
+
+func (e *stackError) Unwrap() error {
+	return e.err
+}
+
+// StackTrace follows the pkg/errors convention of returning the program counters of the stack.
+func (e *stackError) StackTrace() []uintptr {
+	return e.pcs
+}
+
+// stackOf walks the chain of errors and formats the stack of the first one exposing it.
+func stackOf(err error) string {
+	for err != nil {
+		if pcs, ok := stackTrace(err); ok {
+			return formatStack(pcs)
+		}
+		err = errors.Unwrap(err)
+	}
+	return ""
+}
+
+// stackTrace checks if the error has a StackTrace method that returns a slice of program counters.
+// Reflection is used to support pkg/errors without depending on it, since its StackTrace method returns
+// a named type ([]errors.Frame).
+func stackTrace(err error) ([]uintptr, bool) {
+	m := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !m.IsValid() {
+		return nil, false
+	}
+	mt := m.Type()
+	if mt.NumIn() != 0 || mt.NumOut() != 1 {
+		return nil, false
+	}
+	out := mt.Out(0)
+	if out.Kind() != reflect.Slice || out.Elem().Kind() != reflect.Uintptr {
+		return nil, false
+	}
+	v := m.Call(nil)[0]
+	pcs := make([]uintptr, v.Len())
+	for i := range pcs {
+		pcs[i] = uintptr(v.Index(i).Uint())
+	}
+	return pcs, len(pcs) > 0
+}
+
+func formatStack(pcs []uintptr) string {
+	var b strings.Builder
+	frames := runtime.CallersFrames(pcs)
+	for {
+		f, more := frames.Next()
+		_, _ = fmt.Fprintf(&b, "%s\n\t%s:%d\n", f.Function, f.File, f.Line)
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,130 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestErr(t *testing.T) {
+	attrsOf := func(a slog.Attr) map[string]string {
+		res := map[string]string{}
+		for _, ga := range a.Value.Group() {
+			res[ga.Key] = ga.Value.String()
+		}
+		return res
+	}
+	t.Run("error without stack", func(t *testing.T) {
+		a := Err(errors.New("plain error"))
+		if got, want := a.Key, "error"; got != want {
+			t.Fatalf("expected key %q but got %q", want, got)
+		}
+		attrs := attrsOf(a)
+		if got, want := attrs["message"], "plain error"; got != want {
+			t.Errorf("expected message %q but got %q", want, got)
+		}
+		if _, ok := attrs["stack"]; ok {
+			t.Errorf("expected no stack but got one")
+		}
+	})
+	t.Run("error with stack", func(t *testing.T) {
+		err := fmt.Errorf("wrapped: %w", WithStack(errors.New("root cause")))
+		attrs := attrsOf(Err(err))
+		if got, want := attrs["message"], "wrapped: root cause"; got != want {
+			t.Errorf("expected message %q but got %q", want, got)
+		}
+		if want := "logging.TestErr"; !strings.Contains(attrs["stack"], want) {
+			t.Errorf("expected stack to contain %q but got:\n%s", want, attrs["stack"])
+		}
+	})
+	t.Run("pkg/errors style stack", func(t *testing.T) {
+		err := WithStack(errors.New("x")).(*stackError)
+		frames := make(pkgErrorsStack, len(err.pcs))
+		for i, pc := range err.pcs {
+			frames[i] = pkgErrorsFrame(pc)
+		}
+		attrs := attrsOf(Err(&pkgErrorsLike{stack: frames}))
+		if want := "logging.TestErr"; !strings.Contains(attrs["stack"], want) {
+			t.Errorf("expected stack to contain %q but got:\n%s", want, attrs["stack"])
+		}
+	})
+	t.Run("type and causes", func(t *testing.T) {
+		_, pathErr := os.Open("/does/not/exist")
+		err := fmt.Errorf("loading config: %w", WithStack(pathErr))
+		a := Err(err)
+		attrs := attrsOf(a)
+		if got, want := attrs["type"], "*fmt.wrapError"; got != want {
+			t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+		}
+		var causes []string
+		for _, ga := range a.Value.Group() {
+			if ga.Key == "causes" {
+				causes, _ = ga.Value.Any().([]string)
+			}
+		}
+		want := []string{pathErr.Error(), "no such file or directory"}
+		if !slices.Equal(causes, want) {
+			t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, causes)
+		}
+		if got, want := attrsOf(Err(WithStack(pathErr)))["type"], "*fs.PathError"; got != want {
+			t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+		}
+	})
+	t.Run("causes are capped", func(t *testing.T) {
+		err := errors.New("root")
+		for i := range 20 {
+			err = fmt.Errorf("level %d: %w", i, err)
+		}
+		for _, ga := range Err(err).Value.Group() {
+			if ga.Key != "causes" {
+				continue
+			}
+			if got := len(ga.Value.Any().([]string)); got != causesMaxDepth {
+				t.Errorf("got a different value than the wanted one. expected: %d; got: %d", causesMaxDepth, got)
+			}
+		}
+	})
+	t.Run("errors implementing slog.LogValuer", func(t *testing.T) {
+		attrs := attrsOf(Err(&valuerError{code: 42}))
+		if got, want := attrs["code"], "42"; got != want {
+			t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+		}
+		attrs = attrsOf(Err(valuerScalarError{}))
+		if got, want := attrs["details"], "scalar"; got != want {
+			t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+		}
+	})
+	t.Run("nil error", func(t *testing.T) {
+		if a := Err(nil); !a.Equal(slog.Attr{}) {
+			t.Errorf("expected an empty attribute but got %v", a)
+		}
+	})
+}
+
+// pkgErrorsLike mimics the errors from github.com/pkg/errors.
+type pkgErrorsLike struct {
+	stack pkgErrorsStack
+}
+type pkgErrorsFrame uintptr
+type pkgErrorsStack []pkgErrorsFrame
+
+func (e *pkgErrorsLike) Error() string              { return "pkg errors like" }
+func (e *pkgErrorsLike) StackTrace() pkgErrorsStack { return e.stack }
+
+type valuerError struct {
+	code int
+}
+
+func (e *valuerError) Error() string { return "valuer error" }
+func (e *valuerError) LogValue() slog.Value {
+	return slog.GroupValue(slog.Int("code", e.code))
+}
+
+type valuerScalarError struct{}
+
+func (valuerScalarError) Error() string        { return "valuer scalar error" }
+func (valuerScalarError) LogValue() slog.Value { return slog.StringValue("scalar") }
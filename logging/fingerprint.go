@@ -0,0 +1,204 @@
+package logging
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// The keys of the attributes added by [FingerprintHandler].
+const (
+	FingerprintKey = "fingerprint"
+	OccurrencesKey = "occurrences"
+)
+
+// fingerprints tracks the errors fingerprinted by the handlers, as reported by [TopErrors].
+var fingerprints = newFingerprintRegistry(0)
+
+// ErrorFingerprint describes the occurrences of an error, as identified by its fingerprint.
+type ErrorFingerprint struct {
+	Fingerprint string `json:"fingerprint"`
+	Occurrences uint64 `json:"occurrences"`
+	// Message is the message of the first record with this fingerprint.
+	Message string `json:"message"`
+}
+
+// WithFingerprints overwrites the LOG_FINGERPRINTS env var, enabling the [FingerprintHandler] that keeps
+// track of at most size fingerprints.
+func WithFingerprints(size int) Option {
+	return func(c *config) {
+		c.fingerprints = &size
+	}
+}
+
+// FingerprintHandler returns a handler that identifies the same error logged repeatedly (ie: from a retry loop).
+// For each record at error level (or above), a fingerprint is computed from the message, the "error" attribute
+// and the top frame of the stack, when present (see [StacktraceHandler] and [Err]). The fingerprint is attached
+// under the [FingerprintKey] key, together with the number of its occurrences since the process started, under
+// the [OccurrencesKey] key.
+// The memory is bounded by keeping only the last size fingerprints seen, the least recently seen being evicted.
+// The fingerprints are shared by all the handlers and [TopErrors] reports the most frequent ones. Giving a
+// different size to a new handler resizes the fingerprints kept.
+func FingerprintHandler(next slog.Handler, size int) slog.Handler {
+	fingerprints.resize(size)
+	return &fingerprintHandler{next: next, registry: fingerprints}
+}
+
+// TopErrors returns the n most frequent errors identified by the [FingerprintHandler].
+func TopErrors(n int) []ErrorFingerprint {
+	return fingerprints.top(n)
+}
+
+type fingerprintHandler struct {
+	next     slog.Handler
+	registry *fingerprintRegistry
+	// errAttr is the "error" attribute given through WithAttrs
+	errAttr string
+	grouped bool
+}
+
+func (h *fingerprintHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	return h.next.Enabled(ctx, l)
+}
+
+func (h *fingerprintHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level < slog.LevelError {
+		return h.next.Handle(ctx, r)
+	}
+	errAttr, stack := h.errAttr, ""
+	if !h.grouped {
+		r.Attrs(func(a slog.Attr) bool {
+			switch a.Key {
+			case "error":
+				errAttr, stack = errorParts(a.Value, stack)
+			case StackKey:
+				stack = a.Value.String()
+			}
+			return true
+		})
+	}
+	fp := fingerprint(r.Message, errAttr, topFrame(stack))
+	occurrences := h.registry.add(fp, r.Message)
+	r = r.Clone()
+	r.AddAttrs(slog.String(FingerprintKey, fp), slog.Uint64(OccurrencesKey, occurrences))
+	return h.next.Handle(ctx, r)
+}
+
+func (h *fingerprintHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	errAttr := h.errAttr
+	if !h.grouped {
+		for _, a := range attrs {
+			if a.Key == "error" {
+				errAttr, _ = errorParts(a.Value, "")
+			}
+		}
+	}
+	return &fingerprintHandler{next: h.next.WithAttrs(attrs), registry: h.registry, errAttr: errAttr, grouped: h.grouped}
+}
+
+func (h *fingerprintHandler) WithGroup(name string) slog.Handler {
+	return &fingerprintHandler{next: h.next.WithGroup(name), registry: h.registry, errAttr: h.errAttr, grouped: true}
+}
+
+// errorParts returns the message and the stack of an "error" attribute, supporting the group created by [Err].
+func errorParts(v slog.Value, stack string) (string, string) {
+	v = v.Resolve()
+	if v.Kind() != slog.KindGroup {
+		return v.String(), stack
+	}
+	var msg string
+	for _, a := range v.Group() {
+		switch a.Key {
+		case "message":
+			msg = a.Value.String()
+		case "stack":
+			stack = a.Value.String()
+		}
+	}
+	return msg, stack
+}
+
+// topFrame returns the first frame (function and location) of a stack formatted by [formatStack].
+func topFrame(stack string) string {
+	lines := strings.SplitN(stack, "\n", 3)
+	return strings.Join(lines[:min(len(lines), 2)], "\n")
+}
+
+func fingerprint(parts ...string) string {
+	h := fnv.New64a()
+	for _, p := range parts {
+		_, _ = h.Write([]byte(p))
+		_, _ = h.Write([]byte{0})
+	}
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// fingerprintRegistry is an LRU of the fingerprints with their occurrences.
+type fingerprintRegistry struct {
+	m       sync.Mutex
+	size    int
+	lru     *list.List // of *ErrorFingerprint, the most recently seen at the front
+	entries map[string]*list.Element
+}
+
+func newFingerprintRegistry(size int) *fingerprintRegistry {
+	return &fingerprintRegistry{
+		size:    size,
+		lru:     list.New(),
+		entries: map[string]*list.Element{},
+	}
+}
+
+func (r *fingerprintRegistry) resize(size int) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.size = size
+	r.evict()
+}
+
+// add counts an occurrence of the fingerprint, returning the number of its occurrences.
+func (r *fingerprintRegistry) add(fp, msg string) uint64 {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if e, ok := r.entries[fp]; ok {
+		r.lru.MoveToFront(e)
+		f := e.Value.(*ErrorFingerprint)
+		f.Occurrences++
+		return f.Occurrences
+	}
+	r.entries[fp] = r.lru.PushFront(&ErrorFingerprint{Fingerprint: fp, Occurrences: 1, Message: msg})
+	r.evict()
+	return 1
+}
+
+func (r *fingerprintRegistry) evict() {
+	for r.lru.Len() > max(r.size, 0) {
+		e := r.lru.Back()
+		r.lru.Remove(e)
+		delete(r.entries, e.Value.(*ErrorFingerprint).Fingerprint)
+	}
+}
+
+func (r *fingerprintRegistry) top(n int) []ErrorFingerprint {
+	r.m.Lock()
+	res := make([]ErrorFingerprint, 0, r.lru.Len())
+	for e := r.lru.Front(); e != nil; e = e.Next() {
+		res = append(res, *e.Value.(*ErrorFingerprint))
+	}
+	r.m.Unlock()
+	slices.SortStableFunc(res, func(a, b ErrorFingerprint) int {
+		switch {
+		case a.Occurrences > b.Occurrences:
+			return -1
+		case a.Occurrences < b.Occurrences:
+			return 1
+		}
+		return 0
+	})
+	return res[:min(max(n, 0), len(res))]
+}
@@ -0,0 +1,136 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestFingerprintHandler(t *testing.T) {
+	records := func(t *testing.T, b *bytes.Buffer) []map[string]any {
+		t.Helper()
+		var res []map[string]any
+		for _, line := range strings.Split(strings.TrimSpace(b.String()), "\n") {
+			var rec map[string]any
+			if err := json.Unmarshal([]byte(line), &rec); err != nil {
+				t.Fatalf("failed to parse the record %q: %s", line, err)
+			}
+			res = append(res, rec)
+		}
+		return res
+	}
+	t.Run("same error gets the same fingerprint and counts the occurrences", func(t *testing.T) {
+		fingerprints = newFingerprintRegistry(0)
+		var b bytes.Buffer
+		l := slog.New(FingerprintHandler(slog.NewJSONHandler(&b, nil), 10))
+		for range 3 {
+			l.Error("failed to connect", "error", errors.New("connection refused"))
+		}
+		l.Error("failed to connect", "error", errors.New("timeout"))
+		l.Warn("warn log here")
+
+		recs := records(t, &b)
+		if len(recs) != 5 {
+			t.Fatalf("expected 5 records but got:\n%s", b.String())
+		}
+		for i, want := range []float64{1, 2, 3} {
+			if got := recs[i][OccurrencesKey]; got != want {
+				t.Errorf("got a different value than the wanted one. expected: %v; got: %v", want, got)
+			}
+			if recs[i][FingerprintKey] != recs[0][FingerprintKey] {
+				t.Errorf("expected the same fingerprint for the same error but got %v and %v", recs[0][FingerprintKey], recs[i][FingerprintKey])
+			}
+		}
+		if recs[3][FingerprintKey] == recs[0][FingerprintKey] {
+			t.Errorf("expected a different fingerprint for a different error but got %v", recs[3][FingerprintKey])
+		}
+		if got := recs[3][OccurrencesKey]; got != float64(1) {
+			t.Errorf("got a different value than the wanted one. expected: %v; got: %v", 1, got)
+		}
+		if _, ok := recs[4][FingerprintKey]; ok {
+			t.Errorf("expected no fingerprint below the error level but got: %v", recs[4])
+		}
+	})
+	t.Run("error given through With and through Err", func(t *testing.T) {
+		fingerprints = newFingerprintRegistry(0)
+		var b bytes.Buffer
+		l := slog.New(FingerprintHandler(slog.NewJSONHandler(&b, nil), 10))
+		err := errors.New("connection refused")
+		l.With("error", err).Error("failed to connect")
+		l.Error("failed to connect", "error", err)
+		l.Error("failed to connect", Err(err))
+
+		recs := records(t, &b)
+		if recs[0][FingerprintKey] != recs[1][FingerprintKey] || recs[1][FingerprintKey] != recs[2][FingerprintKey] {
+			t.Errorf("expected the same fingerprint for the same error but got: %s", b.String())
+		}
+	})
+	t.Run("TopErrors returns the most frequent errors", func(t *testing.T) {
+		fingerprints = newFingerprintRegistry(0)
+		l := slog.New(FingerprintHandler(noopHandler{}, 10))
+		for i := range 4 {
+			for range i + 1 {
+				l.Error(fmt.Sprintf("error %d", i))
+			}
+		}
+		top := TopErrors(2)
+		if len(top) != 2 {
+			t.Fatalf("expected 2 errors but got: %v", top)
+		}
+		for i, want := range []ErrorFingerprint{{Occurrences: 4, Message: "error 3"}, {Occurrences: 3, Message: "error 2"}} {
+			if top[i].Occurrences != want.Occurrences || top[i].Message != want.Message {
+				t.Errorf("got a different value than the wanted one. expected: %+v; got: %+v", want, top[i])
+			}
+		}
+	})
+	t.Run("least recently seen fingerprints are evicted", func(t *testing.T) {
+		fingerprints = newFingerprintRegistry(0)
+		l := slog.New(FingerprintHandler(noopHandler{}, 2))
+		l.Error("error 1")
+		l.Error("error 2")
+		l.Error("error 1")
+		l.Error("error 3")
+
+		top := TopErrors(10)
+		if len(top) != 2 {
+			t.Fatalf("expected 2 errors but got: %v", top)
+		}
+		for _, e := range top {
+			if e.Message == "error 2" {
+				t.Errorf("expected the least recently seen error to be evicted but got: %v", top)
+			}
+		}
+	})
+	t.Run("LOG_FINGERPRINTS", func(t *testing.T) {
+		fingerprints = newFingerprintRegistry(0)
+		t.Setenv("LOG_FINGERPRINTS", "10")
+		var b bytes.Buffer
+		if _, err := SetupWithWriter(&b); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		slog.Error("error log here")
+		if !strings.Contains(b.String(), FingerprintKey+"=") || !strings.Contains(b.String(), OccurrencesKey+"=1") {
+			t.Errorf("expected the record to be fingerprinted but got: %s", b.String())
+		}
+	})
+	t.Run("disabled by default", func(t *testing.T) {
+		var b bytes.Buffer
+		if _, err := SetupWithWriter(&b); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		slog.Error("error log here")
+		if strings.Contains(b.String(), FingerprintKey+"=") {
+			t.Errorf("expected no fingerprint but got: %s", b.String())
+		}
+	})
+	t.Run("invalid LOG_FINGERPRINTS", func(t *testing.T) {
+		t.Setenv("LOG_FINGERPRINTS", "-1")
+		if _, err := SetupWithWriter(&bytes.Buffer{}); err == nil {
+			t.Errorf("expected an error for the invalid value but got nothing")
+		}
+	})
+}
@@ -0,0 +1,48 @@
+package logging
+
+import (
+	"log/slog"
+	"os"
+	"runtime"
+	"slices"
+)
+
+// The keys of the attributes added by [WithHostAttrs].
+const (
+	HostKey      = "host"
+	PIDKey       = "pid"
+	GoVersionKey = "go_version"
+)
+
+// WithHostAttrs overwrites the LOG_HOST_ATTR env var, configuring if the host, pid and go_version
+// attributes are added to all the records.
+func WithHostAttrs(b bool) Option {
+	return func(c *config) {
+		c.hostAttrs = &b
+	}
+}
+
+// withHostAttrs adds the attributes describing the process to the given ones, when missing.
+// The hostname falls back on "unknown" when it cannot be resolved.
+func withHostAttrs(attrs []slog.Attr) []slog.Attr {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "unknown"
+	}
+	for _, a := range []slog.Attr{
+		slog.String(HostKey, host),
+		slog.Int(PIDKey, os.Getpid()),
+		slog.String(GoVersionKey, runtime.Version()),
+	} {
+		if !slices.ContainsFunc(attrs, func(d slog.Attr) bool { return d.Key == a.Key }) {
+			attrs = append(attrs, a)
+		}
+	}
+	return attrs
+}
+
+// isJSONFormat reports whether the format is rendering JSON, for which the host attributes are enabled by default.
+func isJSONFormat(f Format) bool {
+	_, preset := jsonPresets[f]
+	return f == FormatJSON || preset
+}
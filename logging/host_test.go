@@ -0,0 +1,80 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestHostAttrs(t *testing.T) {
+	t.Run("enabled by default for json", func(t *testing.T) {
+		t.Setenv("LOG_FORMAT", "json")
+		var b bytes.Buffer
+		if _, err := SetupWithWriter(&b); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		slog.Info("info log here")
+
+		var got map[string]any
+		if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+			t.Fatalf("failed to parse the record %q: %s", b.String(), err)
+		}
+		host, _ := os.Hostname()
+		if got[HostKey] != host {
+			t.Errorf("got a different host than the wanted one. expected: %q; got: %v", host, got[HostKey])
+		}
+		if got[PIDKey] != float64(os.Getpid()) {
+			t.Errorf("got a different pid than the wanted one. expected: %d; got: %v", os.Getpid(), got[PIDKey])
+		}
+		if got[GoVersionKey] != runtime.Version() {
+			t.Errorf("got a different go version than the wanted one. expected: %q; got: %v", runtime.Version(), got[GoVersionKey])
+		}
+	})
+	t.Run("disabled by default for text", func(t *testing.T) {
+		var b bytes.Buffer
+		if _, err := SetupWithWriter(&b); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		slog.Info("info log here")
+		if strings.Contains(b.String(), PIDKey+"=") {
+			t.Errorf("expected no host attributes but got: %s", b.String())
+		}
+	})
+	t.Run("LOG_HOST_ATTR=false disables them for json", func(t *testing.T) {
+		t.Setenv("LOG_FORMAT", "json")
+		t.Setenv("LOG_HOST_ATTR", "false")
+		var b bytes.Buffer
+		if _, err := SetupWithWriter(&b); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		slog.Info("info log here")
+		if strings.Contains(b.String(), `"`+PIDKey+`"`) {
+			t.Errorf("expected no host attributes but got: %s", b.String())
+		}
+	})
+	t.Run("LOG_HOST_ATTR=true enables them for text", func(t *testing.T) {
+		t.Setenv("LOG_HOST_ATTR", "true")
+		var b bytes.Buffer
+		if _, err := SetupWithWriter(&b); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		slog.Info("info log here")
+		if !strings.Contains(b.String(), PIDKey+"=") {
+			t.Errorf("expected the host attributes but got: %s", b.String())
+		}
+	})
+	t.Run("defaults given by the user are kept", func(t *testing.T) {
+		var b bytes.Buffer
+		if _, err := SetupWithWriter(&b, WithHostAttrs(true), WithDefaultAttrs(slog.String(HostKey, "pod-1"))); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		slog.Info("info log here")
+		if got := strings.Count(b.String(), HostKey+"="); got != 1 || !strings.Contains(b.String(), "host=pod-1") {
+			t.Errorf("expected the host given by the user but got: %s", b.String())
+		}
+	})
+}
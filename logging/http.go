@@ -0,0 +1,60 @@
+package logging
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// The names of the attributes describing the HTTP activity. These are part of the logs format that the dashboards
+// and the alerts rely on, so these must not be changed.
+const (
+	// HTTPRequestKey is the group holding the attributes returned by [HTTPRequestAttrs].
+	HTTPRequestKey = "request"
+	// HTTPResponseKey is the group holding the attributes returned by [HTTPResponseAttrs].
+	HTTPResponseKey = "response"
+
+	HTTPMethodKey     = "method"
+	HTTPPathKey       = "path"
+	HTTPQueryKey      = "query"
+	HTTPRemoteAddrKey = "remote_addr"
+	HTTPUserAgentKey  = "user_agent"
+	HTTPRequestIDKey  = "request_id"
+
+	HTTPStatusKey     = "status"
+	HTTPBytesKey      = "bytes"
+	HTTPDurationMsKey = "duration_ms"
+)
+
+// HTTPRequestAttrs returns the attributes describing the given request, grouped under [HTTPRequestKey].
+// The requestID is the one correlating the logs of the request (ie: the one read by httpx.GetReqID), since logging
+// cannot read it from the context by itself.
+// The query, the user agent and the request id are present only when these are not empty.
+func HTTPRequestAttrs(r *http.Request, requestID string) slog.Attr {
+	attrs := []any{
+		slog.String(HTTPMethodKey, r.Method),
+		slog.String(HTTPPathKey, r.URL.Path),
+	}
+	if q := r.URL.RawQuery; q != "" {
+		attrs = append(attrs, slog.String(HTTPQueryKey, q))
+	}
+	if ra := r.RemoteAddr; ra != "" {
+		attrs = append(attrs, slog.String(HTTPRemoteAddrKey, ra))
+	}
+	if ua := r.UserAgent(); ua != "" {
+		attrs = append(attrs, slog.String(HTTPUserAgentKey, ua))
+	}
+	if requestID != "" {
+		attrs = append(attrs, slog.String(HTTPRequestIDKey, requestID))
+	}
+	return slog.Group(HTTPRequestKey, attrs...)
+}
+
+// HTTPResponseAttrs returns the attributes describing a response, grouped under [HTTPResponseKey].
+func HTTPResponseAttrs(status, size int, d time.Duration) slog.Attr {
+	return slog.Group(HTTPResponseKey,
+		slog.Int(HTTPStatusKey, status),
+		slog.Int(HTTPBytesKey, size),
+		slog.Float64(HTTPDurationMsKey, float64(d)/float64(time.Millisecond)),
+	)
+}
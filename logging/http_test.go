@@ -0,0 +1,58 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestHTTPAttrs(t *testing.T) {
+	logRecord := func(t *testing.T, attrs ...any) map[string]any {
+		t.Helper()
+		var b bytes.Buffer
+		slog.New(slog.NewJSONHandler(&b, nil)).Info("request", attrs...)
+		var rec map[string]any
+		if err := json.Unmarshal(b.Bytes(), &rec); err != nil {
+			t.Fatalf("failed to parse the record %q: %s", b.String(), err)
+		}
+		return rec
+	}
+	t.Run("request attributes", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/users/1?verbose=true", nil)
+		r.Header.Set("User-Agent", "test-agent")
+
+		rec := logRecord(t, HTTPRequestAttrs(r, "req-123"))
+		want := map[string]any{
+			HTTPMethodKey:     http.MethodPost,
+			HTTPPathKey:       "/users/1",
+			HTTPQueryKey:      "verbose=true",
+			HTTPRemoteAddrKey: "192.0.2.1:1234",
+			HTTPUserAgentKey:  "test-agent",
+			HTTPRequestIDKey:  "req-123",
+		}
+		if got := rec[HTTPRequestKey]; !reflect.DeepEqual(got, want) {
+			t.Errorf("got a different value than the wanted one. expected: %v; got: %v", want, got)
+		}
+	})
+	t.Run("empty values are omitted", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = ""
+		rec := logRecord(t, HTTPRequestAttrs(r, ""))
+		want := map[string]any{HTTPMethodKey: http.MethodGet, HTTPPathKey: "/"}
+		if got := rec[HTTPRequestKey]; !reflect.DeepEqual(got, want) {
+			t.Errorf("got a different value than the wanted one. expected: %v; got: %v", want, got)
+		}
+	})
+	t.Run("response attributes", func(t *testing.T) {
+		rec := logRecord(t, HTTPResponseAttrs(http.StatusCreated, 42, 1500*time.Microsecond))
+		want := map[string]any{HTTPStatusKey: float64(201), HTTPBytesKey: float64(42), HTTPDurationMsKey: 1.5}
+		if got := rec[HTTPResponseKey]; !reflect.DeepEqual(got, want) {
+			t.Errorf("got a different value than the wanted one. expected: %v; got: %v", want, got)
+		}
+	})
+}
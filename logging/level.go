@@ -0,0 +1,71 @@
+package logging
+
+import (
+	"log/slog"
+	"sync"
+
+	"github.com/yottta/go-core/shutdown"
+)
+
+// levelVar is the level used by the handler installed by [Setup] and its variants.
+// Being shared, it allows changing the level at runtime without configuring again the logging.
+var levelVar slog.LevelVar
+
+var (
+	toggleOnce sync.Once
+	toggleM    sync.Mutex
+	// toggledFrom holds the level that was active before toggling to debug. Nil when the toggle is not active.
+	toggledFrom *slog.Level
+)
+
+// SetLevel changes the level of the logger installed by [Setup] at runtime.
+// This is safe to be called concurrently.
+func SetLevel(l slog.Level) {
+	levelVar.Set(l)
+}
+
+// Level returns the current level of the logger installed by [Setup].
+func Level() slog.Level {
+	return levelVar.Level()
+}
+
+// EnableSignalToggle starts listening for syscall.SIGUSR1 and, on each signal received, flips the level between
+// the configured one and debug. This gives the operators live debug logging without a restart.
+// Calling this multiple times has no additional effect. On Windows, where syscall.SIGUSR1 does not exist,
+// this does nothing.
+func EnableSignalToggle() {
+	if len(toggleSignals) == 0 {
+		return
+	}
+	toggleOnce.Do(func() {
+		ch := shutdown.Chan(toggleSignals...)
+		go func() {
+			for range ch {
+				toggleDebug()
+			}
+		}()
+	})
+}
+
+// toggleDebug switches to debug level or restores the level that was active before switching.
+func toggleDebug() {
+	toggleM.Lock()
+	defer toggleM.Unlock()
+	if toggledFrom != nil {
+		SetLevel(*toggledFrom)
+		toggledFrom = nil
+		slog.With("level", Level()).Info("log level restored")
+		return
+	}
+	prev := Level()
+	toggledFrom = &prev
+	SetLevel(slog.LevelDebug)
+	slog.With("previous_level", prev).Info("log level switched to debug")
+}
+
+// resetToggle forgets the level saved by [toggleDebug] since a new setup configures the level again.
+func resetToggle() {
+	toggleM.Lock()
+	defer toggleM.Unlock()
+	toggledFrom = nil
+}
@@ -0,0 +1,37 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+)
+
+func TestSetLevel(t *testing.T) {
+	t.Run("changes the level of the installed logger", func(t *testing.T) {
+		t.Setenv("LOG_LEVEL", "error")
+		var b bytes.Buffer
+		l, _ := SetupWithWriter(&b)
+		if got, want := Level(), slog.LevelError; got != want {
+			t.Fatalf("expected level %s but got %s", want, got)
+		}
+		SetLevel(slog.LevelInfo)
+		if got, want := Level(), slog.LevelInfo; got != want {
+			t.Fatalf("expected level %s but got %s", want, got)
+		}
+		if !l.Enabled(context.Background(), slog.LevelInfo) {
+			t.Errorf("expected the installed logger to use the new level")
+		}
+	})
+	t.Run("concurrent calls", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := range 50 {
+			wg.Go(func() {
+				SetLevel(slog.Level(i % 8))
+				_ = Level()
+			})
+		}
+		wg.Wait()
+	})
+}
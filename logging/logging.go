@@ -1,9 +1,14 @@
 package logging
 
 import (
+	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"os"
+	"slices"
+	"strconv"
+	"sync"
 
 	"github.com/yottta/go-core/env"
 )
@@ -11,36 +16,322 @@ import (
 // Setup is setting up slog with different options
 // This is handling the following env vars:
 // * LOG_LEVEL: vals: debug, info, warn, error. This is controlling the logging level. Default: debug
-// * LOG_FORMAT: vals: text, json. This is controlling the format of the logs. Default: text
+// The level can be configured also per module, for the loggers created with [Named] (ie: info,storage=debug,httpx=warn).
+// * LOG_FORMAT: vals: text, json, pretty, gcp, ecs. This is controlling the format of the logs. The pretty format is
+// meant for the local development and is described in [NewPrettyHandler]. The gcp and ecs formats are JSON formats
+// using the fields expected by the Google Cloud Logging and by the Elastic Common Schema. Default: text
 // * LOG_SOURCE: true, false. This is controlling to include or not the sources of the logs. Default: false
+// * LOG_TRACE_CORRELATION: true, false. This is controlling to add the OpenTelemetry trace and span IDs to the logs. Default: false
+// * LOG_SAMPLING: true, false. This is controlling the rate-limiting of the repeated records with the defaults of
+// [SamplingHandler]. Default: false
+// * LOG_TAIL_ON_ERROR: the number of records below the configured level to keep and write ahead of an error, as
+// described in [TailHandler]. A value of 0 disables it. Default: 0
+// * LOG_STACK_ON_ERROR: true, false. This is controlling to add the stack trace of the caller to the error records,
+// as described in [StacktraceHandler]. Default: false
+// * LOG_DEFAULT_ATTRS: comma separated list of key=value (ie: service=payments,env=prod). These attributes are added
+// to all the records, next to the version of the main module when available. Default: none
+// * LOG_HOST_ATTR: true, false. This is controlling to add the host, pid and go_version attributes to all the records.
+// Default: true for the JSON formats (json, gcp, ecs), false otherwise
+// * LOG_FINGERPRINTS: the number of error fingerprints to keep, as described in [FingerprintHandler]. A value of 0
+// disables the fingerprinting. Default: 0
+// * LOG_TIME_FORMAT: vals: rfc3339, rfc3339nano, unix_ms. This is controlling how the time of the records is rendered.
+// Default: the format of the slog handlers
+// * LOG_KEY_MAPPING: comma separated list of from=to (ie: time=timestamp,msg=message,level=severity). This is renaming
+// the top level keys of the records. Default: none
+// * LOG_OUTPUT: vals: stderr, stdout, discard or the path of a file. This is controlling where the logs are written.
+// When the file cannot be opened, the logs are written to stderr. Default: stderr
+// * LOG_OUTPUTS: comma separated list of destination:format (ie: stderr:text,/var/log/app.json:json). The destination
+// can be stderr, stdout, discard or a file path. When the format is missing, the one from LOG_FORMAT is used. This takes precedence
+// over LOG_OUTPUT. A suffix that is not a known format is kept as part of the file path (ie: C:\logs\app.log).
+// Default: stderr
+// * LOG_MAX_SIZE_MB: the size at which the log files are rotated. A value lower or equal to 0 disables the rotation. Default: 100
+// * LOG_MAX_BACKUPS: the number of rotated log files to keep. Default: 0, meaning all
+// * LOG_MAX_AGE_DAYS: the number of days to keep the rotated log files. Default: 0, meaning forever
+//
+// The log files are reopened when the process receives syscall.SIGHUP, for logrotate compatibility.
+// The records written are counted per level, as reported by [Stats].
+//
+// Setup can be called multiple times. Each call reads again the env vars and replaces the default logger. For keeping
+// the options given to [SetupWith] instead, use [Reload].
+// Any unrecognized value is silently replaced with its default. For reporting these, use [SetupE].
 func Setup() {
-	setupWithWriter(os.Stderr)
+	_, _ = SetupE()
 }
 
-// setupWithWriter is mainly created for testing
-func setupWithWriter(w io.Writer) {
-	level := env.StringWithDefault("LOG_LEVEL", "debug")
-	format := env.StringWithDefault("LOG_FORMAT", "text")
-	addSource := env.BoolWithDefault("LOG_SOURCE", false)
+// SetupE is the same as [Setup] but returns an error describing any unrecognized value of the env vars.
+// Even when an error is returned, the logger configured with the fallback values is installed as default and
+// returned, so the logging still works.
+func SetupE() (*slog.Logger, error) {
+	return SetupWith()
+}
 
-	lvl := &slog.LevelVar{}
-	err := lvl.UnmarshalText([]byte(level))
-	if err != nil {
-		lvl.Set(slog.LevelDebug)
+// SetupWith is the same as [SetupE] but allows configuring the logging programmatically.
+// The precedence of the values is: option > env var > default. Only the env vars that are
+// not overwritten by an [Option] are read and validated.
+func SetupWith(opts ...Option) (*slog.Logger, error) {
+	last.Lock()
+	defer last.Unlock()
+	var c config
+	for _, opt := range opts {
+		opt(&c)
+	}
+	l, err := setup(c)
+	last.opts = slices.Clone(opts)
+	last.logger = l
+	return l, err
+}
+
+// last holds the options and the logger of the latest setup, for [Reload].
+var last struct {
+	sync.Mutex
+	opts   []Option
+	logger *slog.Logger
+}
+
+// Reload configures the logging again, reading again the env vars but keeping the options given to the latest
+// [SetupWith] or [SetupWithWriter]. This is meant for reloading the configuration without a restart (ie: on
+// syscall.SIGHUP), unlike [Setup] which discards these options.
+// When the logging was never set up, or the default logger was replaced since the latest setup (ie: with
+// [slog.SetDefault]), nothing is changed and the current default logger is returned.
+func Reload() (*slog.Logger, error) {
+	last.Lock()
+	opts, l := last.opts, last.logger
+	last.Unlock()
+	if l == nil || slog.Default() != l {
+		return slog.Default(), nil
+	}
+	return SetupWith(opts...)
+}
+
+// SetupWithWriter is the same as [SetupWith] but writes the logs to the given writer, ignoring the
+// LOG_OUTPUT and LOG_OUTPUTS env vars.
+func SetupWithWriter(w io.Writer, opts ...Option) (*slog.Logger, error) {
+	return SetupWith(append(opts, WithWriter(w))...)
+}
+
+func setup(c config) (*slog.Logger, error) {
+	var errs []error
+	if c.addSource == nil {
+		addSource, err := boolEnv("LOG_SOURCE")
+		if err != nil {
+			errs = append(errs, err)
+		}
+		c.addSource = &addSource
+	}
+
+	if c.traceCorrelation == nil {
+		traceCorrelation, err := boolEnv("LOG_TRACE_CORRELATION")
+		if err != nil {
+			errs = append(errs, err)
+		}
+		c.traceCorrelation = &traceCorrelation
+	}
+
+	if c.sampling == nil {
+		sampling, err := boolEnv("LOG_SAMPLING")
+		if err != nil {
+			errs = append(errs, err)
+		}
+		c.sampling = &sampling
+	}
+
+	if c.tailOnError == nil {
+		tail, err := intEnv("LOG_TAIL_ON_ERROR")
+		if err != nil {
+			errs = append(errs, err)
+		}
+		c.tailOnError = &tail
+	}
+
+	if c.fingerprints == nil {
+		fingerprints, err := intEnv("LOG_FINGERPRINTS")
+		if err != nil {
+			errs = append(errs, err)
+		}
+		c.fingerprints = &fingerprints
+	}
+
+	if c.stacktraces == nil {
+		stackOnError, err := boolEnv("LOG_STACK_ON_ERROR")
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if stackOnError {
+			l := slog.LevelError
+			c.stacktraces = &l
+		}
+	}
+
+	if c.defaultAttrs == nil {
+		defaultAttrs, err := defaultAttrsFromEnv(env.String("LOG_DEFAULT_ATTRS"))
+		if err != nil {
+			errs = append(errs, err)
+		}
+		c.defaultAttrs = defaultAttrs
+	}
+	c.defaultAttrs = withBuildVersion(c.defaultAttrs)
+
+	resetToggle()
+	modules := map[string]slog.Level{}
+	if c.level != nil {
+		levelVar.Set(*c.level)
+	} else {
+		level := env.StringWithDefault("LOG_LEVEL", "debug")
+		def, m, err := parseLevelSpec(level)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid LOG_LEVEL %q: expected one of debug, info, warn, error, optionally followed by module=level entries", level))
+		} else {
+			modules = m
+		}
+		if def == nil {
+			def = new(slog.Level)
+			*def = slog.LevelDebug
+		}
+		levelVar.Set(*def)
+	}
+	moduleLevels.set(modules)
+
+	formatSource := "format given through WithFormat"
+	if c.format == nil {
+		format := Format(env.StringWithDefault("LOG_FORMAT", string(FormatText)))
+		c.format = &format
+		formatSource = "LOG_FORMAT"
+	}
+
+	if c.replaceAttr == nil {
+		replaceAttr, err := replaceAttrFromEnv(env.String("LOG_TIME_FORMAT"), env.String("LOG_KEY_MAPPING"))
+		if err != nil {
+			errs = append(errs, err)
+		}
+		c.replaceAttr = replaceAttr
 	}
 
 	opts := slog.HandlerOptions{
-		AddSource: addSource,
-		Level:     lvl,
+		AddSource:   *c.addSource,
+		Level:       minLevel, // the leveling is done by the module handler
+		ReplaceAttr: c.replaceAttr,
+	}
+	if !validFormat(*c.format) {
+		errs = append(errs, fmt.Errorf("invalid %s %q: expected one of text, json, pretty, gcp, ecs", formatSource, *c.format))
+		*c.format = FormatText
+	}
+	if c.hostAttrs == nil {
+		hostAttrs := isJSONFormat(*c.format)
+		if env.String("LOG_HOST_ATTR") != "" {
+			var err error
+			if hostAttrs, err = boolEnv("LOG_HOST_ATTR"); err != nil {
+				errs = append(errs, err)
+				hostAttrs = isJSONFormat(*c.format)
+			}
+		}
+		c.hostAttrs = &hostAttrs
+	}
+	if *c.hostAttrs {
+		c.defaultAttrs = withHostAttrs(c.defaultAttrs)
 	}
 	var h slog.Handler
-	switch format {
-	case "text":
-		h = slog.NewTextHandler(w, &opts)
-	case "json":
-		h = slog.NewJSONHandler(w, &opts)
-	default:
-		h = slog.NewTextHandler(w, &opts)
-	}
-	slog.SetDefault(slog.New(h))
+	var files []*logFile
+	var outputErr error
+	if outputs := env.String("LOG_OUTPUTS"); c.writer == nil && outputs != "" {
+		h, files, outputErr = outputsHandler(outputs, *c.format, &opts)
+	} else if output := env.String("LOG_OUTPUT"); c.writer == nil && output != "" {
+		if w, ok := standardWriter(output); ok {
+			h = newHandler(w, *c.format, &opts)
+		} else if f, err := openLogFile(output); err != nil {
+			outputErr = fmt.Errorf("invalid LOG_OUTPUT %q: %w", output, err)
+		} else {
+			files = append(files, f)
+			h = newHandler(f, *c.format, &opts)
+		}
+	}
+	if h == nil {
+		w := c.writer
+		if w == nil {
+			w = os.Stderr
+		}
+		h = newHandler(w, *c.format, &opts)
+	}
+	h = newModuleHandler(StatsHandler(h))
+	if len(c.defaultAttrs) > 0 {
+		h = DefaultAttrsHandler(h, c.defaultAttrs...)
+	}
+	// the handlers added after this see the records before the dedup holds them, so the fingerprints count all the
+	// occurrences and the stack, the trace ids and the tail are taken when the record is emitted, not at the end of
+	// the window
+	if c.errorDedupWindow > 0 {
+		h = ErrorDedupHandler(h, c.errorDedupWindow)
+	}
+	if *c.fingerprints > 0 {
+		h = FingerprintHandler(h, *c.fingerprints)
+	}
+	if c.stacktraces != nil {
+		h = StacktraceHandler(h, *c.stacktraces)
+	}
+	if *c.tailOnError > 0 {
+		h = TailHandler(h, *c.tailOnError)
+	}
+	if *c.traceCorrelation {
+		h = WithTraceContext(h)
+	}
+	if *c.sampling {
+		h = SamplingHandler(h, SamplingOptions{})
+	}
+	l := slog.New(h)
+	slog.SetDefault(l)
+	reapplyStdLogRedirect()
+	replaceOpenedFiles(files)
+	if outputErr != nil {
+		l.With("error", outputErr).Warn("not all the log outputs could be configured, falling back on stderr when none is available")
+		errs = append(errs, outputErr)
+	}
+	return l, errors.Join(errs...)
+}
+
+func validFormat(f Format) bool {
+	if _, ok := jsonPresets[f]; ok {
+		return true
+	}
+	return f == FormatText || f == FormatJSON || f == FormatPretty
+}
+
+func newHandler(w io.Writer, f Format, opts *slog.HandlerOptions) slog.Handler {
+	switch f {
+	case FormatJSON:
+		return slog.NewJSONHandler(w, opts)
+	case FormatPretty:
+		return NewPrettyHandler(w, opts)
+	}
+	if p, ok := jsonPresets[f]; ok {
+		presetOpts := *opts
+		presetOpts.ReplaceAttr = p.replaceAttr(opts.ReplaceAttr)
+		return slog.NewJSONHandler(w, &presetOpts)
+	}
+	return slog.NewTextHandler(w, opts)
+}
+
+// boolEnv reads the env var as a bool, returning false when it's not set.
+// Unlike env.Bool, this returns an error for the invalid values.
+func boolEnv(k string) (bool, error) {
+	v := env.String(k)
+	if v == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s %q: expected a bool", k, v)
+	}
+	return b, nil
+}
+
+// intEnv reads the env var as a non-negative int, returning 0 when it's not set.
+func intEnv(k string) (int, error) {
+	v := env.String(k)
+	if v == "" {
+		return 0, nil
+	}
+	i, err := strconv.Atoi(v)
+	if err != nil || i < 0 {
+		return 0, fmt.Errorf("invalid %s %q: expected a non-negative integer", k, v)
+	}
+	return i, nil
 }
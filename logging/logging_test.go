@@ -28,7 +28,7 @@ func TestSetup(t *testing.T) {
 			t.Run(lvl, func(t *testing.T) {
 				t.Setenv("LOG_LEVEL", lvl)
 				var b bytes.Buffer
-				setupWithWriter(&b)
+				SetupWithWriter(&b)
 				writeAllLevelLogs()
 				wantD, wantI, wantW, wantE := genWantErrors(lvl)
 				assertLogs(t, b.String(), wantD, wantI, wantW, wantE)
@@ -39,7 +39,7 @@ func TestSetup(t *testing.T) {
 		t.Run("text", func(t *testing.T) {
 			t.Setenv("LOG_FORMAT", "text")
 			var b bytes.Buffer
-			setupWithWriter(&b)
+			SetupWithWriter(&b)
 			writeAllLevelLogs()
 			t.Logf("content: %s", b.String())
 			if content := b.String(); strings.Contains(content, "{") {
@@ -49,7 +49,7 @@ func TestSetup(t *testing.T) {
 		t.Run("json", func(t *testing.T) {
 			t.Setenv("LOG_FORMAT", "json")
 			var b bytes.Buffer
-			setupWithWriter(&b)
+			SetupWithWriter(&b)
 			writeAllLevelLogs()
 			t.Logf("content: %s", b.String())
 			if content := b.String(); !strings.Contains(content, "{") {
@@ -59,7 +59,7 @@ func TestSetup(t *testing.T) {
 		t.Run("wrong format", func(t *testing.T) {
 			t.Setenv("LOG_FORMAT", "wrong")
 			var b bytes.Buffer
-			setupWithWriter(&b)
+			SetupWithWriter(&b)
 			writeAllLevelLogs()
 			t.Logf("content: %s", b.String())
 			if content := b.String(); strings.Contains(content, "{") {
@@ -72,7 +72,7 @@ func TestSetup(t *testing.T) {
 		t.Run("w/o source", func(t *testing.T) {
 			t.Setenv("LOG_SOURCE", "false")
 			var b bytes.Buffer
-			setupWithWriter(&b)
+			SetupWithWriter(&b)
 			writeAllLevelLogs()
 			t.Logf("content: %s", b.String())
 			if content := b.String(); strings.Contains(content, "source=") {
@@ -82,7 +82,7 @@ func TestSetup(t *testing.T) {
 		t.Run("with source", func(t *testing.T) {
 			t.Setenv("LOG_SOURCE", "true")
 			var b bytes.Buffer
-			setupWithWriter(&b)
+			SetupWithWriter(&b)
 			writeAllLevelLogs()
 			t.Logf("content: %s", b.String())
 			if content := b.String(); !strings.Contains(content, "source=") {
@@ -114,3 +114,76 @@ func assertLogs(t *testing.T, output string, debug, info, warn, error bool) {
 	checkLogLevel(t, warn, "warn")
 	checkLogLevel(t, error, "error")
 }
+
+func TestSetupE(t *testing.T) {
+	t.Run("valid values", func(t *testing.T) {
+		t.Setenv("LOG_LEVEL", "info")
+		t.Setenv("LOG_FORMAT", "json")
+		t.Setenv("LOG_SOURCE", "true")
+		var b bytes.Buffer
+		l, err := SetupWithWriter(&b)
+		if err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		if l != slog.Default() {
+			t.Errorf("expected the returned logger to be installed as default")
+		}
+	})
+	t.Run("invalid values are reported and fallback installed", func(t *testing.T) {
+		t.Setenv("LOG_LEVEL", "verbose")
+		t.Setenv("LOG_FORMAT", "jsn")
+		t.Setenv("LOG_SOURCE", "yes please")
+		var b bytes.Buffer
+		l, err := SetupWithWriter(&b)
+		if err == nil {
+			t.Fatalf("expected an error but got nothing")
+		}
+		for _, want := range []string{`LOG_LEVEL "verbose"`, `LOG_FORMAT "jsn"`, `LOG_SOURCE "yes please"`} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("expected error to contain %q but got %q", want, err.Error())
+			}
+		}
+		if l == nil || l != slog.Default() {
+			t.Fatalf("expected the fallback logger to be returned and installed as default")
+		}
+		writeAllLevelLogs()
+		assertLogs(t, b.String(), true, true, true, true)
+		if content := b.String(); strings.Contains(content, "{") {
+			t.Errorf("expected the fallback text format but got json content: %s", content)
+		}
+	})
+}
+
+func TestReload(t *testing.T) {
+	t.Run("keeps the options and reads again the env vars", func(t *testing.T) {
+		t.Setenv("LOG_LEVEL", "error")
+		var b bytes.Buffer
+		SetupWithWriter(&b, WithFormat(FormatJSON))
+		t.Setenv("LOG_LEVEL", "info")
+		l, err := Reload()
+		if err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		if l != slog.Default() {
+			t.Errorf("expected the returned logger to be installed as default")
+		}
+		writeAllLevelLogs()
+		assertLogs(t, b.String(), false, true, true, true)
+		if content := b.String(); !strings.Contains(content, "{") {
+			t.Errorf("expected the json format to be kept but got: %s", content)
+		}
+	})
+	t.Run("keeps a default logger replaced since the setup", func(t *testing.T) {
+		SetupWithWriter(&bytes.Buffer{})
+		var b bytes.Buffer
+		custom := slog.New(slog.NewTextHandler(&b, nil))
+		slog.SetDefault(custom)
+		l, err := Reload()
+		if err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		if l != custom || slog.Default() != custom {
+			t.Errorf("expected the replaced default logger to be kept")
+		}
+	})
+}
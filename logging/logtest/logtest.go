@@ -0,0 +1,172 @@
+// Package logtest provides a [slog.Handler] capturing the records in memory, to assert on the
+// structured logs in tests instead of on their rendered text.
+package logtest
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Record is a captured [slog.Record] with its attributes flattened.
+// The attributes of the groups are keyed by their path joined with dots (ie: "http.method"), including the
+// attributes and the groups configured on the logger through [slog.Logger.With] and [slog.Logger.WithGroup].
+type Record struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+	Attrs   map[string]slog.Value
+}
+
+// Handler captures all the records that it receives, at any level.
+// It's safe for concurrent use and the handlers derived from it with WithAttrs and WithGroup
+// record in the same place.
+type Handler struct {
+	store *store
+
+	attrs  map[string]slog.Value
+	groups []string
+}
+
+type store struct {
+	m       sync.Mutex
+	records []Record
+}
+
+// NewHandler returns a new capturing handler.
+func NewHandler() *Handler {
+	return &Handler{store: &store{}}
+}
+
+// Install configures a new capturing handler as the default logger for the lifetime of the test,
+// restoring the previous default logger in t.Cleanup.
+// Since [slog.Default] is global, this cannot be used by tests running with t.Parallel. For those, create
+// a logger with [NewHandler] and pass it explicitly to the code under test.
+func Install(t testing.TB) *Handler {
+	t.Helper()
+	h := NewHandler()
+	prev := slog.Default()
+	slog.SetDefault(slog.New(h))
+	t.Cleanup(func() {
+		slog.SetDefault(prev)
+	})
+	return h
+}
+
+func (h *Handler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+func (h *Handler) Handle(_ context.Context, r slog.Record) error {
+	rec := Record{
+		Time:    r.Time,
+		Level:   r.Level,
+		Message: r.Message,
+		Attrs:   make(map[string]slog.Value, len(h.attrs)+r.NumAttrs()),
+	}
+	for k, v := range h.attrs {
+		rec.Attrs[k] = v
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		addAttr(rec.Attrs, h.groups, a)
+		return true
+	})
+	h.store.m.Lock()
+	defer h.store.m.Unlock()
+	h.store.records = append(h.store.records, rec)
+	return nil
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.attrs = make(map[string]slog.Value, len(h.attrs)+len(attrs))
+	for k, v := range h.attrs {
+		h2.attrs[k] = v
+	}
+	for _, a := range attrs {
+		addAttr(h2.attrs, h.groups, a)
+	}
+	return &h2
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.groups = append(slices.Clip(h.groups), name)
+	return &h2
+}
+
+// Records returns all the records captured so far, in the order in which they were handled.
+func (h *Handler) Records() []Record {
+	h.store.m.Lock()
+	defer h.store.m.Unlock()
+	return slices.Clone(h.store.records)
+}
+
+// FilterByLevel returns the captured records at exactly the given level.
+func (h *Handler) FilterByLevel(l slog.Level) []Record {
+	var res []Record
+	for _, r := range h.Records() {
+		if r.Level == l {
+			res = append(res, r)
+		}
+	}
+	return res
+}
+
+// ContainsMessage reports whether any of the captured records has the given message.
+func (h *Handler) ContainsMessage(msg string) bool {
+	_, ok := h.find(msg)
+	return ok
+}
+
+// AttrsFor returns the attributes of the first captured record with the given message,
+// or nil when there is no such record.
+func (h *Handler) AttrsFor(msg string) map[string]slog.Value {
+	r, ok := h.find(msg)
+	if !ok {
+		return nil
+	}
+	return r.Attrs
+}
+
+// Reset discards all the captured records.
+func (h *Handler) Reset() {
+	h.store.m.Lock()
+	defer h.store.m.Unlock()
+	h.store.records = nil
+}
+
+func (h *Handler) find(msg string) (Record, bool) {
+	for _, r := range h.Records() {
+		if r.Message == msg {
+			return r, true
+		}
+	}
+	return Record{}, false
+}
+
+// addAttr flattens the attribute into the given map, following the same rules as the slog handlers:
+// the empty attributes are ignored and the groups without a key are inlined.
+func addAttr(m map[string]slog.Value, groups []string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			groups = append(slices.Clip(groups), a.Key)
+		}
+		for _, ga := range a.Value.Group() {
+			addAttr(m, groups, ga)
+		}
+		return
+	}
+	m[strings.Join(append(slices.Clip(groups), a.Key), ".")] = a.Value
+}
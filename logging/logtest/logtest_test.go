@@ -0,0 +1,66 @@
+package logtest
+
+import (
+	"log/slog"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	t.Run("captures structured records", func(t *testing.T) {
+		h := NewHandler()
+		l := slog.New(h)
+		l.Debug("debug log here")
+		l.With("request.id", "abc").WithGroup("http").Info("request served", "status", 200, slog.Group("client", "ip", "127.0.0.1"))
+		l.Error("query failed", "error", "timeout")
+
+		if got, want := len(h.Records()), 3; got != want {
+			t.Fatalf("got a different number of records than the wanted one. expected: %d; got: %d", want, got)
+		}
+		if !h.ContainsMessage("debug log here") {
+			t.Errorf("expected the debug record to be captured")
+		}
+		if h.ContainsMessage("missing") {
+			t.Errorf("expected no record with the missing message")
+		}
+		errs := h.FilterByLevel(slog.LevelError)
+		if len(errs) != 1 || errs[0].Message != "query failed" {
+			t.Errorf("expected only the error record but got: %v", errs)
+		}
+
+		attrs := h.AttrsFor("request served")
+		want := map[string]any{
+			"request.id":     "abc",
+			"http.status":    int64(200),
+			"http.client.ip": "127.0.0.1",
+		}
+		if len(attrs) != len(want) {
+			t.Errorf("got different attributes than the wanted ones. expected: %v; got: %v", want, attrs)
+		}
+		for k, v := range want {
+			if got := attrs[k].Any(); got != v {
+				t.Errorf("got a different value for %q than the wanted one. expected: %v; got: %v", k, v, got)
+			}
+		}
+		if h.AttrsFor("missing") != nil {
+			t.Errorf("expected no attributes for a missing message")
+		}
+
+		h.Reset()
+		if got := len(h.Records()); got != 0 {
+			t.Errorf("expected no records after reset but got %d", got)
+		}
+	})
+	t.Run("install swaps the default logger", func(t *testing.T) {
+		prev := slog.Default()
+		t.Run("installed", func(t *testing.T) {
+			h := Install(t)
+			slog.Info("through default", "key", "value")
+			if got := h.AttrsFor("through default")["key"].String(); got != "value" {
+				t.Errorf("got a different value than the wanted one. expected: %q; got: %q", "value", got)
+			}
+		})
+		if slog.Default() != prev {
+			t.Errorf("expected the default logger to be restored after the test")
+		}
+	})
+}
@@ -0,0 +1,221 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// LoggerKey is the attribute that holds the name of the module of a logger, as set by [Named].
+const LoggerKey = "logger"
+
+// minLevel is the level configured on the slog handlers, since the leveling is done by the module handler.
+const minLevel = slog.Level(-1 << 10)
+
+// moduleLevels holds the levels configured per module.
+var moduleLevels = &levels{modules: map[string]slog.Level{}, names: map[string]struct{}{}}
+
+type levels struct {
+	m       sync.RWMutex
+	modules map[string]slog.Level
+	// names holds the modules of the loggers created with [Named], as reported by [Loggers].
+	names map[string]struct{}
+}
+
+func (l *levels) set(modules map[string]slog.Level) {
+	l.m.Lock()
+	defer l.m.Unlock()
+	l.modules = modules
+}
+
+func (l *levels) setModule(module string, lvl slog.Level) {
+	l.m.Lock()
+	defer l.m.Unlock()
+	l.modules[module] = lvl
+}
+
+func (l *levels) register(module string) {
+	l.m.RLock()
+	_, ok := l.names[module]
+	l.m.RUnlock()
+	if ok {
+		return
+	}
+	l.m.Lock()
+	defer l.m.Unlock()
+	l.names[module] = struct{}{}
+}
+
+// level returns the level of the given module, falling back on the default level.
+func (l *levels) level(module string) slog.Level {
+	if module != "" {
+		l.m.RLock()
+		lvl, ok := l.modules[module]
+		l.m.RUnlock()
+		if ok {
+			return lvl
+		}
+	}
+	return levelVar.Level()
+}
+
+// min returns the lowest level among the default level and the levels of the modules.
+func (l *levels) min() slog.Level {
+	res := levelVar.Level()
+	l.m.RLock()
+	defer l.m.RUnlock()
+	for _, lvl := range l.modules {
+		res = min(res, lvl)
+	}
+	return res
+}
+
+// NamedLevel is the level resolved for the loggers of a module, as reported by [Loggers].
+type NamedLevel struct {
+	Name  string     `json:"name"`
+	Level slog.Level `json:"level"`
+	// Explicit is true when the level is configured for the module, and false when the default level is used.
+	Explicit bool `json:"explicit"`
+}
+
+// Named returns a logger derived from [slog.Default] having the [LoggerKey] attribute set to the given name.
+// The level of the returned logger can be configured separately (ie: LOG_LEVEL=info,storage=debug) or at
+// runtime with [SetNamedLevel]. The name is registered to be reported by [Loggers].
+func Named(name string) *slog.Logger {
+	moduleLevels.register(name)
+	return slog.Default().With(LoggerKey, name)
+}
+
+// SetNamedLevel changes at runtime the level of the loggers of the given module, without affecting the others.
+// The module does not need to have loggers already, the level applies also to the ones created later with [Named].
+// This is safe to be called concurrently.
+func SetNamedLevel(name string, l slog.Level) {
+	moduleLevels.setModule(name, l)
+}
+
+// Loggers returns the modules of the loggers created with [Named] and the ones having a level configured, sorted by
+// name, together with their current level.
+func Loggers() []NamedLevel {
+	moduleLevels.m.RLock()
+	defer moduleLevels.m.RUnlock()
+	def := levelVar.Level()
+	var res []NamedLevel
+	for name := range moduleLevels.names {
+		if _, ok := moduleLevels.modules[name]; !ok {
+			res = append(res, NamedLevel{Name: name, Level: def})
+		}
+	}
+	for name, lvl := range moduleLevels.modules {
+		res = append(res, NamedLevel{Name: name, Level: lvl, Explicit: true})
+	}
+	slices.SortFunc(res, func(a, b NamedLevel) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return res
+}
+
+// SetLevelSpec is the same as [SetLevel] but accepts the module syntax used by LOG_LEVEL
+// (ie: info,storage=debug,httpx=warn). The levels of the modules not present in the spec are removed.
+func SetLevelSpec(spec string) error {
+	def, modules, err := parseLevelSpec(spec)
+	if err != nil {
+		return err
+	}
+	if def != nil {
+		SetLevel(*def)
+	}
+	moduleLevels.set(modules)
+	return nil
+}
+
+// ModuleLevels returns a copy of the levels configured per module.
+func ModuleLevels() map[string]slog.Level {
+	moduleLevels.m.RLock()
+	defer moduleLevels.m.RUnlock()
+	return maps.Clone(moduleLevels.modules)
+}
+
+// parseLevelSpec parses a spec like "info,storage=debug". The returned default level is nil when
+// the spec does not contain one.
+func parseLevelSpec(spec string) (*slog.Level, map[string]slog.Level, error) {
+	var def *slog.Level
+	modules := map[string]slog.Level{}
+	for _, e := range strings.Split(spec, ",") {
+		e = strings.TrimSpace(e)
+		if e == "" {
+			continue
+		}
+		module, level, ok := strings.Cut(e, "=")
+		if !ok {
+			level, module = module, ""
+		}
+		var lvl slog.Level
+		if err := lvl.UnmarshalText([]byte(strings.TrimSpace(level))); err != nil {
+			return nil, nil, fmt.Errorf("invalid level %q in %q: expected one of debug, info, warn, error", level, e)
+		}
+		if module = strings.TrimSpace(module); module == "" {
+			def = &lvl
+			continue
+		}
+		modules[module] = lvl
+	}
+	return def, modules, nil
+}
+
+// moduleHandler resolves the level of each record based on the module of the logger, as set by [Named].
+// This is needed since [slog.HandlerOptions.Level] is applied to all the records.
+type moduleHandler struct {
+	next   slog.Handler
+	module string
+	// grouped is true once a group was opened, after which the attributes cannot set the module anymore.
+	grouped bool
+}
+
+func newModuleHandler(next slog.Handler) slog.Handler {
+	return &moduleHandler{next: next}
+}
+
+func (h *moduleHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	if h.module != "" {
+		return l >= moduleLevels.level(h.module) && h.next.Enabled(ctx, l)
+	}
+	// the record might set the module through its own attributes so the lowest level is used here
+	return l >= moduleLevels.min() && h.next.Enabled(ctx, l)
+}
+
+func (h *moduleHandler) Handle(ctx context.Context, r slog.Record) error {
+	module := h.module
+	if module == "" && !h.grouped {
+		r.Attrs(func(a slog.Attr) bool {
+			if a.Key == LoggerKey {
+				module = a.Value.String()
+				return false
+			}
+			return true
+		})
+	}
+	if r.Level < moduleLevels.level(module) && !isTailFlush(ctx) {
+		return nil
+	}
+	return h.next.Handle(ctx, r)
+}
+
+func (h *moduleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	module := h.module
+	if !h.grouped {
+		for _, a := range attrs {
+			if a.Key == LoggerKey {
+				module = a.Value.String()
+			}
+		}
+	}
+	return &moduleHandler{next: h.next.WithAttrs(attrs), module: module, grouped: h.grouped}
+}
+
+func (h *moduleHandler) WithGroup(name string) slog.Handler {
+	return &moduleHandler{next: h.next.WithGroup(name), module: h.module, grouped: true}
+}
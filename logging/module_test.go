@@ -0,0 +1,134 @@
+package logging
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestModuleLevels(t *testing.T) {
+	t.Run("levels per module from env", func(t *testing.T) {
+		t.Setenv("LOG_LEVEL", "info,storage=debug,httpx=warn")
+		var b bytes.Buffer
+		if _, err := SetupWithWriter(&b); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		slog.Debug("root debug")
+		slog.Info("root info")
+		Named("storage").Debug("storage debug")
+		Named("httpx").Info("httpx info")
+		Named("httpx").Warn("httpx warn")
+		slog.Debug("inline storage debug", LoggerKey, "storage")
+		Named("storage").WithGroup("g").With(LoggerKey, "httpx").Debug("grouped storage debug")
+
+		logs := b.String()
+		for _, want := range []string{"root info", "storage debug", "httpx warn", "inline storage debug", "grouped storage debug"} {
+			if !strings.Contains(logs, want) {
+				t.Errorf("expected logs to contain %q but got:\n%s", want, logs)
+			}
+		}
+		for _, notWant := range []string{"root debug", "httpx info"} {
+			if strings.Contains(logs, notWant) {
+				t.Errorf("expected logs to not contain %q but got:\n%s", notWant, logs)
+			}
+		}
+	})
+	t.Run("set level spec at runtime", func(t *testing.T) {
+		t.Setenv("LOG_LEVEL", "info")
+		var b bytes.Buffer
+		if _, err := SetupWithWriter(&b); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		storage := Named("storage")
+		storage.Debug("before spec")
+		if err := SetLevelSpec("warn,storage=debug"); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		storage.Debug("after spec")
+		slog.Info("root info after spec")
+		if got, want := Level(), slog.LevelWarn; got != want {
+			t.Errorf("expected default level %s but got %s", want, got)
+		}
+		if got, want := ModuleLevels()["storage"], slog.LevelDebug; got != want {
+			t.Errorf("expected storage level %s but got %s", want, got)
+		}
+		logs := b.String()
+		if strings.Contains(logs, "before spec") || strings.Contains(logs, "root info after spec") {
+			t.Errorf("expected the records below the level to be dropped but got:\n%s", logs)
+		}
+		if !strings.Contains(logs, "after spec") {
+			t.Errorf("expected the new module level to be applied but got:\n%s", logs)
+		}
+	})
+	t.Run("invalid spec", func(t *testing.T) {
+		if err := SetLevelSpec("info,storage=verbose"); err == nil {
+			t.Errorf("expected an error for an invalid spec")
+		}
+		t.Setenv("LOG_LEVEL", "info,storage=verbose")
+		var b bytes.Buffer
+		if _, err := SetupWithWriter(&b); err == nil || !strings.Contains(err.Error(), "LOG_LEVEL") {
+			t.Errorf("expected an error about LOG_LEVEL but got: %v", err)
+		}
+	})
+}
+
+func TestNamedLevels(t *testing.T) {
+	t.Run("SetNamedLevel affects only the given module", func(t *testing.T) {
+		t.Setenv("LOG_LEVEL", "info")
+		var b bytes.Buffer
+		if _, err := SetupWithWriter(&b); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		ingest := Named("ingest")
+		ingest.Debug("ingest debug before")
+		SetNamedLevel("ingest", slog.LevelDebug)
+		ingest.Debug("ingest debug after")
+		Named("storage").Debug("storage debug")
+		slog.Debug("root debug")
+
+		logs := b.String()
+		if !strings.Contains(logs, "ingest debug after") {
+			t.Errorf("expected logs to contain %q but got:\n%s", "ingest debug after", logs)
+		}
+		for _, notWant := range []string{"ingest debug before", "storage debug", "root debug"} {
+			if strings.Contains(logs, notWant) {
+				t.Errorf("expected logs to not contain %q but got:\n%s", notWant, logs)
+			}
+		}
+	})
+	t.Run("level of an unknown module applies to the loggers created later", func(t *testing.T) {
+		t.Setenv("LOG_LEVEL", "info")
+		var b bytes.Buffer
+		if _, err := SetupWithWriter(&b); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		SetNamedLevel("later", slog.LevelDebug)
+		Named("later").Debug("later debug")
+		if !strings.Contains(b.String(), "later debug") {
+			t.Errorf("expected logs to contain %q but got:\n%s", "later debug", b.String())
+		}
+	})
+	t.Run("Loggers reports the named loggers and their levels", func(t *testing.T) {
+		t.Setenv("LOG_LEVEL", "warn,storage=debug")
+		if _, err := SetupWithWriter(&bytes.Buffer{}); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		Named("reporting")
+		SetNamedLevel("queue", slog.LevelError)
+
+		got := map[string]NamedLevel{}
+		for _, nl := range Loggers() {
+			got[nl.Name] = nl
+		}
+		for _, want := range []NamedLevel{
+			{Name: "queue", Level: slog.LevelError, Explicit: true},
+			{Name: "reporting", Level: slog.LevelWarn},
+			{Name: "storage", Level: slog.LevelDebug, Explicit: true},
+		} {
+			if got[want.Name] != want {
+				t.Errorf("got a different value than the wanted one. expected: %+v; got: %+v", want, got[want.Name])
+			}
+		}
+	})
+}
@@ -0,0 +1,147 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMultiHandler(t *testing.T) {
+	t.Run("forwards respecting each handler level", func(t *testing.T) {
+		var debugBuf, errorBuf bytes.Buffer
+		l := slog.New(MultiHandler(
+			slog.NewTextHandler(&debugBuf, &slog.HandlerOptions{Level: slog.LevelDebug}),
+			slog.NewJSONHandler(&errorBuf, &slog.HandlerOptions{Level: slog.LevelError}),
+		))
+		writeAllLevelLogsWith(l)
+		assertLogs(t, debugBuf.String(), true, true, true, true)
+		assertLogs(t, errorBuf.String(), false, false, false, true)
+	})
+	t.Run("failing handler does not stop the others", func(t *testing.T) {
+		var b bytes.Buffer
+		h := MultiHandler(
+			failingHandler{},
+			slog.NewTextHandler(&b, nil),
+		)
+		err := h.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "info log here", 0))
+		if err == nil {
+			t.Errorf("expected the error of the failing handler to be returned")
+		}
+		if !strings.Contains(b.String(), "info log here") {
+			t.Errorf("expected the record to reach the healthy handler but got:\n%s", b.String())
+		}
+	})
+	t.Run("attrs and groups propagate to all children", func(t *testing.T) {
+		var textBuf, jsonBuf bytes.Buffer
+		l := slog.New(MultiHandler(
+			slog.NewTextHandler(&textBuf, nil),
+			slog.NewJSONHandler(&jsonBuf, nil),
+		))
+		l.With("svc", "api").WithGroup("req").With("id", "1").Info("msg", "path", "/x")
+		l.Info("plain")
+
+		text := textBuf.String()
+		for _, want := range []string{"svc=api", "req.id=1", "req.path=/x"} {
+			if !strings.Contains(text, want) {
+				t.Errorf("expected text logs to contain %q but got:\n%s", want, text)
+			}
+		}
+		if want := `"svc":"api","req":{"id":"1","path":"/x"}`; !strings.Contains(jsonBuf.String(), want) {
+			t.Errorf("expected json logs to contain %q but got:\n%s", want, jsonBuf.String())
+		}
+		lines := strings.Split(strings.TrimSpace(text), "\n")
+		if len(lines) != 2 || strings.Contains(lines[1], "svc=api") {
+			t.Errorf("expected the attrs to not leak into the parent logger but got:\n%s", text)
+		}
+	})
+}
+
+func TestLogOutputs(t *testing.T) {
+	dir := t.TempDir()
+	jsonFile := filepath.Join(dir, "app.json")
+	textFile := filepath.Join(dir, "app.log")
+	t.Setenv("LOG_OUTPUTS", textFile+":text,"+jsonFile+":json")
+	t.Cleanup(func() { replaceOpenedFiles(nil) })
+	if _, err := SetupE(); err != nil {
+		t.Fatalf("expected no error but got: %s", err)
+	}
+	slog.Info("info log here")
+
+	textContent, err := os.ReadFile(textFile)
+	if err != nil {
+		t.Fatalf("failed to read the text output: %s", err)
+	}
+	if got := string(textContent); !strings.Contains(got, "msg=\"info log here\"") {
+		t.Errorf("expected text content but got:\n%s", got)
+	}
+	jsonContent, err := os.ReadFile(jsonFile)
+	if err != nil {
+		t.Fatalf("failed to read the json output: %s", err)
+	}
+	if got := string(jsonContent); !strings.Contains(got, `"msg":"info log here"`) {
+		t.Errorf("expected json content but got:\n%s", got)
+	}
+
+	t.Run("invalid entries are reported", func(t *testing.T) {
+		t.Setenv("LOG_OUTPUTS", "stderr:yaml,"+filepath.Join(dir, "missing", "app.log"))
+		_, err := SetupE()
+		if err == nil {
+			t.Fatalf("expected an error but got nothing")
+		}
+		for _, want := range []string{`"stderr:yaml"`, "missing"} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("expected error to contain %q but got %q", want, err.Error())
+			}
+		}
+	})
+}
+
+func TestParseOutput(t *testing.T) {
+	tests := []struct {
+		entry      string
+		wantDest   string
+		wantFormat Format
+		wantErr    bool
+	}{
+		{entry: "stderr", wantDest: "stderr", wantFormat: FormatText},
+		{entry: "stdout:json", wantDest: "stdout", wantFormat: FormatJSON},
+		{entry: "/var/log/app.json:json", wantDest: "/var/log/app.json", wantFormat: FormatJSON},
+		{entry: `C:\logs\app.log`, wantDest: `C:\logs\app.log`, wantFormat: FormatText},
+		{entry: `C:\logs\app.json:json`, wantDest: `C:\logs\app.json`, wantFormat: FormatJSON},
+		{entry: "stderr:yaml", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.entry, func(t *testing.T) {
+			dest, format, err := parseOutput(tt.entry, FormatText)
+			if gotErr := err != nil; gotErr != tt.wantErr {
+				t.Fatalf("got a different value than the wanted one. expected error: %t; got: %v", tt.wantErr, err)
+			}
+			if dest != tt.wantDest {
+				t.Errorf("got a different value than the wanted one. expected: %q; got: %q", tt.wantDest, dest)
+			}
+			if format != tt.wantFormat {
+				t.Errorf("got a different value than the wanted one. expected: %q; got: %q", tt.wantFormat, format)
+			}
+		})
+	}
+}
+
+func writeAllLevelLogsWith(l *slog.Logger) {
+	l.Debug("debug log here")
+	l.Info("info log here")
+	l.Warn("warn log here")
+	l.Error("error log here")
+}
+
+type failingHandler struct{}
+
+func (failingHandler) Enabled(context.Context, slog.Level) bool  { return true }
+func (failingHandler) Handle(context.Context, slog.Record) error { return errors.New("write failed") }
+func (h failingHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h failingHandler) WithGroup(string) slog.Handler           { return h }
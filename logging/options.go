@@ -0,0 +1,103 @@
+package logging
+
+import (
+	"io"
+	"log/slog"
+	"time"
+)
+
+// Format is the format in which the logs are written.
+type Format string
+
+const (
+	FormatText Format = "text"
+	FormatJSON Format = "json"
+	// FormatPretty is meant for the local development. See [NewPrettyHandler].
+	FormatPretty Format = "pretty"
+	// FormatGCP is the JSON format with the fields expected by the Google Cloud Logging.
+	FormatGCP Format = "gcp"
+	// FormatECS is the JSON format with the fields of the Elastic Common Schema.
+	FormatECS Format = "ecs"
+)
+
+// config holds the values given through [Option]. A nil field means that the value was not
+// given and it's read from the env vars instead.
+type config struct {
+	level     *slog.Level
+	format    *Format
+	writer    io.Writer
+	addSource *bool
+
+	traceCorrelation *bool
+	sampling         *bool
+	tailOnError      *int
+	stacktraces      *slog.Level
+	defaultAttrs     []slog.Attr
+	hostAttrs        *bool
+	fingerprints     *int
+
+	replaceAttr func(groups []string, a slog.Attr) slog.Attr
+
+	errorDedupWindow time.Duration
+}
+
+// Option configures the logging programmatically when used with [SetupWith].
+// The values given through options take precedence over the env vars.
+type Option func(*config)
+
+// WithLevel overwrites the LOG_LEVEL env var.
+func WithLevel(l slog.Level) Option {
+	return func(c *config) {
+		c.level = &l
+	}
+}
+
+// WithFormat overwrites the LOG_FORMAT env var.
+func WithFormat(f Format) Option {
+	return func(c *config) {
+		c.format = &f
+	}
+}
+
+// WithWriter configures where the logs are written. Default: os.Stderr
+func WithWriter(w io.Writer) Option {
+	return func(c *config) {
+		c.writer = w
+	}
+}
+
+// WithSource overwrites the LOG_SOURCE env var.
+func WithSource(b bool) Option {
+	return func(c *config) {
+		c.addSource = &b
+	}
+}
+
+// WithTraceCorrelation overwrites the LOG_TRACE_CORRELATION env var.
+func WithTraceCorrelation(b bool) Option {
+	return func(c *config) {
+		c.traceCorrelation = &b
+	}
+}
+
+// WithSampling overwrites the LOG_SAMPLING env var.
+func WithSampling(b bool) Option {
+	return func(c *config) {
+		c.sampling = &b
+	}
+}
+
+// WithTailOnError overwrites the LOG_TAIL_ON_ERROR env var.
+func WithTailOnError(n int) Option {
+	return func(c *config) {
+		c.tailOnError = &n
+	}
+}
+
+// WithReplaceAttr overwrites the LOG_TIME_FORMAT and LOG_KEY_MAPPING env vars with the given function,
+// used as [slog.HandlerOptions.ReplaceAttr].
+func WithReplaceAttr(f func(groups []string, a slog.Attr) slog.Attr) Option {
+	return func(c *config) {
+		c.replaceAttr = f
+	}
+}
@@ -0,0 +1,109 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSetupWithPrecedence(t *testing.T) {
+	t.Run("level", func(t *testing.T) {
+		cases := map[string]struct {
+			env  string
+			opts []Option
+			want slog.Level
+		}{
+			"default":         {want: slog.LevelDebug},
+			"env":             {env: "warn", want: slog.LevelWarn},
+			"option over env": {env: "warn", opts: []Option{WithLevel(slog.LevelError)}, want: slog.LevelError},
+		}
+		for name, tt := range cases {
+			t.Run(name, func(t *testing.T) {
+				t.Setenv("LOG_LEVEL", tt.env)
+				var b bytes.Buffer
+				l, err := SetupWithWriter(&b, tt.opts...)
+				if err != nil {
+					t.Fatalf("expected no error but got: %s", err)
+				}
+				if !l.Enabled(context.Background(), tt.want) || l.Enabled(context.Background(), tt.want-1) {
+					t.Errorf("expected the logger to be enabled starting with level %s", tt.want)
+				}
+			})
+		}
+	})
+	t.Run("format", func(t *testing.T) {
+		cases := map[string]struct {
+			env      string
+			opts     []Option
+			wantJSON bool
+		}{
+			"default":         {wantJSON: false},
+			"env":             {env: "json", wantJSON: true},
+			"option over env": {env: "json", opts: []Option{WithFormat(FormatText)}, wantJSON: false},
+		}
+		for name, tt := range cases {
+			t.Run(name, func(t *testing.T) {
+				t.Setenv("LOG_FORMAT", tt.env)
+				var b bytes.Buffer
+				if _, err := SetupWithWriter(&b, tt.opts...); err != nil {
+					t.Fatalf("expected no error but got: %s", err)
+				}
+				slog.Info("format log here")
+				if got := strings.HasPrefix(b.String(), "{"); got != tt.wantJSON {
+					t.Errorf("expected json format to be %t but got content: %s", tt.wantJSON, b.String())
+				}
+			})
+		}
+	})
+	t.Run("source", func(t *testing.T) {
+		cases := map[string]struct {
+			env        string
+			opts       []Option
+			wantSource bool
+		}{
+			"default":         {wantSource: false},
+			"env":             {env: "true", wantSource: true},
+			"option over env": {env: "true", opts: []Option{WithSource(false)}, wantSource: false},
+		}
+		for name, tt := range cases {
+			t.Run(name, func(t *testing.T) {
+				t.Setenv("LOG_SOURCE", tt.env)
+				var b bytes.Buffer
+				if _, err := SetupWithWriter(&b, tt.opts...); err != nil {
+					t.Fatalf("expected no error but got: %s", err)
+				}
+				slog.Info("source log here")
+				if got := strings.Contains(b.String(), "source="); got != tt.wantSource {
+					t.Errorf("expected source to be %t but got content: %s", tt.wantSource, b.String())
+				}
+			})
+		}
+	})
+	t.Run("writer", func(t *testing.T) {
+		var b bytes.Buffer
+		if _, err := SetupWith(WithWriter(&b)); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		slog.Info("writer log here")
+		if !strings.Contains(b.String(), "writer log here") {
+			t.Errorf("expected the logs to be written in the given writer")
+		}
+	})
+	t.Run("option overwrites invalid env", func(t *testing.T) {
+		t.Setenv("LOG_LEVEL", "verbose")
+		t.Setenv("LOG_FORMAT", "jsn")
+		var b bytes.Buffer
+		if _, err := SetupWithWriter(&b, WithLevel(slog.LevelInfo), WithFormat(FormatJSON)); err != nil {
+			t.Fatalf("expected the invalid env vars to be ignored but got: %s", err)
+		}
+	})
+	t.Run("invalid format option", func(t *testing.T) {
+		var b bytes.Buffer
+		_, err := SetupWithWriter(&b, WithFormat("yaml"))
+		if err == nil || !strings.Contains(err.Error(), "WithFormat") {
+			t.Fatalf("expected an error about the format option but got: %v", err)
+		}
+	})
+}
@@ -0,0 +1,196 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/yottta/go-core/env"
+	"github.com/yottta/go-core/shutdown"
+)
+
+const defaultMaxSizeMB = 100
+
+var (
+	openedFilesM sync.Mutex
+	// openedFiles holds, by path, the files opened for LOG_OUTPUT and LOG_OUTPUTS that are still written into.
+	openedFiles = map[string]*openedFile{}
+
+	reopenOnce sync.Once
+)
+
+// openedFile tracks the handlers writing into a [RotatingFile], so it's closed only once none of them is reachable.
+type openedFile struct {
+	f *RotatingFile
+	// users is the number of the reachable [logFile] pointing to f
+	users int
+	// current is set when the file is part of the last setup
+	current bool
+}
+
+// logFile is the writer given to the handlers of a setup. Since the handlers are the only ones holding it, it becomes
+// unreachable together with them, releasing the file. This way, the loggers created before a new setup (ie: the
+// ones captured from [slog.Default]) keep writing into their files.
+type logFile struct {
+	*RotatingFile
+}
+
+// outputsHandler parses the LOG_OUTPUTS value and creates a handler for each destination.
+// The destinations that cannot be configured are reported in the returned error and skipped.
+// When no destination could be configured, the returned handler is nil.
+func outputsHandler(outputs string, defFormat Format, opts *slog.HandlerOptions) (slog.Handler, []*logFile, error) {
+	var (
+		handlers []slog.Handler
+		files    []*logFile
+		errs     []error
+	)
+	for _, o := range strings.Split(outputs, ",") {
+		o = strings.TrimSpace(o)
+		if o == "" {
+			continue
+		}
+		dest, format, err := parseOutput(o, defFormat)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if w, ok := standardWriter(dest); ok {
+			handlers = append(handlers, newHandler(w, format, opts))
+			continue
+		}
+		f, err := openLogFile(dest)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid LOG_OUTPUTS entry %q: %w", o, err))
+			continue
+		}
+		files = append(files, f)
+		handlers = append(handlers, newHandler(f, format, opts))
+	}
+	switch len(handlers) {
+	case 0:
+		return nil, files, errors.Join(errs...)
+	case 1:
+		return handlers[0], files, errors.Join(errs...)
+	default:
+		return MultiHandler(handlers...), files, errors.Join(errs...)
+	}
+}
+
+// parseOutput splits a LOG_OUTPUTS entry into its destination and its format. The suffix after the last colon is
+// split off only when it's a known format, so the colons of the file paths are kept (ie: C:\logs\app.log).
+func parseOutput(o string, defFormat Format) (string, Format, error) {
+	idx := strings.LastIndex(o, ":")
+	if idx < 0 {
+		return o, defFormat, nil
+	}
+	dest, format := o[:idx], Format(o[idx+1:])
+	if validFormat(format) {
+		return dest, format, nil
+	}
+	if _, ok := standardWriter(dest); ok {
+		return "", "", fmt.Errorf("invalid LOG_OUTPUTS entry %q: format expected to be one of text, json, pretty, gcp, ecs", o)
+	}
+	return o, defFormat, nil
+}
+
+// standardWriter returns the writer of the destinations that are not files: stderr, stdout and discard.
+func standardWriter(dest string) (io.Writer, bool) {
+	switch dest {
+	case "stderr":
+		return os.Stderr, true
+	case "stdout":
+		return os.Stdout, true
+	case "discard":
+		return io.Discard, true
+	}
+	return nil, false
+}
+
+// openLogFile returns a writer into the file at the given path, rotated as configured by the LOG_MAX_SIZE_MB,
+// LOG_MAX_BACKUPS and LOG_MAX_AGE_DAYS env vars. The file already opened by a previous setup for the same path
+// is reused, so the loggers of the previous setup keep writing into it.
+func openLogFile(path string) (*logFile, error) {
+	maxSizeMB, maxBackups, maxAgeDays := env.IntWithDefault("LOG_MAX_SIZE_MB", defaultMaxSizeMB), env.Int("LOG_MAX_BACKUPS"), env.Int("LOG_MAX_AGE_DAYS")
+	openedFilesM.Lock()
+	defer openedFilesM.Unlock()
+	of, ok := openedFiles[path]
+	if ok {
+		of.f.setLimits(maxSizeMB, maxBackups, maxAgeDays)
+	} else {
+		f, err := NewRotatingFile(path, maxSizeMB, maxBackups, maxAgeDays)
+		if err != nil {
+			return nil, err
+		}
+		of = &openedFile{f: f}
+		openedFiles[path] = of
+	}
+	of.users++
+	lf := &logFile{RotatingFile: of.f}
+	runtime.AddCleanup(lf, releaseLogFile, of)
+	return lf, nil
+}
+
+// releaseLogFile is called once a [logFile] is unreachable, closing the file when it was the last one and the file
+// is not part of the last setup.
+func releaseLogFile(of *openedFile) {
+	openedFilesM.Lock()
+	defer openedFilesM.Unlock()
+	of.users--
+	closeUnused(of)
+}
+
+// closeUnused closes the file when it's neither written into nor part of the last setup.
+// Expects openedFilesM to be held.
+func closeUnused(of *openedFile) {
+	if of.users > 0 || of.current {
+		return
+	}
+	_ = of.f.Close()
+	if openedFiles[of.f.path] == of {
+		delete(openedFiles, of.f.path)
+	}
+}
+
+// replaceOpenedFiles marks the files of the last setup, closing the ones of the previous setups that are not
+// written into anymore.
+// When files are opened, it also starts listening for syscall.SIGHUP to reopen them, for logrotate compatibility.
+func replaceOpenedFiles(files []*logFile) {
+	openedFilesM.Lock()
+	defer openedFilesM.Unlock()
+	for _, of := range openedFiles {
+		of.current = false
+	}
+	for _, f := range files {
+		openedFiles[f.path].current = true
+	}
+	for _, of := range openedFiles {
+		closeUnused(of)
+	}
+	if len(files) > 0 {
+		reopenOnce.Do(func() {
+			ch := shutdown.Chan(syscall.SIGHUP)
+			go func() {
+				for range ch {
+					reopenFiles()
+				}
+			}()
+		})
+	}
+}
+
+// reopenFiles reopens all the files still written into.
+func reopenFiles() {
+	openedFilesM.Lock()
+	defer openedFilesM.Unlock()
+	for _, of := range openedFiles {
+		if err := of.f.Reopen(); err != nil {
+			slog.With("error", err).With("path", of.f.path).Warn("failed to reopen log file")
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package logging
+
+import (
+	"log/slog"
+	"path/filepath"
+)
+
+// jsonPreset describes how the records are rendered for a logging provider that expects specific JSON fields.
+// Adding a new provider is a matter of adding a new entry into [jsonPresets].
+type jsonPreset struct {
+	// keys maps the built-in keys of slog to the ones expected by the provider.
+	keys map[string]string
+	// levels maps the levels to the names expected by the provider, ordered from the highest to the lowest.
+	// A level is named after the first entry that it's greater or equal to.
+	levels []presetLevel
+	// source converts the source of the record into the format expected by the provider.
+	source func(*slog.Source) slog.Value
+}
+
+type presetLevel struct {
+	min  slog.Level
+	name string
+}
+
+var jsonPresets = map[Format]jsonPreset{
+	// https://cloud.google.com/logging/docs/structured-logging
+	FormatGCP: {
+		keys: map[string]string{
+			slog.TimeKey:    "timestamp",
+			slog.LevelKey:   "severity",
+			slog.MessageKey: "message",
+			slog.SourceKey:  "logging.googleapis.com/sourceLocation",
+		},
+		levels: []presetLevel{
+			{min: slog.LevelError, name: "ERROR"},
+			{min: slog.LevelWarn, name: "WARNING"},
+			{min: slog.LevelInfo, name: "INFO"},
+			{min: slog.LevelDebug, name: "DEBUG"},
+		},
+		source: func(s *slog.Source) slog.Value {
+			return slog.GroupValue(
+				slog.String("file", s.File),
+				slog.Int("line", s.Line),
+				slog.String("function", s.Function),
+			)
+		},
+	},
+	// https://www.elastic.co/guide/en/ecs/current/ecs-log.html
+	FormatECS: {
+		keys: map[string]string{
+			slog.TimeKey:    "@timestamp",
+			slog.LevelKey:   "log.level",
+			slog.MessageKey: "message",
+			slog.SourceKey:  "log.origin",
+		},
+		levels: []presetLevel{
+			{min: slog.LevelError, name: "error"},
+			{min: slog.LevelWarn, name: "warn"},
+			{min: slog.LevelInfo, name: "info"},
+			{min: slog.LevelDebug, name: "debug"},
+		},
+		source: func(s *slog.Source) slog.Value {
+			return slog.GroupValue(
+				slog.Group("file",
+					slog.String("name", filepath.Base(s.File)),
+					slog.Int("line", s.Line),
+				),
+				slog.String("function", s.Function),
+			)
+		},
+	},
+}
+
+// replaceAttr returns the [slog.HandlerOptions.ReplaceAttr] of the preset. The given function, when not nil,
+// is applied before the preset, so the keys renamed by it (ie: with LOG_KEY_MAPPING) take precedence.
+func (p jsonPreset) replaceAttr(before func([]string, slog.Attr) slog.Attr) func([]string, slog.Attr) slog.Attr {
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if before != nil {
+			a = before(groups, a)
+		}
+		if len(groups) > 0 {
+			return a
+		}
+		return p.replaceBuiltIn(a)
+	}
+}
+
+func (p jsonPreset) replaceBuiltIn(a slog.Attr) slog.Attr {
+	to, ok := p.keys[a.Key]
+	if !ok {
+		return a
+	}
+	switch a.Key {
+	case slog.LevelKey:
+		if l, ok := a.Value.Any().(slog.Level); ok {
+			a.Value = slog.StringValue(p.levelName(l))
+		}
+	case slog.SourceKey:
+		if s, ok := a.Value.Any().(*slog.Source); ok && p.source != nil {
+			a.Value = p.source(s)
+		}
+	}
+	a.Key = to
+	return a
+}
+
+func (p jsonPreset) levelName(l slog.Level) string {
+	for _, pl := range p.levels {
+		if l >= pl.min {
+			return pl.name
+		}
+	}
+	if len(p.levels) > 0 {
+		return p.levels[len(p.levels)-1].name
+	}
+	return l.String()
+}
@@ -0,0 +1,104 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func TestJSONPresets(t *testing.T) {
+	cases := []struct {
+		format Format
+		want   map[string]any
+	}{
+		{
+			format: FormatGCP,
+			want: map[string]any{
+				"severity": "WARNING",
+				"message":  "warn log here",
+				"key":      "value",
+			},
+		},
+		{
+			format: FormatECS,
+			want: map[string]any{
+				"log.level": "warn",
+				"message":   "warn log here",
+				"key":       "value",
+			},
+		},
+	}
+	for _, tt := range cases {
+		t.Run(string(tt.format), func(t *testing.T) {
+			t.Setenv("LOG_FORMAT", string(tt.format))
+			t.Setenv("LOG_SOURCE", "true")
+			var b bytes.Buffer
+			if _, err := SetupWithWriter(&b); err != nil {
+				t.Fatalf("expected no error but got: %s", err)
+			}
+			slog.Warn("warn log here", "key", "value")
+
+			var got map[string]any
+			if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+				t.Fatalf("failed to parse the record %q: %s", b.String(), err)
+			}
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("got a different value for %q than the wanted one. expected: %v; got: %v", k, v, got[k])
+				}
+			}
+			for _, k := range []string{slog.TimeKey, slog.LevelKey, slog.MessageKey, slog.SourceKey} {
+				if _, ok := got[k]; ok {
+					t.Errorf("expected the built-in key %q to be renamed but got: %s", k, b.String())
+				}
+			}
+			p := jsonPresets[tt.format]
+			if _, ok := got[p.keys[slog.TimeKey]]; !ok {
+				t.Errorf("expected the time under %q but got: %s", p.keys[slog.TimeKey], b.String())
+			}
+			source, ok := got[p.keys[slog.SourceKey]].(map[string]any)
+			if !ok {
+				t.Fatalf("expected the source under %q but got: %s", p.keys[slog.SourceKey], b.String())
+			}
+			if _, ok := source["function"]; !ok {
+				t.Errorf("expected the source to contain the function but got: %v", source)
+			}
+		})
+	}
+	t.Run("level names", func(t *testing.T) {
+		p := jsonPresets[FormatGCP]
+		for l, want := range map[slog.Level]string{
+			slog.LevelDebug - 4: "DEBUG",
+			slog.LevelDebug:     "DEBUG",
+			slog.LevelInfo + 2:  "INFO",
+			slog.LevelWarn:      "WARNING",
+			slog.LevelError + 4: "ERROR",
+		} {
+			if got := p.levelName(l); got != want {
+				t.Errorf("got a different level name for %s than the wanted one. expected: %q; got: %q", l, want, got)
+			}
+		}
+	})
+	t.Run("key mapping takes precedence", func(t *testing.T) {
+		t.Setenv("LOG_FORMAT", string(FormatGCP))
+		t.Setenv("LOG_KEY_MAPPING", "msg=text")
+		t.Setenv("LOG_TIME_FORMAT", "unix_ms")
+		var b bytes.Buffer
+		if _, err := SetupWithWriter(&b); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		slog.Info("info log here")
+
+		var got map[string]any
+		if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+			t.Fatalf("failed to parse the record %q: %s", b.String(), err)
+		}
+		if got["text"] != "info log here" {
+			t.Errorf("expected the message under the mapped key but got: %s", b.String())
+		}
+		if _, ok := got["timestamp"].(float64); !ok {
+			t.Errorf("expected the time formatted as unix ms but got: %s", b.String())
+		}
+	})
+}
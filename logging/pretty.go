@@ -0,0 +1,206 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/yottta/go-core/env"
+)
+
+const (
+	ansiReset   = "\033[0m"
+	ansiBold    = "\033[1m"
+	ansiDim     = "\033[2m"
+	ansiRed     = "\033[31m"
+	ansiGreen   = "\033[32m"
+	ansiYellow  = "\033[33m"
+	ansiMagenta = "\033[35m"
+	ansiCyan    = "\033[36m"
+
+	// prettyMessageWidth is the width to which the messages are padded to have the attributes aligned.
+	prettyMessageWidth = 40
+)
+
+// NewPrettyHandler returns a handler meant for the local development, writing human friendly records:
+// dim timestamps, colored level badges, aligned messages followed by key=value attributes on the same line.
+// The attributes with multi-line values (ie: the stack of an error) are written indented under the record.
+// The colors are disabled when the writer is not a terminal or when the NO_COLOR env var is set.
+// The [slog.HandlerOptions.ReplaceAttr] is applied only to the attributes of the records, not to the built-in ones.
+func NewPrettyHandler(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
+	if opts == nil {
+		opts = &slog.HandlerOptions{}
+	}
+	return &prettyHandler{
+		w:     w,
+		mu:    &sync.Mutex{},
+		opts:  *opts,
+		color: env.String("NO_COLOR") == "" && isTerminal(w),
+	}
+}
+
+type prettyHandler struct {
+	w     io.Writer
+	mu    *sync.Mutex
+	opts  slog.HandlerOptions
+	color bool
+
+	// attrs are the attributes given through WithAttrs, already flattened
+	attrs  []prettyAttr
+	groups []string
+}
+
+type prettyAttr struct {
+	key string
+	val slog.Value
+}
+
+func (h *prettyHandler) Enabled(_ context.Context, l slog.Level) bool {
+	minLevel := slog.LevelInfo
+	if h.opts.Level != nil {
+		minLevel = h.opts.Level.Level()
+	}
+	return l >= minLevel
+}
+
+func (h *prettyHandler) Handle(_ context.Context, r slog.Record) error {
+	var b strings.Builder
+	if !r.Time.IsZero() {
+		b.WriteString(h.paint(ansiDim, r.Time.Format("15:04:05.000")))
+		b.WriteByte(' ')
+	}
+	b.WriteString(h.paint(levelColor(r.Level), fmt.Sprintf("%-5s", r.Level.String())))
+	b.WriteByte(' ')
+	if h.opts.AddSource && r.PC != 0 {
+		fs := runtime.CallersFrames([]uintptr{r.PC})
+		f, _ := fs.Next()
+		b.WriteString(h.paint(ansiDim, fmt.Sprintf("%s:%d", f.File, f.Line)))
+		b.WriteByte(' ')
+	}
+
+	attrs := slices.Clone(h.attrs)
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = h.appendAttr(attrs, h.groups, a)
+		return true
+	})
+	msg := r.Message
+	if len(attrs) > 0 && len(msg) < prettyMessageWidth {
+		msg += strings.Repeat(" ", prettyMessageWidth-len(msg))
+	}
+	b.WriteString(h.paint(ansiBold, msg))
+
+	var multiline []prettyAttr
+	for _, a := range attrs {
+		v := a.val.String()
+		if strings.Contains(v, "\n") {
+			multiline = append(multiline, a)
+			continue
+		}
+		if v == "" || strings.ContainsAny(v, " =\"\t") {
+			v = strconv.Quote(v)
+		}
+		b.WriteByte(' ')
+		b.WriteString(h.paint(ansiCyan, a.key+"="))
+		b.WriteString(v)
+	}
+	b.WriteByte('\n')
+	for _, a := range multiline {
+		b.WriteString("    ")
+		b.WriteString(h.paint(ansiCyan, a.key+":"))
+		b.WriteByte('\n')
+		for line := range strings.SplitSeq(strings.TrimRight(a.val.String(), "\n"), "\n") {
+			b.WriteString("        ")
+			b.WriteString(line)
+			b.WriteByte('\n')
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, b.String())
+	return err
+}
+
+// appendAttr flattens the given attribute, prefixing its key with the groups it belongs to.
+func (h *prettyHandler) appendAttr(attrs []prettyAttr, groups []string, a slog.Attr) []prettyAttr {
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() != slog.KindGroup && h.opts.ReplaceAttr != nil {
+		a = h.opts.ReplaceAttr(groups, a)
+		a.Value = a.Value.Resolve()
+	}
+	if a.Equal(slog.Attr{}) {
+		return attrs
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			groups = append(slices.Clip(groups), a.Key)
+		}
+		for _, ga := range a.Value.Group() {
+			attrs = h.appendAttr(attrs, groups, ga)
+		}
+		return attrs
+	}
+	key := a.Key
+	if len(groups) > 0 {
+		key = strings.Join(groups, ".") + "." + a.Key
+	}
+	return append(attrs, prettyAttr{key: key, val: a.Value})
+}
+
+func (h *prettyHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	h2 := *h
+	h2.attrs = slices.Clip(h.attrs)
+	for _, a := range attrs {
+		h2.attrs = h.appendAttr(h2.attrs, h.groups, a)
+	}
+	return &h2
+}
+
+func (h *prettyHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.groups = append(slices.Clip(h.groups), name)
+	return &h2
+}
+
+func (h *prettyHandler) paint(color, s string) string {
+	if !h.color {
+		return s
+	}
+	return color + s + ansiReset
+}
+
+func levelColor(l slog.Level) string {
+	switch {
+	case l >= slog.LevelError:
+		return ansiRed
+	case l >= slog.LevelWarn:
+		return ansiYellow
+	case l >= slog.LevelInfo:
+		return ansiGreen
+	default:
+		return ansiMagenta
+	}
+}
+
+// isTerminal reports whether the writer is a character device, like a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
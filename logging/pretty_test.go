@@ -0,0 +1,95 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrettyHandler(t *testing.T) {
+	t.Run("record layout", func(t *testing.T) {
+		var b bytes.Buffer
+		l := slog.New(NewPrettyHandler(&b, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		l.Info("request served", "method", "GET", "path", "/users", "msg with spaces", "a b")
+
+		got := b.String()
+		if _, err := time.Parse("15:04:05.000", got[:12]); err != nil {
+			t.Errorf("expected the record to start with the time but got: %q", got)
+		}
+		want := "INFO  request served" + strings.Repeat(" ", prettyMessageWidth-len("request served")) +
+			` method=GET path=/users msg with spaces="a b"` + "\n"
+		if !strings.HasSuffix(got, want) {
+			t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+		}
+		if strings.Contains(got, "\033[") {
+			t.Errorf("expected no colors when the writer is not a terminal but got: %q", got)
+		}
+	})
+	t.Run("attrs and groups of derived loggers", func(t *testing.T) {
+		var b bytes.Buffer
+		base := slog.New(NewPrettyHandler(&b, nil))
+		l := base.With("request.id", "abc").WithGroup("http").With("method", "GET")
+		l.Info("first", "status", 200, slog.Group("client", "ip", "127.0.0.1"))
+		base.WithGroup("empty").Info("second")
+
+		lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("expected 2 records but got %d: %q", len(lines), b.String())
+		}
+		if want := "request.id=abc http.method=GET http.status=200 http.client.ip=127.0.0.1"; !strings.HasSuffix(lines[0], want) {
+			t.Errorf("expected %q to end with %q", lines[0], want)
+		}
+		if want := "INFO  second"; !strings.HasSuffix(lines[1], want) {
+			t.Errorf("expected %q to end with %q", lines[1], want)
+		}
+	})
+	t.Run("multi-line errors are indented", func(t *testing.T) {
+		var b bytes.Buffer
+		l := slog.New(NewPrettyHandler(&b, nil))
+		l.Error("failed", Err(WithStack(errors.New("boom"))))
+
+		got := b.String()
+		if want := "error.message=boom error.type=*errors.errorString\n    error.stack:\n        "; !strings.Contains(got, want) {
+			t.Errorf("expected %q to contain %q", got, want)
+		}
+	})
+	t.Run("level and source", func(t *testing.T) {
+		var b bytes.Buffer
+		l := slog.New(NewPrettyHandler(&b, &slog.HandlerOptions{Level: slog.LevelWarn, AddSource: true}))
+		l.Info("info log here")
+		l.Warn("warn log here")
+
+		got := b.String()
+		if strings.Contains(got, "info log here") {
+			t.Errorf("expected info to be filtered out but got: %q", got)
+		}
+		if want := "pretty_test.go:"; !strings.Contains(got, want) {
+			t.Errorf("expected %q to contain %q", got, want)
+		}
+	})
+	t.Run("colors", func(t *testing.T) {
+		var b bytes.Buffer
+		h := NewPrettyHandler(&b, nil).(*prettyHandler)
+		h.color = true
+		slog.New(h).Error("colored")
+		if want := ansiRed + "ERROR" + ansiReset; !strings.Contains(b.String(), want) {
+			t.Errorf("expected %q to contain %q", b.String(), want)
+		}
+	})
+	t.Run("LOG_FORMAT=pretty", func(t *testing.T) {
+		t.Setenv("LOG_FORMAT", "pretty")
+		t.Setenv("LOG_LEVEL", "warn")
+		var b bytes.Buffer
+		if _, err := SetupWithWriter(&b); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		writeAllLevelLogs()
+		assertLogs(t, b.String(), false, false, true, true)
+		if want := "WARN  warn log here"; !strings.Contains(b.String(), want) {
+			t.Errorf("expected %q to contain %q", b.String(), want)
+		}
+	})
+}
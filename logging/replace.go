@@ -0,0 +1,73 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// TimeFormat is the format in which the time of the records is rendered.
+type TimeFormat string
+
+const (
+	TimeFormatRFC3339     TimeFormat = "rfc3339"
+	TimeFormatRFC3339Nano TimeFormat = "rfc3339nano"
+	TimeFormatUnixMilli   TimeFormat = "unix_ms"
+)
+
+// replaceAttrFromEnv builds the [slog.HandlerOptions.ReplaceAttr] function from the LOG_TIME_FORMAT and
+// LOG_KEY_MAPPING env vars. When none of them is set, nil is returned.
+func replaceAttrFromEnv(timeFormat, keyMapping string) (func([]string, slog.Attr) slog.Attr, error) {
+	var errs []error
+	tf := TimeFormat(strings.ToLower(timeFormat))
+	switch tf {
+	case "", TimeFormatRFC3339, TimeFormatRFC3339Nano, TimeFormatUnixMilli:
+	default:
+		errs = append(errs, fmt.Errorf("invalid LOG_TIME_FORMAT %q: expected one of rfc3339, rfc3339nano, unix_ms", timeFormat))
+		tf = ""
+	}
+	mapping := map[string]string{}
+	for _, m := range strings.Split(keyMapping, ",") {
+		m = strings.TrimSpace(m)
+		if m == "" {
+			continue
+		}
+		from, to, ok := strings.Cut(m, "=")
+		from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+		if !ok || from == "" || to == "" {
+			errs = append(errs, fmt.Errorf("invalid LOG_KEY_MAPPING entry %q: expected from=to", m))
+			continue
+		}
+		mapping[from] = to
+	}
+	err := errors.Join(errs...)
+	if tf == "" && len(mapping) == 0 {
+		return nil, err
+	}
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if len(groups) > 0 {
+			return a
+		}
+		if a.Key == slog.TimeKey && a.Value.Kind() == slog.KindTime {
+			a.Value = formatTime(a.Value.Time(), tf)
+		}
+		if to, ok := mapping[a.Key]; ok {
+			a.Key = to
+		}
+		return a
+	}, err
+}
+
+func formatTime(t time.Time, tf TimeFormat) slog.Value {
+	switch tf {
+	case TimeFormatRFC3339:
+		return slog.StringValue(t.Format(time.RFC3339))
+	case TimeFormatRFC3339Nano:
+		return slog.StringValue(t.Format(time.RFC3339Nano))
+	case TimeFormatUnixMilli:
+		return slog.Int64Value(t.UnixMilli())
+	}
+	return slog.TimeValue(t)
+}
@@ -0,0 +1,97 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReplaceAttr(t *testing.T) {
+	decode := func(t *testing.T, b *bytes.Buffer) map[string]any {
+		t.Helper()
+		var res map[string]any
+		if err := json.Unmarshal(b.Bytes(), &res); err != nil {
+			t.Fatalf("failed to decode the json log %q: %s", b.String(), err)
+		}
+		return res
+	}
+	t.Run("key mapping and unix_ms in json", func(t *testing.T) {
+		t.Setenv("LOG_FORMAT", "json")
+		t.Setenv("LOG_TIME_FORMAT", "unix_ms")
+		t.Setenv("LOG_KEY_MAPPING", "time=timestamp,msg=message,level=severity")
+		var b bytes.Buffer
+		if _, err := SetupWithWriter(&b); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		before := time.Now().UnixMilli()
+		slog.Info("info log here", slog.Group("g", slog.String("msg", "nested")))
+		got := decode(t, &b)
+		for _, k := range []string{"time", "msg", "level"} {
+			if _, ok := got[k]; ok {
+				t.Errorf("expected key %q to be renamed but it's still present: %v", k, got)
+			}
+		}
+		if got["message"] != "info log here" || got["severity"] != "INFO" {
+			t.Errorf("expected the renamed keys to hold the values but got: %v", got)
+		}
+		ts, ok := got["timestamp"].(float64)
+		if !ok || int64(ts) < before {
+			t.Errorf("expected the timestamp in epoch millis but got: %v", got["timestamp"])
+		}
+		if g, ok := got["g"].(map[string]any); !ok || g["msg"] != "nested" {
+			t.Errorf("expected the nested keys to not be renamed but got: %v", got["g"])
+		}
+	})
+	t.Run("rfc3339 in text", func(t *testing.T) {
+		t.Setenv("LOG_TIME_FORMAT", "rfc3339")
+		t.Setenv("LOG_KEY_MAPPING", "msg=message")
+		var b bytes.Buffer
+		if _, err := SetupWithWriter(&b); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		slog.Info("info log here")
+		if want := `message="info log here"`; !strings.Contains(b.String(), want) {
+			t.Errorf("expected %q in the logs but got:\n%s", want, b.String())
+		}
+		ts := strings.TrimPrefix(strings.Fields(b.String())[0], "time=")
+		if _, err := time.Parse(time.RFC3339, ts); err != nil || strings.Contains(ts, ".") {
+			t.Errorf("expected the time in rfc3339 but got %q", ts)
+		}
+	})
+	t.Run("invalid values", func(t *testing.T) {
+		t.Setenv("LOG_TIME_FORMAT", "iso")
+		t.Setenv("LOG_KEY_MAPPING", "time")
+		var b bytes.Buffer
+		_, err := SetupWithWriter(&b)
+		if err == nil {
+			t.Fatalf("expected an error but got nothing")
+		}
+		for _, want := range []string{"LOG_TIME_FORMAT", "LOG_KEY_MAPPING"} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("expected error to contain %q but got %q", want, err.Error())
+			}
+		}
+	})
+	t.Run("option takes precedence", func(t *testing.T) {
+		t.Setenv("LOG_FORMAT", "json")
+		t.Setenv("LOG_KEY_MAPPING", "msg=message")
+		var b bytes.Buffer
+		_, err := SetupWithWriter(&b, WithReplaceAttr(func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.MessageKey {
+				a.Key = "text"
+			}
+			return a
+		}))
+		if err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		slog.Info("info log here")
+		got := decode(t, &b)
+		if got["text"] != "info log here" {
+			t.Errorf("expected the key from the option but got: %v", got)
+		}
+	})
+}
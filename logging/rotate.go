@@ -0,0 +1,194 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const backupTimeFormat = "2006-01-02T15-04-05.000000000"
+
+// RotatingFile is an [io.Writer] that writes into a file and rotates it once it reaches the configured size.
+// The rotated files are renamed by adding the rotation time to their name (ie: app-2006-01-02T15-04-05.000000000.log)
+// and the old ones are removed based on the configured number of backups and age.
+// This is safe to be used concurrently.
+type RotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+	maxAge     time.Duration
+
+	m    sync.Mutex
+	f    *os.File
+	size int64
+}
+
+// NewRotatingFile opens, or creates, the file at the given path.
+// A maxSizeMB lower or equal to 0 disables the rotation, while a maxBackups or maxAgeDays lower or
+// equal to 0 keeps all the rotated files.
+func NewRotatingFile(path string, maxSizeMB, maxBackups, maxAgeDays int) (*RotatingFile, error) {
+	r := &RotatingFile{
+		path:       path,
+		maxSize:    int64(maxSizeMB) * 1024 * 1024,
+		maxBackups: maxBackups,
+		maxAge:     time.Duration(maxAgeDays) * 24 * time.Hour,
+	}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// setLimits replaces the rotation limits, given in the same way as to [NewRotatingFile].
+func (r *RotatingFile) setLimits(maxSizeMB, maxBackups, maxAgeDays int) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.maxSize = int64(maxSizeMB) * 1024 * 1024
+	r.maxBackups = maxBackups
+	r.maxAge = time.Duration(maxAgeDays) * 24 * time.Hour
+}
+
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if r.f == nil {
+		return 0, os.ErrClosed
+	}
+	var rotateErr error
+	if r.maxSize > 0 && r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if rotateErr = r.rotate(); rotateErr != nil && r.f == nil {
+			return 0, rotateErr
+		}
+	}
+	n, err := r.f.Write(p)
+	r.size += int64(n)
+	return n, errors.Join(rotateErr, err)
+}
+
+// Reopen closes and opens again the file. This is useful when the file is rotated by an external
+// tool, like logrotate.
+func (r *RotatingFile) Reopen() error {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if r.f != nil {
+		_ = r.f.Close()
+	}
+	return r.open()
+}
+
+func (r *RotatingFile) Close() error {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if r.f == nil {
+		return nil
+	}
+	err := r.f.Close()
+	r.f = nil
+	return err
+}
+
+func (r *RotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		r.f = nil
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		r.f = nil
+		return err
+	}
+	r.f = f
+	r.size = info.Size()
+	return nil
+}
+
+// rotate renames the current file into a backup, opens a new one and removes the old backups.
+// A failure to close the current file doesn't stop the rotation, since the handle cannot be trusted anymore,
+// but it's still returned once the new file is opened. When the file cannot be renamed, it's opened again to
+// keep writing into it, and the error is returned only to be reported.
+func (r *RotatingFile) rotate() error {
+	var closeErr error
+	if err := r.f.Close(); err != nil {
+		closeErr = fmt.Errorf("failed to close the rotated log file: %w", err)
+	}
+	r.f = nil
+	name := r.backupName(time.Now())
+	for t := time.Now(); fileExists(name); t = t.Add(time.Nanosecond) {
+		name = r.backupName(t)
+	}
+	if err := os.Rename(r.path, name); err != nil {
+		// the file is kept growing rather than losing the logs until the next reopen
+		return errors.Join(closeErr, fmt.Errorf("failed to rotate log file: %w", err), r.open())
+	}
+	if err := r.open(); err != nil {
+		return errors.Join(closeErr, err)
+	}
+	r.removeOldBackups()
+	return closeErr
+}
+
+func (r *RotatingFile) backupName(t time.Time) string {
+	ext := filepath.Ext(r.path)
+	prefix := strings.TrimSuffix(r.path, ext)
+	return fmt.Sprintf("%s-%s%s", prefix, t.Format(backupTimeFormat), ext)
+}
+
+func (r *RotatingFile) removeOldBackups() {
+	if r.maxBackups <= 0 && r.maxAge <= 0 {
+		return
+	}
+	backups := r.backups()
+	// newest first
+	slices.SortFunc(backups, func(a, b backup) int {
+		return b.t.Compare(a.t)
+	})
+	cutoff := time.Now().Add(-r.maxAge)
+	for i, b := range backups {
+		tooMany := r.maxBackups > 0 && i >= r.maxBackups
+		tooOld := r.maxAge > 0 && b.t.Before(cutoff)
+		if tooMany || tooOld {
+			_ = os.Remove(b.path)
+		}
+	}
+}
+
+type backup struct {
+	path string
+	t    time.Time
+}
+
+func (r *RotatingFile) backups() []backup {
+	ext := filepath.Ext(r.path)
+	prefix := strings.TrimSuffix(filepath.Base(r.path), ext) + "-"
+	dir := filepath.Dir(r.path)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var res []backup
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+			continue
+		}
+		ts := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext)
+		t, err := time.ParseInLocation(backupTimeFormat, ts, time.Local)
+		if err != nil {
+			continue
+		}
+		res = append(res, backup{path: filepath.Join(dir, name), t: t})
+	}
+	return res
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,348 @@
+package logging
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRotatingFile(t *testing.T) {
+	countFiles := func(t *testing.T, dir string) int {
+		t.Helper()
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatalf("failed to read dir: %s", err)
+		}
+		return len(entries)
+	}
+	t.Run("rotates when reaching the max size", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "app.log")
+		r, err := NewRotatingFile(path, 1, 0, 0)
+		if err != nil {
+			t.Fatalf("failed to open the file: %s", err)
+		}
+		defer func() { _ = r.Close() }()
+		r.maxSize = 10
+
+		for range 3 {
+			if _, err := r.Write([]byte("123456789\n")); err != nil {
+				t.Fatalf("failed to write: %s", err)
+			}
+		}
+		if got, want := countFiles(t, dir), 3; got != want {
+			t.Errorf("expected %d files after rotation but got %d", want, got)
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read the file: %s", err)
+		}
+		if got, want := string(content), "123456789\n"; got != want {
+			t.Errorf("expected the current file to contain only the last write. expected %q; got %q", want, got)
+		}
+	})
+	t.Run("keeps only the max backups", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "app.log")
+		r, err := NewRotatingFile(path, 1, 2, 0)
+		if err != nil {
+			t.Fatalf("failed to open the file: %s", err)
+		}
+		defer func() { _ = r.Close() }()
+		r.maxSize = 10
+
+		for range 6 {
+			if _, err := r.Write([]byte("123456789\n")); err != nil {
+				t.Fatalf("failed to write: %s", err)
+			}
+		}
+		if got, want := countFiles(t, dir), 3; got != want {
+			t.Errorf("expected %d files (current + 2 backups) but got %d", want, got)
+		}
+	})
+	t.Run("removes backups older than max age", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "app.log")
+		r, err := NewRotatingFile(path, 1, 0, 1)
+		if err != nil {
+			t.Fatalf("failed to open the file: %s", err)
+		}
+		defer func() { _ = r.Close() }()
+		r.maxSize = 10
+		old := r.backupName(time.Now().Add(-48 * time.Hour))
+		if err := os.WriteFile(old, []byte("old"), 0o644); err != nil {
+			t.Fatalf("failed to create old backup: %s", err)
+		}
+		for range 2 {
+			_, _ = r.Write([]byte("123456789\n"))
+		}
+		if _, err := os.Stat(old); !os.IsNotExist(err) {
+			t.Errorf("expected the old backup to be removed but got: %v", err)
+		}
+	})
+	t.Run("concurrent writes", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "app.log")
+		r, err := NewRotatingFile(path, 1, 0, 0)
+		if err != nil {
+			t.Fatalf("failed to open the file: %s", err)
+		}
+		defer func() { _ = r.Close() }()
+		r.maxSize = 1000
+
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Go(func() {
+				for range 50 {
+					_, _ = r.Write([]byte("concurrent line\n"))
+				}
+			})
+		}
+		wg.Wait()
+		var total int
+		entries, _ := os.ReadDir(dir)
+		for _, e := range entries {
+			content, _ := os.ReadFile(filepath.Join(dir, e.Name()))
+			total += strings.Count(string(content), "concurrent line\n")
+		}
+		if got, want := total, 500; got != want {
+			t.Errorf("expected %d lines across all the files but got %d", want, got)
+		}
+	})
+	t.Run("rotates even when closing the current file fails", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "app.log")
+		r, err := NewRotatingFile(path, 1, 0, 0)
+		if err != nil {
+			t.Fatalf("failed to open the file: %s", err)
+		}
+		defer func() { _ = r.Close() }()
+		r.maxSize = 10
+		if _, err := r.Write([]byte("123456789\n")); err != nil {
+			t.Fatalf("failed to write: %s", err)
+		}
+		// closing the file behind the writer's back makes the close from the rotation fail
+		_ = r.f.Close()
+
+		n, err := r.Write([]byte("after\n"))
+		if err == nil || !strings.Contains(err.Error(), "failed to close the rotated log file") {
+			t.Errorf("expected the close error to be reported but got: %v", err)
+		}
+		if got, want := n, len("after\n"); got != want {
+			t.Errorf("got a different value than the wanted one. expected: %d; got: %d", want, got)
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read the file: %s", err)
+		}
+		if got, want := string(content), "after\n"; got != want {
+			t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+		}
+		if got, want := countFiles(t, dir), 2; got != want {
+			t.Errorf("expected %d files after rotation but got %d", want, got)
+		}
+	})
+	t.Run("keeps writing when the file cannot be renamed", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "app.log")
+		r, err := NewRotatingFile(path, 1, 0, 0)
+		if err != nil {
+			t.Fatalf("failed to open the file: %s", err)
+		}
+		defer func() { _ = r.Close() }()
+		r.maxSize = 10
+		if _, err := r.Write([]byte("123456789\n")); err != nil {
+			t.Fatalf("failed to write: %s", err)
+		}
+		// removing the file behind the writer's back makes the rename of the rotation fail
+		if err := os.Remove(path); err != nil {
+			t.Fatalf("failed to remove the file: %s", err)
+		}
+
+		n, err := r.Write([]byte("after\n"))
+		if err == nil || !strings.Contains(err.Error(), "failed to rotate log file") {
+			t.Errorf("expected the rename error to be reported but got: %v", err)
+		}
+		if got, want := n, len("after\n"); got != want {
+			t.Errorf("got a different value than the wanted one. expected: %d; got: %d", want, got)
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read the file: %s", err)
+		}
+		if got, want := string(content), "after\n"; got != want {
+			t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+		}
+	})
+	t.Run("reopen after external rotation", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "app.log")
+		r, err := NewRotatingFile(path, 0, 0, 0)
+		if err != nil {
+			t.Fatalf("failed to open the file: %s", err)
+		}
+		defer func() { _ = r.Close() }()
+		_, _ = r.Write([]byte("before\n"))
+		if err := os.Rename(path, path+".1"); err != nil {
+			t.Fatalf("failed to rename: %s", err)
+		}
+		if err := r.Reopen(); err != nil {
+			t.Fatalf("failed to reopen: %s", err)
+		}
+		_, _ = r.Write([]byte("after\n"))
+		content, _ := os.ReadFile(path)
+		if got, want := string(content), "after\n"; got != want {
+			t.Errorf("expected the new file to contain %q but got %q", want, got)
+		}
+	})
+}
+
+func TestLogOutput(t *testing.T) {
+	t.Cleanup(func() { replaceOpenedFiles(nil) })
+	t.Run("writes into the file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.log")
+		t.Setenv("LOG_OUTPUT", path)
+		if _, err := SetupE(); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		slog.Info("info log here")
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read the log file: %s", err)
+		}
+		if !strings.Contains(string(content), "info log here") {
+			t.Errorf("expected the log file to contain the log but got:\n%s", content)
+		}
+	})
+	t.Run("loggers captured before a reload keep writing into the file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.log")
+		t.Setenv("LOG_OUTPUT", path)
+		if _, err := SetupE(); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		captured := slog.Default()
+		if _, err := Reload(); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		runtime.GC()
+		captured.Info("captured before the reload")
+		slog.Info("logged after the reload")
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read the log file: %s", err)
+		}
+		for _, want := range []string{"captured before the reload", "logged after the reload"} {
+			if !strings.Contains(string(content), want) {
+				t.Errorf("expected the log file to contain %q but got:\n%s", want, content)
+			}
+		}
+	})
+	t.Run("the previous file is kept open while a logger writes into it", func(t *testing.T) {
+		dir := t.TempDir()
+		before, after := filepath.Join(dir, "before.log"), filepath.Join(dir, "after.log")
+		t.Setenv("LOG_OUTPUT", before)
+		if _, err := SetupE(); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		captured := slog.Default()
+		t.Setenv("LOG_OUTPUT", after)
+		if _, err := SetupE(); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		runtime.GC()
+		captured.Info("captured before the setup")
+		content, err := os.ReadFile(before)
+		if err != nil {
+			t.Fatalf("failed to read the log file: %s", err)
+		}
+		if want := "captured before the setup"; !strings.Contains(string(content), want) {
+			t.Errorf("expected the log file to contain %q but got:\n%s", want, content)
+		}
+	})
+	t.Run("falls back when the file cannot be opened", func(t *testing.T) {
+		t.Setenv("LOG_OUTPUT", filepath.Join(t.TempDir(), "missing", "app.log"))
+		l, err := SetupE()
+		if err == nil || !strings.Contains(err.Error(), "LOG_OUTPUT") {
+			t.Fatalf("expected an error about LOG_OUTPUT but got: %v", err)
+		}
+		if l == nil {
+			t.Fatalf("expected the fallback logger to be returned")
+		}
+	})
+	t.Run("falls back on stderr with a single warning", func(t *testing.T) {
+		stderr := swapStdFile(t, &os.Stderr)
+		t.Setenv("LOG_OUTPUT", filepath.Join(t.TempDir(), "missing", "app.log"))
+		_, _ = SetupE()
+		slog.Info("info log here")
+
+		content := stderr()
+		if got := strings.Count(content, "level=WARN"); got != 1 {
+			t.Errorf("expected a single warning but got %d:\n%s", got, content)
+		}
+		if !strings.Contains(content, "info log here") {
+			t.Errorf("expected the logs to be written to stderr but got:\n%s", content)
+		}
+	})
+	t.Run("writes to stdout", func(t *testing.T) {
+		stdout := swapStdFile(t, &os.Stdout)
+		t.Setenv("LOG_OUTPUT", "stdout")
+		if _, err := SetupE(); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		slog.Info("info log here")
+		if content := stdout(); !strings.Contains(content, "info log here") {
+			t.Errorf("expected the logs to be written to stdout but got:\n%s", content)
+		}
+	})
+	t.Run("discards the logs", func(t *testing.T) {
+		stderr := swapStdFile(t, &os.Stderr)
+		t.Setenv("LOG_OUTPUT", "discard")
+		if _, err := SetupE(); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		slog.Error("error log here")
+		if content := stderr(); content != "" {
+			t.Errorf("expected no logs but got:\n%s", content)
+		}
+	})
+	t.Run("writer given by SetupWithWriter takes precedence", func(t *testing.T) {
+		t.Setenv("LOG_OUTPUT", "discard")
+		var b bytes.Buffer
+		if _, err := SetupWithWriter(&b); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		slog.Info("info log here")
+		if !strings.Contains(b.String(), "info log here") {
+			t.Errorf("expected the logs to be written to the given writer but got:\n%s", b.String())
+		}
+	})
+}
+
+// swapStdFile replaces the given standard file (ie: os.Stdout) with a temporary one for the duration of the test.
+// The returned function reads what was written into it.
+func swapStdFile(t *testing.T, std **os.File) func() string {
+	t.Helper()
+	f, err := os.Create(filepath.Join(t.TempDir(), "std"))
+	if err != nil {
+		t.Fatalf("failed to create the file: %s", err)
+	}
+	prev := *std
+	*std = f
+	t.Cleanup(func() {
+		*std = prev
+		_ = f.Close()
+	})
+	return func() string {
+		content, err := os.ReadFile(f.Name())
+		if err != nil {
+			t.Fatalf("failed to read the file: %s", err)
+		}
+		return string(content)
+	}
+}
@@ -0,0 +1,153 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	defaultSamplingFirst    = 10
+	defaultSamplingInterval = time.Second
+)
+
+// SamplingOptions configures the [SamplingHandler].
+type SamplingOptions struct {
+	// First is the number of records with the same key allowed per interval. Default: 10
+	First int
+	// Interval is the window in which the records are counted. Default: 1s
+	Interval time.Duration
+	// KeyFunc computes the key by which the records are grouped. Default: level and message
+	KeyFunc func(ctx context.Context, r slog.Record) string
+	// SampleErrors enables the sampling also for the records with level error or higher. Default: false
+	SampleErrors bool
+}
+
+// SamplingHandler returns a handler that rate-limits the repeated records.
+// For each key, the first [SamplingOptions.First] records of an interval are forwarded to the next handler and
+// the rest are dropped. At the end of an interval in which records were dropped, a single summary record is
+// emitted with the number of the suppressed records.
+func SamplingHandler(next slog.Handler, opts SamplingOptions) slog.Handler {
+	if opts.First <= 0 {
+		opts.First = defaultSamplingFirst
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = defaultSamplingInterval
+	}
+	if opts.KeyFunc == nil {
+		opts.KeyFunc = defaultSamplingKey
+	}
+	return &samplingHandler{
+		next: next,
+		opts: opts,
+		state: &samplingState{
+			windows: map[string]*samplingWindow{},
+		},
+	}
+}
+
+func defaultSamplingKey(_ context.Context, r slog.Record) string {
+	return r.Level.String() + ":" + r.Message
+}
+
+type samplingHandler struct {
+	next  slog.Handler
+	opts  SamplingOptions
+	state *samplingState
+}
+
+// samplingState is shared between the handlers derived with WithAttrs and WithGroup.
+type samplingState struct {
+	m         sync.Mutex
+	windows   map[string]*samplingWindow
+	lastSweep time.Time
+}
+
+type samplingWindow struct {
+	start      time.Time
+	count      int
+	suppressed int
+	scheduled  bool
+}
+
+func (h *samplingHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	return h.next.Enabled(ctx, l)
+}
+
+func (h *samplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level >= slog.LevelError && !h.opts.SampleErrors {
+		return h.next.Handle(ctx, r)
+	}
+	if !h.allow(h.opts.KeyFunc(ctx, r), r) {
+		return nil
+	}
+	return h.next.Handle(ctx, r)
+}
+
+func (h *samplingHandler) allow(key string, r slog.Record) bool {
+	s := h.state
+	s.m.Lock()
+	defer s.m.Unlock()
+	now := time.Now()
+	h.sweep(now)
+	w, ok := s.windows[key]
+	if !ok || (!w.scheduled && now.Sub(w.start) >= h.opts.Interval) {
+		w = &samplingWindow{start: now}
+		s.windows[key] = w
+	}
+	w.count++
+	if w.count <= h.opts.First {
+		return true
+	}
+	w.suppressed++
+	if !w.scheduled {
+		w.scheduled = true
+		time.AfterFunc(h.opts.Interval-now.Sub(w.start), func() {
+			h.summarize(key, w, r)
+		})
+	}
+	return false
+}
+
+// summarize emits the summary record for the window and forgets it.
+func (h *samplingHandler) summarize(key string, w *samplingWindow, r slog.Record) {
+	s := h.state
+	s.m.Lock()
+	suppressed := w.suppressed
+	if s.windows[key] == w {
+		delete(s.windows, key)
+	}
+	s.m.Unlock()
+
+	summary := slog.NewRecord(time.Now(), r.Level, "suppressed similar messages", 0)
+	summary.AddAttrs(
+		slog.Int("sampling.suppressed", suppressed),
+		slog.String("sampling.message", r.Message),
+		slog.Duration("sampling.interval", h.opts.Interval),
+	)
+	_ = h.next.Handle(context.Background(), summary)
+}
+
+// sweep removes the expired windows without suppressed records, to keep the memory bounded.
+// This must be called with the lock held.
+func (h *samplingHandler) sweep(now time.Time) {
+	s := h.state
+	if now.Sub(s.lastSweep) < h.opts.Interval {
+		return
+	}
+	s.lastSweep = now
+	for k, w := range s.windows {
+		if !w.scheduled && now.Sub(w.start) >= h.opts.Interval {
+			delete(s.windows, k)
+		}
+	}
+}
+
+func (h *samplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &samplingHandler{next: h.next.WithAttrs(attrs), opts: h.opts, state: h.state}
+}
+
+func (h *samplingHandler) WithGroup(name string) slog.Handler {
+	return &samplingHandler{next: h.next.WithGroup(name), opts: h.opts, state: h.state}
+}
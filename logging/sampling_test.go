@@ -0,0 +1,118 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer is a [bytes.Buffer] safe for the concurrent writes of the summary records.
+type syncBuffer struct {
+	m sync.Mutex
+	b bytes.Buffer
+}
+
+func (s *syncBuffer) Write(p []byte) (int, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.b.Write(p)
+}
+
+func (s *syncBuffer) String() string {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.b.String()
+}
+
+func TestSamplingHandler(t *testing.T) {
+	t.Run("suppresses repeated records and emits a summary", func(t *testing.T) {
+		var b syncBuffer
+		l := slog.New(SamplingHandler(slog.NewTextHandler(&b, nil), SamplingOptions{First: 3, Interval: 50 * time.Millisecond}))
+		for range 100 {
+			l.Warn("retry failed")
+		}
+		l.Warn("other message")
+		if got, want := strings.Count(b.String(), `msg="retry failed"`), 3; got != want {
+			t.Errorf("expected %d records to pass but got %d", want, got)
+		}
+		if !strings.Contains(b.String(), `msg="other message"`) {
+			t.Errorf("expected records with another key to not be affected")
+		}
+		<-time.After(150 * time.Millisecond)
+		if want := "sampling.suppressed=97"; !strings.Contains(b.String(), want) {
+			t.Errorf("expected the summary %q but got:\n%s", want, b.String())
+		}
+		l.Warn("retry failed")
+		if got, want := strings.Count(b.String(), `msg="retry failed"`), 4; got != want {
+			t.Errorf("expected the records to pass again in the new interval, %d vs %d", want, got)
+		}
+	})
+	t.Run("errors are not sampled by default", func(t *testing.T) {
+		var b syncBuffer
+		l := slog.New(SamplingHandler(slog.NewTextHandler(&b, nil), SamplingOptions{First: 1, Interval: time.Minute}))
+		for range 5 {
+			l.Error("failure")
+		}
+		if got, want := strings.Count(b.String(), "msg=failure"), 5; got != want {
+			t.Errorf("expected %d error records but got %d", want, got)
+		}
+	})
+	t.Run("errors are sampled when configured", func(t *testing.T) {
+		var b syncBuffer
+		l := slog.New(SamplingHandler(slog.NewTextHandler(&b, nil), SamplingOptions{First: 1, Interval: time.Minute, SampleErrors: true}))
+		for range 5 {
+			l.Error("failure")
+		}
+		if got, want := strings.Count(b.String(), "msg=failure"), 1; got != want {
+			t.Errorf("expected %d error records but got %d", want, got)
+		}
+	})
+	t.Run("custom key function", func(t *testing.T) {
+		var b syncBuffer
+		byPath := func(_ context.Context, r slog.Record) string {
+			var path string
+			r.Attrs(func(a slog.Attr) bool {
+				if a.Key == "path" {
+					path = a.Value.String()
+					return false
+				}
+				return true
+			})
+			return path
+		}
+		l := slog.New(SamplingHandler(slog.NewTextHandler(&b, nil), SamplingOptions{First: 1, Interval: time.Minute, KeyFunc: byPath}))
+		l.Info("request", "path", "/a")
+		l.Info("another request", "path", "/a")
+		l.Info("request", "path", "/b")
+		if got, want := strings.Count(b.String(), "path=/a"), 1; got != want {
+			t.Errorf("expected %d records for /a but got %d", want, got)
+		}
+		if got, want := strings.Count(b.String(), "path=/b"), 1; got != want {
+			t.Errorf("expected %d records for /b but got %d", want, got)
+		}
+	})
+	t.Run("state is shared with derived handlers", func(t *testing.T) {
+		var b syncBuffer
+		l := slog.New(SamplingHandler(slog.NewTextHandler(&b, nil), SamplingOptions{First: 1, Interval: time.Minute}))
+		l.Info("msg")
+		l.With("a", "b").Info("msg")
+		if got, want := strings.Count(b.String(), "msg=msg"), 1; got != want {
+			t.Errorf("expected %d records but got %d", want, got)
+		}
+	})
+	t.Run("enabled by env var in setup", func(t *testing.T) {
+		t.Setenv("LOG_SAMPLING", "true")
+		var b bytes.Buffer
+		l, err := SetupWithWriter(&b)
+		if err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		if _, ok := l.Handler().(*samplingHandler); !ok {
+			t.Errorf("expected the sampling handler to be installed but got %T", l.Handler())
+		}
+	})
+}
@@ -0,0 +1,11 @@
+//go:build !windows
+
+package logging
+
+import (
+	"os"
+	"syscall"
+)
+
+// toggleSignals are the signals used by [EnableSignalToggle].
+var toggleSignals = []os.Signal{syscall.SIGUSR1}
@@ -0,0 +1,40 @@
+//go:build !windows
+
+package logging
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestEnableSignalToggle(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "warn")
+	var b bytes.Buffer
+	_, _ = SetupWithWriter(&b)
+	EnableSignalToggle()
+	EnableSignalToggle()
+
+	waitForLevel := func(want slog.Level) {
+		t.Helper()
+		deadline := time.After(2 * time.Second)
+		for Level() != want {
+			select {
+			case <-deadline:
+				t.Fatalf("expected level %s but got %s", want, Level())
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+	}
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatalf("failed to send SIGUSR1: %s", err)
+	}
+	waitForLevel(slog.LevelDebug)
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatalf("failed to send SIGUSR1: %s", err)
+	}
+	waitForLevel(slog.LevelWarn)
+}
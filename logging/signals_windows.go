@@ -0,0 +1,8 @@
+//go:build windows
+
+package logging
+
+import "os"
+
+// toggleSignals are the signals used by [EnableSignalToggle]. None is available on Windows.
+var toggleSignals []os.Signal
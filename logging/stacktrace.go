@@ -0,0 +1,73 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"strings"
+)
+
+// StackKey is the key of the attribute added by [StacktraceHandler].
+const StackKey = "stack"
+
+// StacktraceHandler returns a handler that adds the stack trace of the caller, under the [StackKey] key,
+// to the records at or above the given level. The stack starts at the frame that logged the record, so the
+// frames of slog and of the handlers are not included.
+// The stack is a single string attribute, with a frame per line, that the pretty format renders as an indented
+// block. The stack is captured only for the records at or above the level.
+func StacktraceHandler(next slog.Handler, minLevel slog.Level) slog.Handler {
+	return &stacktraceHandler{next: next, minLevel: minLevel}
+}
+
+// WithStacktraces overwrites the LOG_STACK_ON_ERROR env var, adding the stack traces to the records at or
+// above the given level. See [StacktraceHandler].
+func WithStacktraces(minLevel slog.Level) Option {
+	return func(c *config) {
+		c.stacktraces = &minLevel
+	}
+}
+
+type stacktraceHandler struct {
+	next     slog.Handler
+	minLevel slog.Level
+}
+
+func (h *stacktraceHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	return h.next.Enabled(ctx, l)
+}
+
+func (h *stacktraceHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level >= h.minLevel {
+		r = r.Clone()
+		r.AddAttrs(slog.String(StackKey, callerStack(r.PC)))
+	}
+	return h.next.Handle(ctx, r)
+}
+
+func (h *stacktraceHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &stacktraceHandler{next: h.next.WithAttrs(attrs), minLevel: h.minLevel}
+}
+
+func (h *stacktraceHandler) WithGroup(name string) slog.Handler {
+	return &stacktraceHandler{next: h.next.WithGroup(name), minLevel: h.minLevel}
+}
+
+// callerStack formats the current stack starting with the frame of the given program counter.
+// When the frame is not found (ie: the record was created manually), the leading frames of slog
+// and of this package are skipped instead.
+func callerStack(pc uintptr) string {
+	pcs := make([]uintptr, stackMaxDepth*2)
+	pcs = pcs[:runtime.Callers(2, pcs)]
+	for i, p := range pcs {
+		if pc != 0 && p == pc {
+			return formatStack(pcs[i:])
+		}
+	}
+	for i, p := range pcs {
+		f, _ := runtime.CallersFrames([]uintptr{p}).Next()
+		if !strings.HasPrefix(f.Function, "log/slog.") && !strings.HasPrefix(f.Function, "github.com/yottta/go-core/logging.") {
+			return formatStack(pcs[i:])
+		}
+	}
+	return formatStack(pcs)
+}
@@ -0,0 +1,71 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestStacktraceHandler(t *testing.T) {
+	t.Run("stack starts at the caller", func(t *testing.T) {
+		var b bytes.Buffer
+		l := slog.New(StacktraceHandler(slog.NewJSONHandler(&b, nil), slog.LevelError))
+		l.With("key", "value").Error("failed")
+
+		var rec map[string]any
+		if err := json.Unmarshal(b.Bytes(), &rec); err != nil {
+			t.Fatalf("failed to parse the record %q: %s", b.String(), err)
+		}
+		stack, ok := rec[StackKey].(string)
+		if !ok {
+			t.Fatalf("expected the record to contain a string %q attribute but got: %v", StackKey, rec)
+		}
+		firstFrame := strings.SplitN(stack, "\n", 2)[0]
+		if want := "TestStacktraceHandler"; !strings.Contains(firstFrame, want) {
+			t.Errorf("expected the first frame to be the caller but got %q in:\n%s", firstFrame, stack)
+		}
+		if strings.Contains(stack, "log/slog.") {
+			t.Errorf("expected the slog frames to be skipped but got:\n%s", stack)
+		}
+	})
+	t.Run("records below the level have no stack", func(t *testing.T) {
+		var b bytes.Buffer
+		l := slog.New(StacktraceHandler(slog.NewTextHandler(&b, nil), slog.LevelError))
+		l.Warn("warn log here")
+		if strings.Contains(b.String(), StackKey+"=") {
+			t.Errorf("expected no stack but got: %s", b.String())
+		}
+	})
+	t.Run("LOG_STACK_ON_ERROR", func(t *testing.T) {
+		t.Setenv("LOG_STACK_ON_ERROR", "true")
+		var b bytes.Buffer
+		if _, err := SetupWithWriter(&b); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		slog.Warn("warn log here")
+		slog.Error("error log here")
+		lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("expected 2 records but got:\n%s", b.String())
+		}
+		if strings.Contains(lines[0], StackKey+"=") {
+			t.Errorf("expected no stack for the warning but got: %s", lines[0])
+		}
+		if !strings.Contains(lines[1], StackKey+"=") {
+			t.Errorf("expected a stack for the error but got: %s", lines[1])
+		}
+	})
+	t.Run("WithStacktraces overwrites the env var", func(t *testing.T) {
+		t.Setenv("LOG_STACK_ON_ERROR", "false")
+		var b bytes.Buffer
+		if _, err := SetupWithWriter(&b, WithStacktraces(slog.LevelWarn)); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		slog.Warn("warn log here")
+		if !strings.Contains(b.String(), StackKey+"=") {
+			t.Errorf("expected a stack for the warning but got: %s", b.String())
+		}
+	})
+}
@@ -0,0 +1,157 @@
+package logging
+
+import (
+	"context"
+	"expvar"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+)
+
+// stats counts the records written through the handlers configured by [Setup].
+var stats = &recordStats{}
+
+// Stats returns a snapshot of the number of records written per level since the logging was set up
+// (or since the last [ResetStats]). This is a cheap way to alert on the rate of the error logs.
+func Stats() map[slog.Level]uint64 {
+	return stats.levels()
+}
+
+// LoggerStats is the same as [Stats] but split per the name of the loggers created with [Named].
+// The records of the loggers without a name are not included.
+func LoggerStats() map[string]map[slog.Level]uint64 {
+	return stats.loggers()
+}
+
+// ResetStats resets all the counters. This is mainly useful in tests.
+func ResetStats() {
+	stats.reset()
+}
+
+// CollectStats calls the given function with each counter, allowing to export them into a metrics system
+// (ie: from the Collect method of a Prometheus collector). The logger is empty for the total per level.
+func CollectStats(fn func(level slog.Level, logger string, count uint64)) {
+	for l, c := range stats.levels() {
+		fn(l, "", c)
+	}
+	for name, levels := range stats.loggers() {
+		for l, c := range levels {
+			fn(l, name, c)
+		}
+	}
+}
+
+// ExpvarStats returns the counters of [Stats] as an [expvar.Var], keyed by the level names.
+// Publish it with expvar.Publish("logging", logging.ExpvarStats()).
+func ExpvarStats() expvar.Var {
+	return expvar.Func(func() any {
+		res := map[string]uint64{}
+		for l, c := range stats.levels() {
+			res[l.String()] = c
+		}
+		return res
+	})
+}
+
+// StatsHandler returns a handler counting the records that it writes into next, as reported by [Stats].
+// This is configured by default by [Setup].
+func StatsHandler(next slog.Handler) slog.Handler {
+	return &statsHandler{next: next, stats: stats}
+}
+
+type recordStats struct {
+	// perLevel holds *atomic.Uint64 keyed by slog.Level
+	perLevel sync.Map
+	// perLogger holds *atomic.Uint64 keyed by loggerLevel
+	perLogger sync.Map
+}
+
+type loggerLevel struct {
+	logger string
+	level  slog.Level
+}
+
+func (s *recordStats) inc(logger string, l slog.Level) {
+	counter(&s.perLevel, l).Add(1)
+	if logger != "" {
+		counter(&s.perLogger, loggerLevel{logger: logger, level: l}).Add(1)
+	}
+}
+
+func counter[K comparable](m *sync.Map, k K) *atomic.Uint64 {
+	if c, ok := m.Load(k); ok {
+		return c.(*atomic.Uint64)
+	}
+	c, _ := m.LoadOrStore(k, &atomic.Uint64{})
+	return c.(*atomic.Uint64)
+}
+
+func (s *recordStats) levels() map[slog.Level]uint64 {
+	res := map[slog.Level]uint64{}
+	s.perLevel.Range(func(k, v any) bool {
+		res[k.(slog.Level)] = v.(*atomic.Uint64).Load()
+		return true
+	})
+	return res
+}
+
+func (s *recordStats) loggers() map[string]map[slog.Level]uint64 {
+	res := map[string]map[slog.Level]uint64{}
+	s.perLogger.Range(func(k, v any) bool {
+		ll := k.(loggerLevel)
+		if res[ll.logger] == nil {
+			res[ll.logger] = map[slog.Level]uint64{}
+		}
+		res[ll.logger][ll.level] = v.(*atomic.Uint64).Load()
+		return true
+	})
+	return res
+}
+
+func (s *recordStats) reset() {
+	s.perLevel.Clear()
+	s.perLogger.Clear()
+}
+
+type statsHandler struct {
+	next   slog.Handler
+	stats  *recordStats
+	logger string
+	// grouped is true once a group was opened, after which the attributes cannot set the logger name anymore.
+	grouped bool
+}
+
+func (h *statsHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	return h.next.Enabled(ctx, l)
+}
+
+func (h *statsHandler) Handle(ctx context.Context, r slog.Record) error {
+	logger := h.logger
+	if !h.grouped {
+		r.Attrs(func(a slog.Attr) bool {
+			if a.Key == LoggerKey {
+				logger = a.Value.String()
+				return false
+			}
+			return true
+		})
+	}
+	h.stats.inc(logger, r.Level)
+	return h.next.Handle(ctx, r)
+}
+
+func (h *statsHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	logger := h.logger
+	if !h.grouped {
+		for _, a := range attrs {
+			if a.Key == LoggerKey {
+				logger = a.Value.String()
+			}
+		}
+	}
+	return &statsHandler{next: h.next.WithAttrs(attrs), stats: h.stats, logger: logger, grouped: h.grouped}
+}
+
+func (h *statsHandler) WithGroup(name string) slog.Handler {
+	return &statsHandler{next: h.next.WithGroup(name), stats: h.stats, logger: h.logger, grouped: true}
+}
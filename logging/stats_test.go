@@ -0,0 +1,73 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"maps"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	t.Run("counts the written records per level", func(t *testing.T) {
+		t.Setenv("LOG_LEVEL", "info,storage=debug")
+		var b bytes.Buffer
+		if _, err := SetupWithWriter(&b); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		ResetStats()
+		writeAllLevelLogs()
+		slog.Error("another error")
+		Named("storage").Debug("storage debug")
+		slog.With(LoggerKey, "storage").WithGroup("g").Error("storage error")
+
+		want := map[slog.Level]uint64{
+			slog.LevelDebug: 1,
+			slog.LevelInfo:  1,
+			slog.LevelWarn:  1,
+			slog.LevelError: 3,
+		}
+		if got := Stats(); !maps.Equal(got, want) {
+			t.Errorf("got a different value than the wanted one. expected: %v; got: %v", want, got)
+		}
+		wantStorage := map[slog.Level]uint64{slog.LevelDebug: 1, slog.LevelError: 1}
+		if got := LoggerStats()["storage"]; !maps.Equal(got, wantStorage) {
+			t.Errorf("got a different value than the wanted one. expected: %v; got: %v", wantStorage, got)
+		}
+
+		var collected uint64
+		CollectStats(func(_ slog.Level, logger string, count uint64) {
+			if logger == "" {
+				collected += count
+			}
+		})
+		if got, want := collected, uint64(6); got != want {
+			t.Errorf("got a different total than the wanted one. expected: %d; got: %d", want, got)
+		}
+
+		var exported map[string]uint64
+		if err := json.Unmarshal([]byte(ExpvarStats().String()), &exported); err != nil {
+			t.Fatalf("failed to parse the expvar: %s", err)
+		}
+		if got, want := exported["ERROR"], uint64(3); got != want {
+			t.Errorf("got a different value than the wanted one. expected: %d; got: %d", want, got)
+		}
+	})
+	t.Run("snapshot is a copy", func(t *testing.T) {
+		var b bytes.Buffer
+		if _, err := SetupWithWriter(&b); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		ResetStats()
+		slog.Error("error log here")
+		snapshot := Stats()
+		slog.Error("error log here")
+		if got, want := snapshot[slog.LevelError], uint64(1); got != want {
+			t.Errorf("expected the snapshot to not change. expected: %d; got: %d", want, got)
+		}
+		ResetStats()
+		if got := Stats(); len(got) != 0 {
+			t.Errorf("expected no counters after reset but got: %v", got)
+		}
+	})
+}
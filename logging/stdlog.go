@@ -0,0 +1,88 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"log/slog"
+	"os"
+	"reflect"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// stdLogTimestamp matches the date and time prefix written by the standard library log package.
+var stdLogTimestamp = regexp.MustCompile(`^(\d{4}/\d{2}/\d{2} )?(\d{2}:\d{2}:\d{2}(\.\d{1,6})? )?`)
+
+var (
+	stdLogRedirectM sync.Mutex
+	// stdLogRedirect is the writer configured by [RedirectStdLog], nil when not redirected
+	stdLogRedirect *stdLogWriter
+)
+
+// RedirectStdLog points the output of the standard library log package to [slog.Default], at the given level.
+// This way, the lines written by the third-party code and by net/http through the log package get the level,
+// the format and the attributes of the rest of the logs.
+// The redirection is kept when the logging is configured again with [Setup].
+func RedirectStdLog(level slog.Level) {
+	stdLogRedirectM.Lock()
+	defer stdLogRedirectM.Unlock()
+	stdLogRedirect = &stdLogWriter{level: level}
+	applyStdLogRedirect()
+}
+
+// StdLogger returns a [log.Logger] that writes each line into [slog.Default] at the given level.
+// This is useful for the APIs that accept only a [log.Logger], like [net/http.Server.ErrorLog].
+func StdLogger(level slog.Level) *log.Logger {
+	return log.New(&stdLogWriter{level: level}, "", 0)
+}
+
+// applyStdLogRedirect configures again the log package since [slog.SetDefault] replaces its output.
+// It must be called with stdLogRedirectM locked.
+func applyStdLogRedirect() {
+	if stdLogRedirect == nil {
+		return
+	}
+	log.SetOutput(stdLogRedirect)
+	log.SetFlags(0)
+}
+
+// reapplyStdLogRedirect is called after the default logger is replaced.
+func reapplyStdLogRedirect() {
+	stdLogRedirectM.Lock()
+	defer stdLogRedirectM.Unlock()
+	applyStdLogRedirect()
+}
+
+// stdLogWriter writes each line into [slog.Default], as read when the line is written.
+type stdLogWriter struct {
+	level slog.Level
+}
+
+func (w *stdLogWriter) Write(p []byte) (int, error) {
+	h := slog.Default().Handler()
+	if isSlogDefaultHandler(h) {
+		// the default handler of slog writes through the log package, so using it would deadlock
+		return os.Stderr.Write(p)
+	}
+	ctx := context.Background()
+	if !h.Enabled(ctx, w.level) {
+		return len(p), nil
+	}
+	for line := range bytes.SplitSeq(bytes.TrimRight(p, "\n"), []byte("\n")) {
+		line = stdLogTimestamp.ReplaceAll(line, nil)
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		if err := h.Handle(ctx, slog.NewRecord(time.Now(), w.level, string(line), 0)); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// isSlogDefaultHandler reports whether the handler is the one used by slog before [slog.SetDefault] is called.
+func isSlogDefaultHandler(h slog.Handler) bool {
+	return reflect.TypeOf(h).String() == "*slog.defaultHandler"
+}
@@ -0,0 +1,74 @@
+package logging
+
+import (
+	"bytes"
+	"log"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestStdLog(t *testing.T) {
+	t.Run("StdLogger writes each line through slog", func(t *testing.T) {
+		var b bytes.Buffer
+		if _, err := SetupWithWriter(&b); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		l := StdLogger(slog.LevelWarn)
+		l.Print("http: TLS handshake error\nsecond line\n")
+
+		lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("expected 2 records but got:\n%s", b.String())
+		}
+		for i, want := range []string{`level=WARN msg="http: TLS handshake error"`, `level=WARN msg="second line"`} {
+			if !strings.Contains(lines[i], want) {
+				t.Errorf("expected %q to contain %q", lines[i], want)
+			}
+		}
+	})
+	t.Run("StdLogger respects the level", func(t *testing.T) {
+		t.Setenv("LOG_LEVEL", "error")
+		var b bytes.Buffer
+		if _, err := SetupWithWriter(&b); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		StdLogger(slog.LevelInfo).Print("info log here")
+		if b.Len() != 0 {
+			t.Errorf("expected no logs but got:\n%s", b.String())
+		}
+	})
+	t.Run("RedirectStdLog strips the timestamps and survives setup", func(t *testing.T) {
+		prevOutput, prevFlags := log.Writer(), log.Flags()
+		t.Cleanup(func() {
+			stdLogRedirectM.Lock()
+			stdLogRedirect = nil
+			stdLogRedirectM.Unlock()
+			log.SetOutput(prevOutput)
+			log.SetFlags(prevFlags)
+		})
+		var b bytes.Buffer
+		RedirectStdLog(slog.LevelError)
+		if _, err := SetupWithWriter(&b); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		log.SetFlags(log.LstdFlags | log.Lmicroseconds)
+		log.Print("legacy log here")
+
+		if want := `level=ERROR msg="legacy log here"`; !strings.Contains(b.String(), want) {
+			t.Errorf("expected %q to contain %q", b.String(), want)
+		}
+	})
+	t.Run("timestamps are stripped", func(t *testing.T) {
+		for in, want := range map[string]string{
+			"2009/11/10 23:00:00 message":        "message",
+			"2009/11/10 23:00:00.123456 message": "message",
+			"23:00:00 message":                   "message",
+			"message 2009/11/10":                 "message 2009/11/10",
+		} {
+			if got := stdLogTimestamp.ReplaceAllString(in, ""); got != want {
+				t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+			}
+		}
+	})
+}
@@ -0,0 +1,141 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+type ctxKeyTail struct{}
+
+// ctxKeyTailFlush marks the context of the records flushed by [TailHandler], so the leveling of the
+// handlers down the chain can let them pass.
+type ctxKeyTailFlush struct{}
+
+// TailHandler returns a handler that keeps the last n records that are not enabled by the next handler
+// (ie: debug records) and, when a record at error level (or above) is handled, writes the kept records ahead of it.
+// This gives the context preceding an error without having the debug logs always on.
+//
+// The records are kept in a ring buffer of n records, the oldest being evicted when it's full, so the memory
+// is bounded regardless of how long a scope lives. By default, a single buffer is shared by all the records.
+// Use [TailScope] to give a context (ie: a request) its own buffer, so an error flushes only the records of the
+// same scope. The buffer is emptied on each flush.
+//
+// Since it needs to see all the records, this handler is enabled for all the levels.
+func TailHandler(next slog.Handler, n int) slog.Handler {
+	return &tailHandler{
+		next:   next,
+		shared: newTailBuffer(n),
+		n:      n,
+	}
+}
+
+// TailScope returns a new context with its own buffer for the records handled by [TailHandler].
+// The buffer is released together with the context.
+func TailScope(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ctxKeyTail{}, &tailScope{})
+}
+
+// isTailFlush reports whether the record is flushed by a [TailHandler].
+func isTailFlush(ctx context.Context) bool {
+	return ctx.Value(ctxKeyTailFlush{}) != nil
+}
+
+type tailHandler struct {
+	next   slog.Handler
+	shared *tailBuffer
+	n      int
+}
+
+// tailScope lazily creates the buffer since the size is known only by the handler.
+type tailScope struct {
+	once sync.Once
+	buf  *tailBuffer
+}
+
+func (h *tailHandler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+func (h *tailHandler) Handle(ctx context.Context, r slog.Record) error {
+	buf := h.buffer(ctx)
+	if r.Level >= slog.LevelError {
+		flushCtx := context.WithValue(ctx, ctxKeyTailFlush{}, true)
+		for _, e := range buf.drain() {
+			_ = e.next.Handle(flushCtx, e.record)
+		}
+		return h.next.Handle(ctx, r)
+	}
+	if h.next.Enabled(ctx, r.Level) {
+		return h.next.Handle(ctx, r)
+	}
+	buf.add(tailEntry{next: h.next, record: r.Clone()})
+	return nil
+}
+
+func (h *tailHandler) buffer(ctx context.Context) *tailBuffer {
+	s, ok := ctx.Value(ctxKeyTail{}).(*tailScope)
+	if !ok {
+		return h.shared
+	}
+	s.once.Do(func() {
+		s.buf = newTailBuffer(h.n)
+	})
+	return s.buf
+}
+
+func (h *tailHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &tailHandler{next: h.next.WithAttrs(attrs), shared: h.shared, n: h.n}
+}
+
+func (h *tailHandler) WithGroup(name string) slog.Handler {
+	return &tailHandler{next: h.next.WithGroup(name), shared: h.shared, n: h.n}
+}
+
+type tailEntry struct {
+	// next is the handler that received the record, keeping its attributes and groups
+	next   slog.Handler
+	record slog.Record
+}
+
+// tailBuffer is a ring buffer of records.
+type tailBuffer struct {
+	m       sync.Mutex
+	entries []tailEntry
+	start   int
+	size    int
+}
+
+func newTailBuffer(n int) *tailBuffer {
+	return &tailBuffer{entries: make([]tailEntry, max(n, 0))}
+}
+
+func (b *tailBuffer) add(e tailEntry) {
+	b.m.Lock()
+	defer b.m.Unlock()
+	if len(b.entries) == 0 {
+		return
+	}
+	idx := (b.start + b.size) % len(b.entries)
+	b.entries[idx] = e
+	if b.size < len(b.entries) {
+		b.size++
+		return
+	}
+	// full, so the oldest one was overwritten
+	b.start = (b.start + 1) % len(b.entries)
+}
+
+// drain returns the records from the oldest to the newest and empties the buffer.
+func (b *tailBuffer) drain() []tailEntry {
+	b.m.Lock()
+	defer b.m.Unlock()
+	res := make([]tailEntry, 0, b.size)
+	for i := range b.size {
+		idx := (b.start + i) % len(b.entries)
+		res = append(res, b.entries[idx])
+		b.entries[idx] = tailEntry{}
+	}
+	b.start, b.size = 0, 0
+	return res
+}
@@ -0,0 +1,97 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestTailHandler(t *testing.T) {
+	t.Run("error flushes the buffered records ahead of it", func(t *testing.T) {
+		var b bytes.Buffer
+		l := slog.New(TailHandler(slog.NewTextHandler(&b, &slog.HandlerOptions{Level: slog.LevelInfo}), 3))
+		for i := range 10 {
+			l.Debug(fmt.Sprintf("debug %d", i))
+		}
+		l.Info("info passes")
+		if got := b.String(); strings.Contains(got, "debug") {
+			t.Errorf("expected the debug records to be buffered but got:\n%s", got)
+		}
+		l.With("component", "db").Error("query failed")
+
+		lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+		want := []string{"info passes", "debug 7", "debug 8", "debug 9", "query failed"}
+		if len(lines) != len(want) {
+			t.Fatalf("expected %d records but got %d:\n%s", len(want), len(lines), b.String())
+		}
+		for i, w := range want {
+			if !strings.Contains(lines[i], w) {
+				t.Errorf("expected record %d to contain %q but got: %s", i, w, lines[i])
+			}
+		}
+		if !strings.Contains(lines[4], "component=db") {
+			t.Errorf("expected the attributes of the error to be kept but got: %s", lines[4])
+		}
+
+		b.Reset()
+		l.Error("second error")
+		if got := strings.Count(b.String(), "\n"); got != 1 {
+			t.Errorf("expected the buffer to be emptied by the previous flush but got:\n%s", b.String())
+		}
+	})
+	t.Run("buffered records keep the attributes of their loggers", func(t *testing.T) {
+		var b bytes.Buffer
+		l := slog.New(TailHandler(slog.NewTextHandler(&b, &slog.HandlerOptions{Level: slog.LevelInfo}), 3))
+		l.WithGroup("req").With("id", "1").Debug("scoped debug")
+		l.Error("failed")
+		if want := "msg=\"scoped debug\" req.id=1"; !strings.Contains(b.String(), want) {
+			t.Errorf("expected %q to contain %q", b.String(), want)
+		}
+	})
+	t.Run("scopes flush only their own records", func(t *testing.T) {
+		var b bytes.Buffer
+		l := slog.New(TailHandler(slog.NewTextHandler(&b, &slog.HandlerOptions{Level: slog.LevelInfo}), 5))
+		ctxA := TailScope(context.Background())
+		ctxB := TailScope(context.Background())
+		l.DebugContext(ctxA, "debug of a")
+		l.DebugContext(ctxB, "debug of b")
+		l.Debug("unscoped debug")
+		l.ErrorContext(ctxA, "error of a")
+
+		got := b.String()
+		if !strings.Contains(got, "debug of a") {
+			t.Errorf("expected the records of the scope to be flushed but got:\n%s", got)
+		}
+		if strings.Contains(got, "debug of b") || strings.Contains(got, "unscoped debug") {
+			t.Errorf("expected the records of the other scopes to be kept but got:\n%s", got)
+		}
+	})
+	t.Run("zero size keeps nothing", func(t *testing.T) {
+		var b bytes.Buffer
+		l := slog.New(TailHandler(slog.NewTextHandler(&b, &slog.HandlerOptions{Level: slog.LevelInfo}), 0))
+		l.Debug("debug log here")
+		l.Error("error log here")
+		assertLogs(t, b.String(), false, false, false, true)
+	})
+	t.Run("LOG_TAIL_ON_ERROR", func(t *testing.T) {
+		t.Setenv("LOG_LEVEL", "error")
+		t.Setenv("LOG_TAIL_ON_ERROR", "2")
+		var b bytes.Buffer
+		if _, err := SetupWithWriter(&b); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		writeAllLevelLogs()
+		assertLogs(t, b.String(), false, true, true, true)
+	})
+	t.Run("invalid LOG_TAIL_ON_ERROR", func(t *testing.T) {
+		t.Setenv("LOG_TAIL_ON_ERROR", "many")
+		var b bytes.Buffer
+		_, err := SetupWithWriter(&b)
+		if want := `invalid LOG_TAIL_ON_ERROR "many": expected a non-negative integer`; err == nil || err.Error() != want {
+			t.Errorf("got a different error than the wanted one. expected: %q; got: %v", want, err)
+		}
+	})
+}
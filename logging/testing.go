@@ -0,0 +1,52 @@
+package logging
+
+import (
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Discard installs a default logger that drops all the records.
+func Discard() {
+	slog.SetDefault(slog.New(slog.DiscardHandler))
+}
+
+// SetupForTest installs a default logger that writes the records, at any level, through t.Log, so these are shown
+// only when the test fails or runs in verbose mode. The previous default logger is restored in t.Cleanup.
+// The records logged after the test finished, by the goroutines that outlived it, are dropped instead of
+// making t.Log panic.
+// Since [slog.Default] is global, this cannot be used by tests running with t.Parallel.
+func SetupForTest(t testing.TB) {
+	t.Helper()
+	w := &testWriter{t: t}
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: minLevel})))
+	t.Cleanup(func() {
+		slog.SetDefault(prev)
+		w.finish()
+	})
+}
+
+// testWriter writes through t.Log until the test finishes. The slog handlers write each record with a single
+// call of Write.
+type testWriter struct {
+	t    testing.TB
+	m    sync.Mutex
+	done bool
+}
+
+func (w *testWriter) Write(p []byte) (int, error) {
+	w.m.Lock()
+	defer w.m.Unlock()
+	if !w.done {
+		w.t.Log(strings.TrimSuffix(string(p), "\n"))
+	}
+	return len(p), nil
+}
+
+func (w *testWriter) finish() {
+	w.m.Lock()
+	defer w.m.Unlock()
+	w.done = true
+}
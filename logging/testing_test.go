@@ -0,0 +1,72 @@
+package logging
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestDiscard(t *testing.T) {
+	prev := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(prev) })
+	Discard()
+	if slog.Default().Enabled(t.Context(), slog.LevelError) {
+		t.Errorf("expected the default logger to drop all the records")
+	}
+}
+
+func TestSetupForTest(t *testing.T) {
+	t.Run("records are written through t.Log", func(t *testing.T) {
+		rec := &recordingTB{TB: t}
+		prev := slog.Default()
+		t.Cleanup(func() { slog.SetDefault(prev) })
+
+		SetupForTest(rec)
+		slog.Debug("debug log here", "key", "value")
+		if len(rec.logs) != 1 || !strings.Contains(rec.logs[0], `msg="debug log here" key=value`) {
+			t.Errorf("expected the record to be written through t.Log but got: %q", rec.logs)
+		}
+
+		rec.runCleanups()
+		if got := slog.Default(); got != prev {
+			t.Errorf("expected the previous default logger to be restored")
+		}
+	})
+	t.Run("records after the test finished are dropped", func(t *testing.T) {
+		rec := &recordingTB{TB: t}
+		prev := slog.Default()
+		t.Cleanup(func() { slog.SetDefault(prev) })
+
+		SetupForTest(rec)
+		l := slog.Default()
+		rec.runCleanups()
+		l.Info("log from a leaked goroutine")
+		if len(rec.logs) != 0 {
+			t.Errorf("expected no record after the test finished but got: %q", rec.logs)
+		}
+	})
+}
+
+// recordingTB captures the calls of Log and Cleanup, to be able to simulate the end of a test.
+type recordingTB struct {
+	testing.TB
+	logs     []string
+	cleanups []func()
+}
+
+func (r *recordingTB) Log(args ...any) {
+	for _, a := range args {
+		r.logs = append(r.logs, a.(string))
+	}
+}
+
+func (r *recordingTB) Cleanup(f func()) {
+	r.cleanups = append(r.cleanups, f)
+}
+
+func (r *recordingTB) runCleanups() {
+	for i := len(r.cleanups) - 1; i >= 0; i-- {
+		r.cleanups[i]()
+	}
+	r.cleanups = nil
+}
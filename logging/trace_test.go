@@ -0,0 +1,69 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestWithTraceContext(t *testing.T) {
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10},
+		SpanID:     trace.SpanID{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08},
+		TraceFlags: trace.FlagsSampled,
+	})
+	spanCtx := trace.ContextWithSpanContext(context.Background(), sc)
+
+	t.Run("adds trace and span ids", func(t *testing.T) {
+		var b bytes.Buffer
+		l := slog.New(WithTraceContext(slog.NewTextHandler(&b, nil)))
+		l.With("attr", "val").WithGroup("g").InfoContext(spanCtx, "with span")
+		for _, want := range []string{"g.trace_id=" + sc.TraceID().String(), "g.span_id=" + sc.SpanID().String(), "attr=val"} {
+			if !strings.Contains(b.String(), want) {
+				t.Errorf("expected logs to contain %q but got:\n%s", want, b.String())
+			}
+		}
+	})
+	t.Run("records without span are untouched", func(t *testing.T) {
+		var b bytes.Buffer
+		l := slog.New(WithTraceContext(slog.NewTextHandler(&b, nil)))
+		l.InfoContext(context.Background(), "without span")
+		if strings.Contains(b.String(), "trace_id") || strings.Contains(b.String(), "span_id") {
+			t.Errorf("expected logs to not contain trace attributes but got:\n%s", b.String())
+		}
+	})
+	t.Run("records without span do not allocate", func(t *testing.T) {
+		h := WithTraceContext(noopHandler{})
+		r := slog.NewRecord(time.Now(), slog.LevelInfo, "msg", 0)
+		ctx := context.Background()
+		allocs := testing.AllocsPerRun(100, func() {
+			_ = h.Handle(ctx, r)
+		})
+		if allocs != 0 {
+			t.Errorf("expected no allocation but got %f", allocs)
+		}
+	})
+	t.Run("enabled by env var in setup", func(t *testing.T) {
+		t.Setenv("LOG_TRACE_CORRELATION", "true")
+		var b bytes.Buffer
+		if _, err := SetupWithWriter(&b); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		slog.InfoContext(spanCtx, "with span")
+		if want := "trace_id=" + sc.TraceID().String(); !strings.Contains(b.String(), want) {
+			t.Errorf("expected logs to contain %q but got:\n%s", want, b.String())
+		}
+	})
+}
+
+type noopHandler struct{}
+
+func (noopHandler) Enabled(context.Context, slog.Level) bool  { return true }
+func (noopHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h noopHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h noopHandler) WithGroup(string) slog.Handler           { return h }
@@ -0,0 +1,46 @@
+package shutdown
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// Graceful is the same as [Context] but the returned context is cancelled only after the grace period following the
+// first signal, allowing the process to keep serving while it's taken out of the load balancers.
+// A second signal received during the grace period cancels the context right away.
+// The [context.Cause] of the context names the signals and the grace period (ie: "shutdown: received signal SIGTERM
+// and the grace period of 5s elapsed") and [SignalFromContext] returns the first signal.
+func Graceful(parent context.Context, grace time.Duration, sigs ...os.Signal) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(parent)
+	ch, stop := chanWithStop(siteGraceful, sigs...)
+	go func() {
+		defer stop()
+		var sig os.Signal
+		select {
+		case s, ok := <-ch:
+			if !ok {
+				return
+			}
+			sig = s
+		case <-ctx.Done():
+			return
+		}
+
+		t := time.NewTimer(grace)
+		defer t.Stop()
+		select {
+		case <-t.C:
+			cancel(&signalError{sig: sig, grace: grace})
+		case s, ok := <-ch:
+			if ok {
+				cancel(&signalError{sig: sig, grace: grace, interrupt: s})
+			}
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, func() {
+		cancel(nil)
+		stop()
+	}
+}
@@ -0,0 +1,65 @@
+package shutdown
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestGraceful(t *testing.T) {
+	EnableTestMode()
+	// the other tests rely on the real signals
+	t.Cleanup(func() { testMode.Store(false) })
+
+	t.Run("cancelled after the grace period", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			ctx, cancel := Graceful(context.Background(), 5*time.Second)
+			defer cancel()
+			Trigger(syscall.SIGTERM)
+			<-time.After(4 * time.Second)
+			if ctx.Err() != nil {
+				t.Fatalf("expected the context to not be cancelled during the grace period")
+			}
+			<-time.After(time.Second)
+			synctest.Wait()
+			if ctx.Err() == nil {
+				t.Fatalf("expected the context to be cancelled after the grace period")
+			}
+			if got, want := context.Cause(ctx).Error(), "shutdown: received signal SIGTERM and the grace period of 5s elapsed"; got != want {
+				t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+			}
+			if sig, ok := SignalFromContext(ctx); !ok || sig != syscall.SIGTERM {
+				t.Errorf("expected the context to be cancelled by SIGTERM but got %v, %t", sig, ok)
+			}
+		})
+	})
+	t.Run("second signal cancels right away", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			ctx, cancel := Graceful(context.Background(), 5*time.Second)
+			defer cancel()
+			Trigger(syscall.SIGTERM)
+			synctest.Wait()
+			Trigger(syscall.SIGINT)
+			synctest.Wait()
+			if ctx.Err() == nil {
+				t.Fatalf("expected the context to be cancelled by the second signal")
+			}
+			if got, want := context.Cause(ctx).Error(), "shutdown: received signal SIGTERM, then SIGINT during the grace period of 5s"; got != want {
+				t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+			}
+		})
+	})
+	t.Run("cause of the parent is kept", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			parent, cancelParent := context.WithCancelCause(context.Background())
+			ctx, cancel := Graceful(parent, 5*time.Second)
+			defer cancel()
+			cancelParent(context.DeadlineExceeded)
+			if got := context.Cause(ctx); got != context.DeadlineExceeded {
+				t.Errorf("got a different value than the wanted one. expected: %q; got: %q", context.DeadlineExceeded, got)
+			}
+		})
+	})
+}
@@ -0,0 +1,80 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Group coordinates the draining of background workers (ie: queue pollers): the workers started with [Group.Go]
+// get a context that is cancelled once the process receives a signal, or once the parent context is done, and
+// [Group.Wait] gives them a limited time to finish their work.
+// The cause of the cancellation is available to the workers through [context.Cause] and [SignalFromContext].
+type Group struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	wg      sync.WaitGroup
+	running atomic.Int64
+
+	m    sync.Mutex
+	errs []error
+}
+
+// NewGroup returns a [Group] whose workers are cancelled when ctx is done or when the process receives one of the
+// [defaultSigs]. The signals can be overwritten, like for [Context].
+func NewGroup(ctx context.Context, overwriteSignals ...os.Signal) *Group {
+	ctx, cancel := Context(ctx, overwriteSignals...)
+	return &Group{ctx: ctx, cancel: cancel}
+}
+
+// Go starts the given function in a new goroutine. The given context is cancelled once the group is asked to stop.
+// The errors returned by the function are reported by [Group.Wait], except for [context.Canceled].
+func (g *Group) Go(f func(ctx context.Context) error) {
+	g.running.Add(1)
+	g.wg.Go(func() {
+		defer g.running.Add(-1)
+		if err := f(g.ctx); err != nil && !errors.Is(err, context.Canceled) {
+			g.m.Lock()
+			g.errs = append(g.errs, err)
+			g.m.Unlock()
+		}
+	})
+}
+
+// Wait blocks until all the functions started with [Group.Go] return or until the group is asked to stop. In the
+// latter case, it waits at most drainTimeout for the functions to return.
+// It returns the errors of the functions joined, together with an error reporting the number of the functions that
+// did not finish within the timeout, if any.
+func (g *Group) Wait(drainTimeout time.Duration) error {
+	defer g.cancel()
+	doneCh := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(doneCh)
+	}()
+	select {
+	case <-doneCh:
+		return g.err(nil)
+	case <-g.ctx.Done():
+	}
+
+	t := time.NewTimer(drainTimeout)
+	defer t.Stop()
+	select {
+	case <-doneCh:
+		return g.err(nil)
+	case <-t.C:
+		return g.err(fmt.Errorf("%d workers did not finish within %s", g.running.Load(), drainTimeout))
+	}
+}
+
+func (g *Group) err(timeoutErr error) error {
+	g.m.Lock()
+	defer g.m.Unlock()
+	return errors.Join(append(g.errs, timeoutErr)...)
+}
@@ -0,0 +1,77 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"syscall"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestGroup(t *testing.T) {
+	EnableTestMode()
+	// the other tests rely on the real signals
+	t.Cleanup(func() { testMode.Store(false) })
+
+	t.Run("returns once all the workers finished", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			g := NewGroup(context.Background())
+			g.Go(func(ctx context.Context) error { return nil })
+			g.Go(func(ctx context.Context) error { return errors.New("worker failed") })
+			if err := g.Wait(time.Second); err == nil || err.Error() != "worker failed" {
+				t.Errorf("expected the error of the worker but got: %v", err)
+			}
+		})
+	})
+	t.Run("workers are cancelled on signal with the cause", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			g := NewGroup(context.Background())
+			var sig any
+			g.Go(func(ctx context.Context) error {
+				<-ctx.Done()
+				sig, _ = SignalFromContext(ctx)
+				return ctx.Err()
+			})
+			go func() {
+				<-time.After(time.Second)
+				Trigger(syscall.SIGTERM)
+			}()
+			if err := g.Wait(time.Second); err != nil {
+				t.Errorf("expected no error but got: %s", err)
+			}
+			if sig != syscall.SIGTERM {
+				t.Errorf("got a different value than the wanted one. expected: %v; got: %v", syscall.SIGTERM, sig)
+			}
+		})
+	})
+	t.Run("workers not finishing within the timeout are reported", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			g := NewGroup(ctx)
+			for range 3 {
+				g.Go(func(ctx context.Context) error {
+					<-ctx.Done()
+					<-time.After(time.Minute) // draining takes longer than the timeout
+					return nil
+				})
+			}
+			g.Go(func(ctx context.Context) error {
+				<-ctx.Done()
+				return errors.New("failed to drain")
+			})
+			cancel()
+			err := g.Wait(5 * time.Second)
+			if err == nil {
+				t.Fatalf("expected an error but got nothing")
+			}
+			for _, want := range []string{"failed to drain", "3 workers did not finish within 5s"} {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("expected the error to contain %q but got: %s", want, err)
+				}
+			}
+			<-time.After(time.Minute) // let the workers finish before the bubble ends
+		})
+	})
+}
@@ -0,0 +1,135 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// hooks holds the hooks registered with [OnShutdown].
+var hooks = &registry{}
+
+// Hook is a cleanup function executed during the shutdown. The context is done once the shutdown timeout is exceeded.
+type Hook func(ctx context.Context) error
+
+type namedHook struct {
+	name string
+	f    Hook
+}
+
+type registry struct {
+	m     sync.Mutex
+	hooks []namedHook
+	// started is true once the hooks started to be executed.
+	started bool
+	// runCtx is given to the hooks registered while the hooks are executed. Once the execution finished, the
+	// hooks registered get their own context bounded by the timeout.
+	runCtx  context.Context
+	timeout time.Duration
+}
+
+// OnShutdown registers a hook to be executed by [WaitAndRun], allowing the libraries to register their cleanup
+// (ie: flushing logs, closing pools) without the main function knowing about them.
+// The hooks are executed in the reverse order of their registration. A hook registered after the execution
+// started is executed right away, blocking the caller.
+func OnShutdown(name string, f func(ctx context.Context) error) {
+	hooks.add(name, f)
+}
+
+// WaitAndRun blocks until one of the [defaultSigs] is received or the given context is done, and then executes
+// the hooks registered with [OnShutdown] in the reverse order of their registration.
+// All the hooks are bounded by the given timeout: each hook gets the remaining time and, once this is exceeded,
+// the hooks not executed yet are skipped. The errors and the panics of the hooks are logged, together with their
+// duration, and returned joined.
+func WaitAndRun(ctx context.Context, timeout time.Duration) error {
+	ch, stop := chanWithStop(siteWaitAndRun)
+	defer stop()
+	select {
+	case sig := <-ch:
+		slog.With("signal", sig.String()).Info("shutdown signal received, running the shutdown hooks")
+	case <-ctx.Done():
+		slog.With("cause", context.Cause(ctx)).Info("context done, running the shutdown hooks")
+	}
+	return hooks.run(ctx, timeout)
+}
+
+func (r *registry) add(name string, f Hook) {
+	r.m.Lock()
+	started, runCtx, timeout := r.started, r.runCtx, r.timeout
+	if !started {
+		r.hooks = append(r.hooks, namedHook{name: name, f: f})
+	}
+	r.m.Unlock()
+	if !started {
+		return
+	}
+	if runCtx == nil {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+	}
+	_ = runHook(runCtx, namedHook{name: name, f: f})
+}
+
+func (r *registry) run(ctx context.Context, timeout time.Duration) error {
+	// the given context might be the one that triggered the shutdown, so its cancellation is not inherited
+	runCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+	defer cancel()
+
+	r.m.Lock()
+	r.started, r.runCtx, r.timeout = true, runCtx, timeout
+	toRun := r.hooks
+	r.hooks = nil
+	r.m.Unlock()
+	defer func() {
+		r.m.Lock()
+		r.runCtx = nil
+		r.m.Unlock()
+	}()
+
+	var errs []error
+	for i := len(toRun) - 1; i >= 0; i-- {
+		h := toRun[i]
+		if runCtx.Err() != nil {
+			slog.With("hook", h.name).Warn("shutdown hook skipped since the shutdown timed out")
+			errs = append(errs, fmt.Errorf("%s: skipped: %w", h.name, runCtx.Err()))
+			continue
+		}
+		if err := runHook(runCtx, h); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// runHook executes the hook, returning early when the context is done before the hook finishes.
+func runHook(ctx context.Context, h namedHook) error {
+	start := time.Now()
+	errCh := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				slog.With("hook", h.name).With("stack", string(debug.Stack())).Error("shutdown hook panicked")
+				errCh <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		errCh <- h.f(ctx)
+	}()
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = fmt.Errorf("did not finish in time: %w", ctx.Err())
+	}
+	l := slog.With("hook", h.name).With("duration", time.Since(start))
+	if err != nil {
+		l.With("error", err).Warn("shutdown hook failed")
+		return fmt.Errorf("%s: %w", h.name, err)
+	}
+	l.Debug("shutdown hook finished")
+	return nil
+}
@@ -0,0 +1,102 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHooks(t *testing.T) {
+	t.Run("hooks run in the reverse order of their registration", func(t *testing.T) {
+		r := &registry{}
+		var order []string
+		for _, name := range []string{"first", "second", "third"} {
+			r.add(name, func(ctx context.Context) error {
+				order = append(order, name)
+				return nil
+			})
+		}
+		if err := r.run(context.Background(), time.Second); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		if want := []string{"third", "second", "first"}; !slices.Equal(order, want) {
+			t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, order)
+		}
+	})
+	t.Run("errors and panics are reported", func(t *testing.T) {
+		r := &registry{}
+		var ranAfterPanic bool
+		r.add("after panic", func(ctx context.Context) error { ranAfterPanic = true; return nil })
+		r.add("panicking", func(ctx context.Context) error { panic("boom") })
+		r.add("failing", func(ctx context.Context) error { return errors.New("failed to close") })
+
+		err := r.run(context.Background(), time.Second)
+		if err == nil {
+			t.Fatalf("expected an error but got nothing")
+		}
+		for _, want := range []string{"failing: failed to close", "panicking: panic: boom"} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("expected the error to contain %q but got: %s", want, err)
+			}
+		}
+		if !ranAfterPanic {
+			t.Errorf("expected the hooks to run after a panic")
+		}
+	})
+	t.Run("hooks are bounded by the remaining timeout", func(t *testing.T) {
+		r := &registry{}
+		var skippedRan bool
+		r.add("skipped", func(ctx context.Context) error { skippedRan = true; return nil })
+		r.add("slow", func(ctx context.Context) error {
+			<-time.After(time.Second)
+			return nil
+		})
+		start := time.Now()
+		err := r.run(context.Background(), 100*time.Millisecond)
+		if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+			t.Errorf("expected the run to stop at the timeout but it took %s", elapsed)
+		}
+		if err == nil || !strings.Contains(err.Error(), "slow: did not finish in time") || !strings.Contains(err.Error(), "skipped: skipped") {
+			t.Errorf("expected the timeout to be reported but got: %v", err)
+		}
+		if skippedRan {
+			t.Errorf("expected the hook to be skipped after the timeout")
+		}
+	})
+	t.Run("hooks registered after the execution started run immediately", func(t *testing.T) {
+		r := &registry{}
+		if err := r.run(context.Background(), time.Second); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		var ran bool
+		r.add("late", func(ctx context.Context) error { ran = true; return nil })
+		if !ran {
+			t.Errorf("expected the hook to run on registration")
+		}
+	})
+	t.Run("WaitAndRun runs the hooks once the context is done", func(t *testing.T) {
+		prev := hooks
+		hooks = &registry{}
+		t.Cleanup(func() { hooks = prev })
+
+		var ran bool
+		OnShutdown("hook", func(ctx context.Context) error {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			ran = true
+			return nil
+		})
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if err := WaitAndRun(ctx, time.Second); err != nil {
+			t.Fatalf("expected no error but got: %s", err)
+		}
+		if !ran {
+			t.Errorf("expected the hook to run with a context that is not done")
+		}
+	})
+}
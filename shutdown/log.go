@@ -0,0 +1,70 @@
+package shutdown
+
+import (
+	"log/slog"
+	"os"
+	"slices"
+	"sync/atomic"
+)
+
+// The functions of the package registering the channels, as reported by [LogSignals].
+const (
+	siteWait       = "Wait"
+	siteChan       = "Chan"
+	siteContext    = "Context"
+	siteGraceful   = "Graceful"
+	siteReload     = "Reload"
+	siteWaitAndRun = "WaitAndRun"
+)
+
+var (
+	// signalsLogged is true once [LogSignals] was called.
+	signalsLogged atomic.Bool
+	// signalsLogger is the logger configured by [LogSignals]. Nil when [slog.Default] is used.
+	signalsLogger atomic.Pointer[slog.Logger]
+)
+
+// LogSignals enables logging an info record for each signal seen by the channels and the contexts of this package
+// (ie: "received SIGTERM"), together with the functions that registered them (Wait, Chan, Context, etc.). This gives
+// a hint about why a process stopped. When the logger is nil, [slog.Default] is used.
+// The records of the signals received by the process are written from a separate goroutine, so the delivery of the
+// signals is not affected. The ones of the signals sent with [Trigger] are written before it returns.
+// Calling this again replaces the logger.
+func LogSignals(logger *slog.Logger) {
+	signalsLogger.Store(logger)
+	signalsLogged.Store(true)
+}
+
+// SignalsLogged reports whether the logging of the signals was enabled with [LogSignals].
+func SignalsLogged() bool {
+	return signalsLogged.Load()
+}
+
+// logSignal writes the record for the given signal when [LogSignals] was called.
+func logSignal(sig os.Signal) {
+	if !signalsLogged.Load() {
+		return
+	}
+	l := signalsLogger.Load()
+	if l == nil {
+		l = slog.Default()
+	}
+	l.
+		With("signal", signalName(sig)).
+		With("registered_by", subscribers.sites(sig)).
+		Info("received " + signalName(sig))
+}
+
+// sites returns the functions that registered the channels listening for the given signal.
+func (s *subscriptions) sites(sig os.Signal) []string {
+	s.m.Lock()
+	defer s.m.Unlock()
+	var sites []string
+	for _, sub := range s.chans {
+		if slices.Contains(sub.sigs, sig) && !slices.Contains(sites, sub.site) {
+			sites = append(sites, sub.site)
+		}
+	}
+	slices.Sort(sites)
+	return sites
+}
@@ -0,0 +1,33 @@
+package shutdown
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestLogSignals(t *testing.T) {
+	EnableTestMode()
+	t.Cleanup(func() {
+		// the other tests rely on the real signals and on the signals not being logged
+		testMode.Store(false)
+		signalsLogged.Store(false)
+		signalsLogger.Store(nil)
+	})
+
+	var b bytes.Buffer
+	LogSignals(slog.New(slog.NewTextHandler(&b, nil)))
+	_, stopChan := ChanWithStop(syscall.SIGTERM)
+	defer stopChan()
+	_, cancel := Context(t.Context())
+	defer cancel()
+	_, stopReload := Reload()
+	defer stopReload()
+
+	Trigger(syscall.SIGTERM)
+	if want := `msg="received SIGTERM" signal=SIGTERM registered_by="[Chan Context]"`; !strings.Contains(b.String(), want) {
+		t.Errorf("expected logs to contain %q but got:\n%s", want, b.String())
+	}
+}
@@ -0,0 +1,128 @@
+package shutdown
+
+import (
+	"encoding/json"
+	"maps"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"time"
+)
+
+// historySize is the number of signals kept by the history.
+const historySize = 32
+
+// history holds the last signals seen by the package, as reported by [Observer.Received].
+var history = &signalHistory{}
+
+// SignalEvent is a signal seen by the package, received by the process or sent with [Trigger].
+type SignalEvent struct {
+	Signal os.Signal
+	Time   time.Time
+}
+
+func (e SignalEvent) String() string {
+	return signalName(e.Signal) + " at " + e.Time.Format(time.RFC3339Nano)
+}
+
+func (e SignalEvent) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Signal string    `json:"signal"`
+		Time   time.Time `json:"time"`
+	}{Signal: signalName(e.Signal), Time: e.Time})
+}
+
+// Observer reports the signals seen by the package since its creation with [Observe].
+type Observer struct {
+	from uint64
+}
+
+// Observe returns an [Observer] reporting the signals seen by the package from now on, across all its channels and
+// contexts. Each signal is reported once, no matter how many channels it was relayed to.
+func Observe() *Observer {
+	history.m.Lock()
+	defer history.m.Unlock()
+	return &Observer{from: history.seq}
+}
+
+// Received returns the signals seen since the observer was created, oldest first. Only the last 32 signals seen
+// by the package are kept.
+// The signals received by the process are recorded asynchronously, so these might be missing for a brief moment
+// after being relayed to the channels. The ones sent with [Trigger] are recorded before it returns.
+func (o *Observer) Received() []SignalEvent {
+	return history.since(o.from)
+}
+
+type signalHistory struct {
+	m      sync.Mutex
+	events [historySize]SignalEvent
+	// seq is the number of the signals seen since the process started.
+	seq uint64
+
+	watchM sync.Mutex
+	ch     chan os.Signal
+	// watched holds the number of the channels of the package listening for each signal.
+	watched map[os.Signal]int
+}
+
+func (h *signalHistory) record(sig os.Signal) {
+	h.m.Lock()
+	defer h.m.Unlock()
+	h.events[h.seq%historySize] = SignalEvent{Signal: sig, Time: time.Now()}
+	h.seq++
+}
+
+func (h *signalHistory) since(from uint64) []SignalEvent {
+	h.m.Lock()
+	defer h.m.Unlock()
+	from = max(from, h.seq-min(h.seq, historySize))
+	res := make([]SignalEvent, 0, h.seq-from)
+	for i := from; i < h.seq; i++ {
+		res = append(res, h.events[i%historySize])
+	}
+	return res
+}
+
+// watch records the given signals when received by the process. A single channel is used for all the signals,
+// so each one is recorded once.
+func (h *signalHistory) watch(sigs ...os.Signal) {
+	h.watchM.Lock()
+	defer h.watchM.Unlock()
+	if h.ch == nil {
+		h.ch = make(chan os.Signal, historySize)
+		h.watched = map[os.Signal]int{}
+		go func() {
+			for sig := range h.ch {
+				h.record(sig)
+				logSignal(sig)
+			}
+		}()
+	}
+	for _, sig := range sigs {
+		h.watched[sig]++
+	}
+	signal.Notify(h.ch, sigs...)
+}
+
+// unwatch stops recording the given signals once no channel of the package listens for them anymore, so the
+// history does not change how the process reacts to them.
+func (h *signalHistory) unwatch(sigs ...os.Signal) {
+	h.watchM.Lock()
+	defer h.watchM.Unlock()
+	var changed bool
+	for _, sig := range sigs {
+		if h.watched[sig]--; h.watched[sig] <= 0 {
+			delete(h.watched, sig)
+			changed = true
+		}
+	}
+	if !changed {
+		return
+	}
+	// the signals cannot be removed one by one, so the channel is registered again with the remaining ones
+	signal.Stop(h.ch)
+	if len(h.watched) > 0 {
+		signal.Notify(h.ch, slices.Collect(maps.Keys(h.watched))...)
+	}
+}
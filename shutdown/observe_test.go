@@ -0,0 +1,49 @@
+package shutdown
+
+import (
+	"encoding/json"
+	"syscall"
+	"testing"
+)
+
+func TestObserve(t *testing.T) {
+	EnableTestMode()
+	// the other tests rely on the real signals
+	t.Cleanup(func() { testMode.Store(false) })
+
+	t.Run("reports the signals since the observer was created", func(t *testing.T) {
+		Trigger(syscall.SIGHUP)
+		o := Observe()
+		Trigger(syscall.SIGTERM)
+
+		got := o.Received()
+		if len(got) != 1 || got[0].Signal != syscall.SIGTERM {
+			t.Fatalf("expected exactly one SIGTERM but got: %v", got)
+		}
+		b, err := json.Marshal(got[0])
+		if err != nil {
+			t.Fatalf("failed to marshal the event: %s", err)
+		}
+		var decoded map[string]string
+		if err := json.Unmarshal(b, &decoded); err != nil {
+			t.Fatalf("failed to unmarshal the event %s: %s", b, err)
+		}
+		if decoded["signal"] != "SIGTERM" || decoded["time"] == "" {
+			t.Errorf("expected the event to be marshalled with the signal name and the time but got: %s", b)
+		}
+	})
+	t.Run("history is bounded", func(t *testing.T) {
+		o := Observe()
+		for range historySize + 8 {
+			Trigger(syscall.SIGHUP)
+		}
+		Trigger(syscall.SIGTERM)
+		got := o.Received()
+		if len(got) != historySize {
+			t.Fatalf("got a different value than the wanted one. expected: %d; got: %d", historySize, len(got))
+		}
+		if last := got[len(got)-1]; last.Signal != syscall.SIGTERM {
+			t.Errorf("expected the newest signal to be last but got: %v", last)
+		}
+	})
+}
@@ -0,0 +1,29 @@
+package shutdown
+
+import (
+	"os"
+	"syscall"
+)
+
+// Reload returns a channel receiving an item on each syscall.SIGHUP, the conventional signal for reloading the
+// configuration. The signals can be overwritten. Unlike [Context], nothing is cancelled, so this can be used next
+// to the termination signals handled by [Context] or [Wait].
+// When the items are not consumed fast enough, the signals received in the meantime are coalesced into a single one.
+// The returned function stops the relaying and closes the channel. Calling it multiple times is safe.
+func Reload(overwriteSignals ...os.Signal) (<-chan struct{}, func()) {
+	if len(overwriteSignals) == 0 {
+		overwriteSignals = []os.Signal{syscall.SIGHUP}
+	}
+	ch, stop := chanWithStop(siteReload, overwriteSignals...)
+	reloadCh := make(chan struct{}, 1)
+	go func() {
+		defer close(reloadCh)
+		for range ch {
+			select {
+			case reloadCh <- struct{}{}:
+			default:
+			}
+		}
+	}()
+	return reloadCh, stop
+}
@@ -0,0 +1,59 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+)
+
+const defaultCleanupTimeout = 10 * time.Second
+
+type runConfig struct {
+	signals        []os.Signal
+	cleanupTimeout time.Duration
+}
+
+// Option configures [Run].
+type Option func(*runConfig)
+
+// WithSignals overwrites the signals stopping the run function. Default: [defaultSigs]
+func WithSignals(sigs ...os.Signal) Option {
+	return func(c *runConfig) {
+		c.signals = sigs
+	}
+}
+
+// WithCleanupTimeout configures the time the cleanup function has to finish. Default: 10s
+func WithCleanupTimeout(d time.Duration) Option {
+	return func(c *runConfig) {
+		c.cleanupTimeout = d
+	}
+}
+
+// Run is the entry point of the small services that do not need the full app lifecycle: it calls run with a
+// context that is cancelled once the process receives a signal, as described in [Context], and then calls cleanup
+// with a new context bounded by the cleanup timeout.
+// The cleanup is called also when run returns before any signal is received. A nil cleanup is skipped.
+// The returned error joins the errors of run and cleanup. The [context.Canceled] returned by run after a signal is
+// not considered an error.
+func Run(run func(ctx context.Context) error, cleanup func(ctx context.Context) error, opts ...Option) error {
+	c := runConfig{cleanupTimeout: defaultCleanupTimeout}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	ctx, cancel := Context(context.Background(), c.signals...)
+	defer cancel()
+	runErr := run(ctx)
+	if errors.Is(runErr, context.Canceled) && ctx.Err() != nil {
+		runErr = nil
+	}
+	if cleanup == nil {
+		return runErr
+	}
+
+	cleanupCtx, cleanupCancel := context.WithTimeout(context.Background(), c.cleanupTimeout)
+	defer cleanupCancel()
+	return errors.Join(runErr, cleanup(cleanupCtx))
+}
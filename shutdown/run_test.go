@@ -0,0 +1,70 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"syscall"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	EnableTestMode()
+	// the other tests rely on the real signals
+	t.Cleanup(func() { testMode.Store(false) })
+
+	t.Run("run is cancelled on signal and cleanup is called", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			var cleanupDeadline time.Duration
+			go func() {
+				<-time.After(time.Second)
+				Trigger(syscall.SIGHUP)
+			}()
+			err := Run(
+				func(ctx context.Context) error {
+					<-ctx.Done()
+					return ctx.Err()
+				},
+				func(ctx context.Context) error {
+					deadline, _ := ctx.Deadline()
+					cleanupDeadline = time.Until(deadline)
+					return ctx.Err()
+				},
+				WithSignals(syscall.SIGHUP),
+				WithCleanupTimeout(5*time.Second),
+			)
+			if err != nil {
+				t.Errorf("expected no error but got: %s", err)
+			}
+			if cleanupDeadline != 5*time.Second {
+				t.Errorf("got a different value than the wanted one. expected: %s; got: %s", 5*time.Second, cleanupDeadline)
+			}
+		})
+	})
+	t.Run("cleanup is called when run returns before any signal", func(t *testing.T) {
+		runErr, cleanupErr := errors.New("run failed"), errors.New("cleanup failed")
+		var cleanupCalled bool
+		err := Run(
+			func(ctx context.Context) error { return runErr },
+			func(ctx context.Context) error {
+				cleanupCalled = true
+				if deadline, _ := ctx.Deadline(); time.Until(deadline) > defaultCleanupTimeout {
+					t.Errorf("expected the cleanup to be bounded by the default timeout")
+				}
+				return cleanupErr
+			},
+		)
+		if !cleanupCalled {
+			t.Errorf("expected the cleanup to be called")
+		}
+		if !errors.Is(err, runErr) || !errors.Is(err, cleanupErr) {
+			t.Errorf("expected the errors to be joined but got: %v", err)
+		}
+	})
+	t.Run("nil cleanup", func(t *testing.T) {
+		if err := Run(func(ctx context.Context) error { return nil }, nil); err != nil {
+			t.Errorf("expected no error but got: %s", err)
+		}
+	})
+}
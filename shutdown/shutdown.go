@@ -2,41 +2,207 @@ package shutdown
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
-	"os/signal"
+	"sync"
 	"syscall"
+	"time"
 )
 
-var defaultSigs = []os.Signal{
-	syscall.SIGINT,
-	syscall.SIGTERM,
-}
-
 // Wait creates a new chan that will receive items once one of the [defaultSigs] is received.
 // [defaultSigs] can be overwritten.
 // Once one of the signals is sent to the process, it will be relayed to the channel.
 // This method blocks until one signal is received on the channel.
 func Wait(overwrite ...os.Signal) {
-	signalChan := Chan(overwrite...)
-	<-signalChan
+	WaitSignal(overwrite...)
+}
+
+// WaitSignal is the same as [Wait] but returns the signal that was received, allowing the caller to act
+// differently on each signal (ie: reload on syscall.SIGHUP, exit on syscall.SIGTERM).
+func WaitSignal(overwrite ...os.Signal) os.Signal {
+	signalChan, stop := chanWithStop(siteWait, overwrite...)
+	defer stop()
+	return <-signalChan
+}
+
+// WaitWithTimeout is the same as [Wait] but gives up after d, returning false when no signal was received.
+// Otherwise, it returns the signal received.
+func WaitWithTimeout(d time.Duration, sigs ...os.Signal) (os.Signal, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	sig, err := WaitContext(ctx, sigs...)
+	return sig, err == nil
+}
+
+// WaitContext is the same as [Wait] but returns ctx.Err() when the context is done before receiving a signal.
+// Otherwise, it returns the signal received.
+func WaitContext(ctx context.Context, sigs ...os.Signal) (os.Signal, error) {
+	signalChan, stop := chanWithStop(siteWait, sigs...)
+	defer stop()
+	select {
+	case sig := <-signalChan:
+		return sig, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
 }
 
 // Chan creates a new chan that will receive items once one of the [defaultSigs] is received.
 // [defaultSigs] can be overwritten.
 // Once one of the signals is sent to the process, it will be relayed to the channel allowing
 // the client to act on each signal received.
+// The channel stays registered for the lifetime of the process. When this is not desired, use [ChanWithStop].
+// The channel holds a single signal not received yet, the ones received in the meantime being dropped. When the
+// signals can come in bursts, use [ChanBuffered] or [ChanCoalesced].
 func Chan(overwriteSignals ...os.Signal) <-chan os.Signal {
 	signalChan := make(chan os.Signal, 1)
-	signal.Notify(signalChan, signals(overwriteSignals...)...)
+	notify(signalChan, siteChan, signals(overwriteSignals...)...)
 	return signalChan
 }
 
+// ChanBuffered is the same as [Chan] but the channel holds up to n signals not received yet, instead of 1.
+// The signals are relayed without blocking, like by [signal.Notify]: once the channel holds n signals, the ones that
+// follow are dropped until the channel is drained. A burst of up to n signals (ie: a syscall.SIGHUP immediately
+// followed by a syscall.SIGTERM) is therefore delivered completely, in the order in which the signals were relayed.
+// The OS does not queue the signals though: the same signal sent multiple times before the process handles it
+// might be relayed only once, regardless of n.
+// A n lower than 1 is treated as 1.
+func ChanBuffered(n int, overwriteSignals ...os.Signal) <-chan os.Signal {
+	signalChan := make(chan os.Signal, max(n, 1))
+	notify(signalChan, siteChan, signals(overwriteSignals...)...)
+	return signalChan
+}
+
+// ChanCoalesced is the same as [ChanBuffered] but a signal is dropped when it's the same as the last one not
+// received yet. This way, a burst of the same signal takes a single place of the n available, leaving room for the
+// distinct signals that follow (ie: multiple syscall.SIGHUP followed by a syscall.SIGTERM). A signal is not dropped
+// when the previous one of the same kind was already received from the channel.
+// A n lower than 1 is treated as 1.
+func ChanCoalesced(n int, overwriteSignals ...os.Signal) <-chan os.Signal {
+	n = max(n, 1)
+	in := make(chan os.Signal, n)
+	notify(in, siteChan, signals(overwriteSignals...)...)
+	out := make(chan os.Signal)
+	go coalesce(in, out, n)
+	return out
+}
+
+// coalesce relays the signals from in to out, keeping at most n signals not received yet and dropping the ones
+// that are the same as the last one kept.
+func coalesce(in <-chan os.Signal, out chan<- os.Signal, n int) {
+	var pending []os.Signal
+	for {
+		var (
+			sendCh chan<- os.Signal
+			next   os.Signal
+		)
+		if len(pending) > 0 {
+			sendCh, next = out, pending[0]
+		}
+		select {
+		case sig := <-in:
+			if len(pending) == n || (len(pending) > 0 && pending[len(pending)-1] == sig) {
+				continue
+			}
+			pending = append(pending, sig)
+		case sendCh <- next:
+			pending = pending[1:]
+		}
+	}
+}
+
+// ChanWithStop is the same as [Chan] but returns also a function that stops relaying the signals to the
+// channel and closes it, similar to the cancel function of [signal.NotifyContext].
+// Calling the stop function multiple times is safe.
+func ChanWithStop(overwriteSignals ...os.Signal) (<-chan os.Signal, func()) {
+	return chanWithStop(siteChan, overwriteSignals...)
+}
+
+// chanWithStop is the same as [ChanWithStop], recording the function of the package that registered the channel,
+// as reported by [LogSignals].
+func chanWithStop(site string, overwriteSignals ...os.Signal) (<-chan os.Signal, func()) {
+	signalChan := make(chan os.Signal, 1)
+	notify(signalChan, site, signals(overwriteSignals...)...)
+	var once sync.Once
+	return signalChan, func() {
+		once.Do(func() {
+			stopNotify(signalChan)
+			close(signalChan)
+		})
+	}
+}
+
 // Context returns a [context.Context] that will get cancelled once the process receives one of the signals
 // from [defaultSigs]. The signals used to cancel the context can be overwritten by another
 // list of [os.Signal] to match the user needs.
 // This returns a [context.CancelFunc] that the user is responsible of.
+//
+// When cancelled by a signal, the [context.Cause] of the context names it (ie: "shutdown: received signal SIGTERM")
+// and the signal can be read with [SignalFromContext]. When the parent context is cancelled, its cause is kept.
 func Context(ctx context.Context, overwriteSignals ...os.Signal) (context.Context, context.CancelFunc) {
-	return signal.NotifyContext(ctx, signals(overwriteSignals...)...)
+	ctx, cancel := context.WithCancelCause(ctx)
+	ch, stop := chanWithStop(siteContext, overwriteSignals...)
+	go func() {
+		defer stop()
+		select {
+		case sig, ok := <-ch:
+			if ok {
+				cancel(&signalError{sig: sig})
+			}
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, func() {
+		cancel(nil)
+		stop()
+	}
+}
+
+// SignalFromContext returns the signal that cancelled a context created by [Context].
+// This returns false when the context is not done or it was cancelled for another reason.
+func SignalFromContext(ctx context.Context) (os.Signal, bool) {
+	var se *signalError
+	if errors.As(context.Cause(ctx), &se) {
+		return se.sig, true
+	}
+	return nil, false
+}
+
+// signalError is the cause of the contexts cancelled by a signal.
+type signalError struct {
+	sig os.Signal
+	// grace is the grace period that followed the signal, as configured with [Graceful].
+	grace time.Duration
+	// interrupt is the signal that ended the grace period before it elapsed.
+	interrupt os.Signal
+}
+
+func (e *signalError) Error() string {
+	msg := "shutdown: received signal " + signalName(e.sig)
+	switch {
+	case e.interrupt != nil:
+		msg += fmt.Sprintf(", then %s during the grace period of %s", signalName(e.interrupt), e.grace)
+	case e.grace > 0:
+		msg += fmt.Sprintf(" and the grace period of %s elapsed", e.grace)
+	}
+	return msg
+}
+
+var signalNames = map[os.Signal]string{
+	syscall.SIGHUP:  "SIGHUP",
+	syscall.SIGINT:  "SIGINT",
+	syscall.SIGQUIT: "SIGQUIT",
+	syscall.SIGKILL: "SIGKILL",
+	syscall.SIGTERM: "SIGTERM",
+}
+
+// signalName returns the conventional name of the signal (ie: SIGTERM instead of "terminated").
+func signalName(sig os.Signal) string {
+	if name, ok := signalNames[sig]; ok {
+		return name
+	}
+	return sig.String()
 }
 
 func signals(overwrite ...os.Signal) []os.Signal {
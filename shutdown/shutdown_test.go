@@ -1,12 +1,16 @@
+//go:build !windows
+
 package shutdown
 
 import (
 	"bufio"
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"strings"
 	"syscall"
 	"testing"
 	"time"
@@ -18,6 +22,12 @@ const (
 	shutdownMethodWait    = "wait"
 	shutdownMethodChan    = "chan"
 	shutdownMethodContext = "context"
+	// shutdownMethodChanBuffered reads the signals only after both signals sent by the test were relayed.
+	shutdownMethodChanBuffered = "chan_buffered"
+	// shutdownMethodWaitTimeout reports in the executed method if the wait ended with a signal or with the timeout.
+	shutdownMethodWaitTimeout = "wait_timeout"
+
+	waitTimeout = 2 * time.Second
 )
 
 func TestMain(t *testing.M) {
@@ -27,15 +37,41 @@ func TestMain(t *testing.M) {
 		}
 		switch method {
 		case shutdownMethodWait:
-			Wait()
+			res.receivedSignal = signalName(WaitSignal())
 			res.executedMethod = method // writing it here to be sure that this is written only when the shutdown method is actually executed
 		case shutdownMethodChan:
-			<-Chan()
+			res.receivedSignal = signalName(<-Chan())
+			res.executedMethod = method // writing it here to be sure that this is written only when the shutdown method is actually executed
+		case shutdownMethodChanBuffered:
+			ch := ChanBuffered(2, syscall.SIGHUP, syscall.SIGTERM)
+			<-time.After(2 * time.Second)
+			var received []string
+			timeoutCh := time.After(waitTimeout)
+		read:
+			for len(received) < 2 {
+				select {
+				case sig := <-ch:
+					received = append(received, signalName(sig))
+				case <-timeoutCh:
+					break read
+				}
+			}
+			res.receivedSignal = strings.Join(received, ",")
 			res.executedMethod = method // writing it here to be sure that this is written only when the shutdown method is actually executed
+		case shutdownMethodWaitTimeout:
+			if sig, ok := WaitWithTimeout(waitTimeout); ok {
+				res.receivedSignal = signalName(sig)
+				res.executedMethod = method + ":signal"
+			} else {
+				res.executedMethod = method + ":timeout"
+			}
 		case shutdownMethodContext:
 			ctx, cancel := Context(context.Background())
 			defer cancel()
 			<-ctx.Done()
+			if sig, ok := SignalFromContext(ctx); ok {
+				res.receivedSignal = signalName(sig)
+			}
 			res.executedMethod = method // writing it here to be sure that this is written only when the shutdown method is actually executed
 		default:
 			fmt.Println("invalid shutdown method provided")
@@ -117,7 +153,7 @@ func TestShutdownMethods(t *testing.T) {
 	}
 	for name, tt := range cases {
 		t.Run(name, func(t *testing.T) {
-			stdout, stderr, elapsed, err := run(os.Args[0], shutdownMethodWait, tt.delayBeforeSendingSignal, tt.signalToSend)
+			stdout, stderr, elapsed, err := run(os.Args[0], tt.shutdownMethod, tt.delayBeforeSendingSignal, tt.signalToSend)
 			if err != nil {
 				t.Fatalf("unexpected failure: %s\nstdout:\n%s\nstderr:\n%s", err, stdout, stderr)
 			}
@@ -125,9 +161,12 @@ func TestShutdownMethods(t *testing.T) {
 			if err := res.decode([]byte(stdout)); err != nil {
 				t.Fatalf("failed to decode the results from stdout: %s\nstdout:\n%s", err, stdout)
 			}
-			if wantMethod, gotMethod := shutdownMethodWait, res.executedMethod; wantMethod != gotMethod {
+			if wantMethod, gotMethod := tt.shutdownMethod, res.executedMethod; wantMethod != gotMethod {
 				t.Fatalf("expected to have method %q but got %q", wantMethod, gotMethod)
 			}
+			if wantSignal, gotSignal := signalName(tt.signalToSend), res.receivedSignal; wantSignal != gotSignal {
+				t.Fatalf("expected to have received signal %q but got %q", wantSignal, gotSignal)
+			}
 			if elapsed < tt.delayBeforeSendingSignal {
 				t.Fatalf("time took to run the shutdown method is less than expected. expected: %s, got: %s", tt.delayBeforeSendingSignal, elapsed)
 			}
@@ -137,7 +176,62 @@ func TestShutdownMethods(t *testing.T) {
 	}
 }
 
-func run(cmdPath string, method string, signalAfter time.Duration, signal os.Signal) (string, string, time.Duration, error) {
+func TestWaitWithTimeout(t *testing.T) {
+	cases := map[string]struct {
+		delayBeforeSendingSignal time.Duration
+		wantMethod               string
+	}{
+		"signal received before the timeout": {
+			delayBeforeSendingSignal: 500 * time.Millisecond,
+			wantMethod:               shutdownMethodWaitTimeout + ":signal",
+		},
+		"no signal received": {
+			wantMethod: shutdownMethodWaitTimeout + ":timeout",
+		},
+	}
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			stdout, stderr, elapsed, err := run(os.Args[0], shutdownMethodWaitTimeout, tt.delayBeforeSendingSignal, syscall.SIGTERM)
+			if err != nil {
+				t.Fatalf("unexpected failure: %s\nstdout:\n%s\nstderr:\n%s", err, stdout, stderr)
+			}
+			res := &result{}
+			if err := res.decode([]byte(stdout)); err != nil {
+				t.Fatalf("failed to decode the results from stdout: %s\nstdout:\n%s", err, stdout)
+			}
+			if gotMethod := res.executedMethod; tt.wantMethod != gotMethod {
+				t.Fatalf("expected to have method %q but got %q", tt.wantMethod, gotMethod)
+			}
+			if inProcessElapsed := res.stoppedAt.Sub(res.startedAt); tt.delayBeforeSendingSignal == 0 && inProcessElapsed < waitTimeout {
+				t.Fatalf("expected the wait to last for the timeout. expected: %s, got: %s", waitTimeout, inProcessElapsed)
+			}
+			t.Logf("executing and stopping the process took %s", elapsed)
+		})
+	}
+}
+
+func TestWaitContext(t *testing.T) {
+	t.Run("context done first", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		sig, err := WaitContext(ctx, syscall.SIGUSR2)
+		if sig != nil || !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected the context error but got %v, %v", sig, err)
+		}
+	})
+	t.Run("signal received", func(t *testing.T) {
+		go func() {
+			<-time.After(100 * time.Millisecond)
+			_ = syscall.Kill(os.Getpid(), syscall.SIGUSR2)
+		}()
+		sig, err := WaitContext(context.Background(), syscall.SIGUSR2)
+		if err != nil || sig != syscall.SIGUSR2 {
+			t.Errorf("expected the signal to be received but got %v, %v", sig, err)
+		}
+	})
+}
+
+func run(cmdPath string, method string, signalAfter time.Duration, signals ...os.Signal) (string, string, time.Duration, error) {
 	stdout := &bytes.Buffer{}
 	stderr := &bytes.Buffer{}
 	cmd := exec.Command(cmdPath)
@@ -159,8 +253,10 @@ func run(cmdPath string, method string, signalAfter time.Duration, signal os.Sig
 	if signalAfter != 0 {
 		select {
 		case <-time.After(signalAfter):
-			if err := cmd.Process.Signal(signal); err != nil {
-				return "", "", -1, err
+			for _, signal := range signals {
+				if err := cmd.Process.Signal(signal); err != nil {
+					return "", "", -1, err
+				}
 			}
 		case <-cmdDoneCh:
 		}
@@ -174,6 +270,7 @@ type result struct {
 	startedAt      time.Time
 	stoppedAt      time.Time
 	executedMethod string
+	receivedSignal string
 }
 
 func (r *result) encode() string {
@@ -183,6 +280,8 @@ func (r *result) encode() string {
 	b.WriteString(r.startedAt.Format(time.RFC3339Nano))
 	b.WriteString("\n")
 	b.WriteString(r.stoppedAt.Format(time.RFC3339Nano))
+	b.WriteString("\n")
+	b.WriteString(r.receivedSignal)
 	return b.String()
 }
 
@@ -207,12 +306,180 @@ func (r *result) decode(in []byte) error {
 				return fmt.Errorf("could not decode stop time from the result: %w", err)
 			}
 			r.stoppedAt = tm
+		case 4:
+			r.receivedSignal = t
 		default:
-			return fmt.Errorf("result can decode only 3 lines of data")
+			return fmt.Errorf("result can decode only 4 lines of data")
 		}
 	}
-	if idx != 3 {
-		return fmt.Errorf("expected to read 3 lines of data but got only %d", idx)
+	if idx < 3 {
+		return fmt.Errorf("expected to read at least 3 lines of data but got only %d", idx)
 	}
 	return nil
 }
+
+func TestContextCause(t *testing.T) {
+	t.Run("cause names the received signal", func(t *testing.T) {
+		ctx, cancel := Context(context.Background(), syscall.SIGUSR2)
+		defer cancel()
+		if err := syscall.Kill(os.Getpid(), syscall.SIGUSR2); err != nil {
+			t.Fatalf("failed to send the signal: %s", err)
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected the context to be cancelled by the signal")
+		}
+		if got, want := context.Cause(ctx).Error(), "shutdown: received signal user defined signal 2"; got != want {
+			t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+		}
+		sig, ok := SignalFromContext(ctx)
+		if !ok || sig != syscall.SIGUSR2 {
+			t.Errorf("expected the signal to be read from the context but got %v, %t", sig, ok)
+		}
+	})
+	t.Run("known signals are named conventionally", func(t *testing.T) {
+		err := &signalError{sig: syscall.SIGTERM}
+		if got, want := err.Error(), "shutdown: received signal SIGTERM"; got != want {
+			t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+		}
+	})
+	t.Run("cause of the parent is kept", func(t *testing.T) {
+		parent, cancelParent := context.WithCancelCause(context.Background())
+		ctx, cancel := Context(parent)
+		defer cancel()
+		parentCause := errors.New("parent stopped")
+		cancelParent(parentCause)
+		<-ctx.Done()
+		if got := context.Cause(ctx); got != parentCause {
+			t.Errorf("got a different value than the wanted one. expected: %q; got: %q", parentCause, got)
+		}
+		if _, ok := SignalFromContext(ctx); ok {
+			t.Errorf("expected no signal for a context cancelled by its parent")
+		}
+	})
+	t.Run("cancel func", func(t *testing.T) {
+		ctx, cancel := Context(context.Background())
+		cancel()
+		if got := context.Cause(ctx); got != context.Canceled {
+			t.Errorf("got a different value than the wanted one. expected: %q; got: %q", context.Canceled, got)
+		}
+	})
+}
+
+func TestChanWithStop(t *testing.T) {
+	ch, stop := ChanWithStop(syscall.SIGUSR2)
+	// keeps the signal handled for the process while the first channel is stopped
+	other, stopOther := ChanWithStop(syscall.SIGUSR2)
+	defer stopOther()
+
+	stop()
+	stop() // calling it twice is safe
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR2); err != nil {
+		t.Fatalf("failed to send the signal: %s", err)
+	}
+	select {
+	case <-other:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the signal to be relayed to the channel that was not stopped")
+	}
+	if sig, ok := <-ch; ok {
+		t.Errorf("expected the stopped channel to be closed but received %v", sig)
+	}
+}
+
+func TestChanBuffered(t *testing.T) {
+	stdout, stderr, _, err := run(os.Args[0], shutdownMethodChanBuffered, time.Second, syscall.SIGHUP, syscall.SIGTERM)
+	if err != nil {
+		t.Fatalf("unexpected failure: %s\nstdout:\n%s\nstderr:\n%s", err, stdout, stderr)
+	}
+	res := &result{}
+	if err := res.decode([]byte(stdout)); err != nil {
+		t.Fatalf("failed to decode the results from stdout: %s\nstdout:\n%s", err, stdout)
+	}
+	if got, want := res.receivedSignal, "SIGHUP,SIGTERM"; got != want {
+		t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+	}
+}
+
+func TestChanCoalesced(t *testing.T) {
+	ch := ChanCoalesced(2, syscall.SIGUSR1, syscall.SIGUSR2)
+	for _, sig := range []syscall.Signal{syscall.SIGUSR2, syscall.SIGUSR2, syscall.SIGUSR2, syscall.SIGUSR1} {
+		if err := syscall.Kill(os.Getpid(), sig); err != nil {
+			t.Fatalf("failed to send the signal: %s", err)
+		}
+		<-time.After(50 * time.Millisecond) // let the signal be relayed before sending the next one
+	}
+	for _, want := range []os.Signal{syscall.SIGUSR2, syscall.SIGUSR1} {
+		select {
+		case got := <-ch:
+			if got != want {
+				t.Errorf("got a different value than the wanted one. expected: %q; got: %q", want, got)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected %s to be received", want)
+		}
+	}
+	select {
+	case sig := <-ch:
+		t.Errorf("expected the duplicated signals to be coalesced but received %v", sig)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestObserveReceivedSignals(t *testing.T) {
+	<-time.After(100 * time.Millisecond) // let the signals sent by the previous tests be recorded
+	o := Observe()
+	ch1, stop1 := ChanWithStop(syscall.SIGUSR2)
+	defer stop1()
+	ch2, stop2 := ChanWithStop(syscall.SIGUSR2)
+	defer stop2()
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR2); err != nil {
+		t.Fatalf("failed to send the signal: %s", err)
+	}
+	<-ch1
+	<-ch2
+	deadline := time.After(5 * time.Second)
+	for len(o.Received()) == 0 {
+		select {
+		case <-deadline:
+			t.Fatalf("expected the signal to be recorded")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	<-time.After(100 * time.Millisecond) // a duplicate would be recorded by now
+	if got := o.Received(); len(got) != 1 || got[0].Signal != syscall.SIGUSR2 {
+		t.Errorf("expected the signal to be recorded once but got: %v", got)
+	}
+}
+
+func TestReload(t *testing.T) {
+	ctx, cancel := Context(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+	reloadCh, stop := Reload()
+
+	for range 2 {
+		if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+			t.Fatalf("failed to send SIGHUP: %s", err)
+		}
+		select {
+		case <-reloadCh:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected a reload on each SIGHUP")
+		}
+	}
+	if ctx.Err() != nil {
+		t.Errorf("expected SIGHUP to not cancel the termination context but got: %s", context.Cause(ctx))
+	}
+
+	stop()
+	stop() // calling it twice is safe
+	select {
+	case _, ok := <-reloadCh:
+		if ok {
+			t.Errorf("expected the reload channel to be closed")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the reload channel to be closed")
+	}
+}
@@ -0,0 +1,19 @@
+//go:build !windows
+
+package shutdown
+
+import (
+	"os"
+	"syscall"
+)
+
+var defaultSigs = []os.Signal{
+	syscall.SIGINT,
+	syscall.SIGTERM,
+}
+
+// TerminationSignals returns the signals asking the process to terminate on the current platform.
+// These are syscall.SIGINT, syscall.SIGTERM and syscall.SIGQUIT.
+func TerminationSignals() []os.Signal {
+	return []os.Signal{syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT}
+}
@@ -0,0 +1,21 @@
+//go:build windows
+
+package shutdown
+
+import (
+	"os"
+	"syscall"
+)
+
+// On Windows, os.Interrupt is received on Ctrl+C and Ctrl+Break, and syscall.SIGTERM on the close,
+// logoff and shutdown events of the console.
+var defaultSigs = []os.Signal{
+	os.Interrupt,
+	syscall.SIGTERM,
+}
+
+// TerminationSignals returns the signals asking the process to terminate on the current platform.
+// These are os.Interrupt and syscall.SIGTERM, since syscall.SIGQUIT is never delivered on Windows.
+func TerminationSignals() []os.Signal {
+	return []os.Signal{os.Interrupt, syscall.SIGTERM}
+}
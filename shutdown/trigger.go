@@ -0,0 +1,88 @@
+package shutdown
+
+import (
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// testMode allows [Trigger] to be used. See [EnableTestMode].
+var testMode atomic.Bool
+
+// subscribers holds the channels created by this package, with the signals relayed to each one, for [Trigger].
+var subscribers = &subscriptions{chans: map[chan<- os.Signal]subscription{}}
+
+type subscriptions struct {
+	m     sync.Mutex
+	chans map[chan<- os.Signal]subscription
+}
+
+type subscription struct {
+	sigs []os.Signal
+	// site is the function of the package that registered the channel, as reported by [LogSignals].
+	site string
+	// notified is true when the channel is registered with [signal.Notify] to receive the real signals.
+	notified bool
+}
+
+// EnableTestMode allows [Trigger] to be called. This is meant to be called only from tests and cannot be undone.
+// The channels and the contexts created by this package after enabling the test mode are not registered to receive
+// the signals of the OS anymore, so these receive only the signals sent with [Trigger]. This keeps the delivery
+// deterministic and allows using these inside the testing/synctest bubbles, where [signal.Notify] cannot be called.
+func EnableTestMode() {
+	testMode.Store(true)
+}
+
+// Trigger delivers the given signal, without involving the OS, to all the channels and contexts created by this
+// package that listen for it, as if the process received it. This allows testing the shutdown paths in-process and
+// deterministically (ie: with testing/synctest).
+// Like for the real signals, the delivery does not block: a channel that already has a signal pending does not
+// receive it again. The signals are delivered synchronously, before Trigger returns. The channels and contexts
+// created before [EnableTestMode] also receive the real signals, relayed asynchronously by [signal.Notify], and no
+// order is guaranteed between the two.
+// This panics when [EnableTestMode] was not called, to keep it from being used outside the tests.
+func Trigger(sig os.Signal) {
+	if !testMode.Load() {
+		panic("shutdown.Trigger can be used only after shutdown.EnableTestMode")
+	}
+	history.record(sig)
+	logSignal(sig)
+	subscribers.m.Lock()
+	defer subscribers.m.Unlock()
+	for ch, sub := range subscribers.chans {
+		if !slices.Contains(sub.sigs, sig) {
+			continue
+		}
+		select {
+		case ch <- sig:
+		default:
+		}
+	}
+}
+
+// notify relays the signals to the channel, both the ones received by the process and the ones sent with [Trigger].
+// In test mode, only the ones sent with [Trigger] are relayed.
+func notify(ch chan<- os.Signal, site string, sigs ...os.Signal) {
+	sub := subscription{sigs: sigs, site: site, notified: !testMode.Load()}
+	if sub.notified {
+		history.watch(sigs...)
+		signal.Notify(ch, sigs...)
+	}
+	subscribers.m.Lock()
+	defer subscribers.m.Unlock()
+	subscribers.chans[ch] = sub
+}
+
+// stopNotify stops relaying any signal to the channel. Once this returns, no signal is sent on the channel anymore.
+func stopNotify(ch chan<- os.Signal) {
+	subscribers.m.Lock()
+	sub := subscribers.chans[ch]
+	delete(subscribers.chans, ch)
+	subscribers.m.Unlock()
+	if sub.notified {
+		signal.Stop(ch)
+		history.unwatch(sub.sigs...)
+	}
+}
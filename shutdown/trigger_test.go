@@ -0,0 +1,65 @@
+package shutdown
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"testing/synctest"
+)
+
+func TestTrigger(t *testing.T) {
+	t.Run("panics without the test mode", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("expected Trigger to panic without the test mode")
+			}
+		}()
+		Trigger(syscall.SIGTERM)
+	})
+	EnableTestMode()
+	// the other tests rely on the real signals
+	t.Cleanup(func() { testMode.Store(false) })
+	t.Run("cancels the contexts", func(t *testing.T) {
+		synctest.Test(t, func(t *testing.T) {
+			ctx, cancel := Context(context.Background())
+			defer cancel()
+			Trigger(syscall.SIGTERM)
+			synctest.Wait()
+			if ctx.Err() == nil {
+				t.Fatalf("expected the context to be cancelled")
+			}
+			if sig, ok := SignalFromContext(ctx); !ok || sig != syscall.SIGTERM {
+				t.Errorf("expected the context to be cancelled by SIGTERM but got %v, %t", sig, ok)
+			}
+		})
+	})
+	t.Run("delivers only to the channels listening for the signal", func(t *testing.T) {
+		termCh, stopTerm := ChanWithStop(syscall.SIGTERM)
+		defer stopTerm()
+		hupCh, stopHup := ChanWithStop(syscall.SIGHUP)
+		defer stopHup()
+
+		Trigger(syscall.SIGHUP)
+		select {
+		case sig := <-hupCh:
+			if sig != syscall.SIGHUP {
+				t.Errorf("got a different value than the wanted one. expected: %v; got: %v", syscall.SIGHUP, sig)
+			}
+		default:
+			t.Errorf("expected the signal to be delivered")
+		}
+		select {
+		case sig := <-termCh:
+			t.Errorf("expected no signal but got %v", sig)
+		default:
+		}
+	})
+	t.Run("stopped channels receive nothing", func(t *testing.T) {
+		ch, stop := ChanWithStop(syscall.SIGTERM)
+		stop()
+		Trigger(syscall.SIGTERM) // would panic when sending on the closed channel
+		if _, ok := <-ch; ok {
+			t.Errorf("expected the channel to be closed")
+		}
+	})
+}